@@ -8,6 +8,7 @@ import (
 	"syscall"
 	"time"
 
+	"cloudcostguard/backend/estimator"
 	"cloudcostguard/backend/internal/api"
 	"cloudcostguard/backend/internal/cache"
 	"cloudcostguard/backend/internal/config"
@@ -54,7 +55,11 @@ func runServer() {
 	// Initialize services
 	pricingRepo := postgres.NewPricingRepository(db, logger)
 	pricingCache := cache.NewPricingCache(pricingRepo, logger, cfg.Cache.RefreshInterval)
-	estimatorSvc := service.NewEstimator(pricingCache, logger, db)
+	discounts, err := service.LoadDiscountPolicies(cfg.Pricing.DiscountPolicyFile)
+	if err != nil {
+		logger.Fatal("Failed to load discount policies", zap.Error(err))
+	}
+	estimatorSvc := service.NewEstimator(pricingCache, logger, db, estimator.Options{MaxPricingAge: cfg.Pricing.MaxAge}, discounts)
 	pricingStorer := pricing.NewPostgresPricingDataStorer(db)
 	pricingSvc := pricing.NewService(logger, pricingStorer)
 	pricingSvc.Start(context.Background())
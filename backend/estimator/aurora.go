@@ -0,0 +1,336 @@
+package estimator
+
+import (
+	"fmt"
+	"strings"
+
+	"cloudcostguard/backend/pricing"
+)
+
+func init() {
+	MustRegister(NewCalculator("aws_rds_cluster", []string{"AmazonRDS"}, nil, costForRDSCluster))
+	MustRegister(NewCalculator("aws_rds_cluster_instance", []string{"AmazonRDS"}, []string{"instance_class"}, costForRDSClusterInstance))
+	// Cost is calculated as part of the clusters that belong to the global database, not standalone.
+	MustRegister(NewCalculator("aws_rds_global_cluster", nil, nil, zeroCost))
+}
+
+// Usage types of Aurora clusters, without a region prefix.
+const (
+	auroraStorage                 = "Aurora:StorageUsage"
+	auroraIOOptimizedStorage      = "Aurora:IO-OptimizedStorageUsage"
+	auroraIORequests              = "Aurora:StorageIOUsage"
+	auroraServerlessV2            = "Aurora:ServerlessV2Usage"
+	auroraIOOptimizedServerlessV2 = "Aurora:ServerlessV2IOOptimizedUsage"
+	auroraReplicatedWrites        = "Aurora:ReplicatedWriteIO"
+)
+
+const (
+	// auroraIOOptimizedStorageType is the storage_type of Aurora clusters with the I/O-Optimized configuration.
+	auroraIOOptimizedStorageType = "aurora-iopt1"
+	// auroraServerlessInstanceClass is the instance class of Aurora Serverless v2 instances.
+	auroraServerlessInstanceClass = "db.serverless"
+	// rdsMultiAZClusterDeploymentOption is the deployment option of Multi-AZ DB clusters with two readable standbys.
+	rdsMultiAZClusterDeploymentOption = "Multi-AZ (readable standbys)"
+)
+
+// costForRDSCluster calculates the cost of an AWS RDS cluster.
+// Aurora clusters are charged for their storage and, in the standard configuration, their I/O requests, both from
+// the usage estimates. Their instances are aws_rds_cluster_instance resources priced separately. The primary cluster
+// of an Aurora global database, the source_db_cluster_identifier of its aws_rds_global_cluster, is also charged for
+// the writes it replicates to each secondary cluster in the plan.
+// Multi-AZ DB clusters of the MySQL and PostgreSQL engines are priced for their db_cluster_instance_class and
+// allocated storage like RDS instances.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its usage estimates may include the storage and I/O of
+//        the cluster, and its plan is used to find the secondary clusters of a global database.
+//   attributes: The attributes of the RDS cluster resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the RDS cluster.
+//   An error if the engine or engine mode is not supported or the pricing data cannot be found.
+func costForRDSCluster(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	engine, err := resolveAuroraEngine(attributes)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(engine.DatabaseEngine, "Aurora") {
+		return costForMultiAZCluster(ctx, attributes, engine)
+	}
+	if engineMode, _ := attributes["engine_mode"].(string); engineMode != "" && engineMode != "provisioned" {
+		return nil, fmt.Errorf("%w: unsupported Aurora engine mode %q", ErrMissingAttribute, engineMode)
+	}
+
+	usage := ctx.Usage
+	if usage == nil {
+		usage = &UsageEstimates{}
+	}
+	location := resourceLocation(ctx, attributes)
+	var components []CostComponent
+	add := func(name, unit, usageType string, quantity float64) error {
+		added, err := auroraComponents(ctx, location, engine, name, unit, usageType, quantity)
+		components = append(components, added...)
+		return err
+	}
+
+	// I/O-Optimized clusters pay a higher storage price and nothing for I/O requests.
+	if storageType, _ := attributes["storage_type"].(string); storageType == auroraIOOptimizedStorageType {
+		if err := add("Storage (I/O-Optimized)", "GB-month", auroraIOOptimizedStorage, float64(usage.Aurora.StorageGB)); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := add("Storage", "GB-month", auroraStorage, float64(usage.Aurora.StorageGB)); err != nil {
+			return nil, err
+		}
+		if err := add("I/O requests", "request", auroraIORequests, float64(usage.Aurora.MonthlyIORequests)); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, secondary := range auroraSecondaryClusters(ctx, attributes) {
+		if err := add(fmt.Sprintf("Replicated write I/O (to %s)", secondary), "request", auroraReplicatedWrites, float64(usage.Aurora.MonthlyWriteIORequests)); err != nil {
+			return nil, err
+		}
+	}
+
+	return newCost(components...), nil
+}
+
+// costForMultiAZCluster calculates the cost of a Multi-AZ DB cluster, which runs a writer and two readable standby
+// instances of its db_cluster_instance_class.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   attributes: The attributes of the RDS cluster resource.
+//   engine: The engine of the cluster.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the Multi-AZ DB cluster.
+//   An error if the cluster has no instance class or the pricing data cannot be found.
+func costForMultiAZCluster(ctx *CalculationContext, attributes map[string]interface{}, engine rdsEngine) (*Cost, error) {
+	instanceClass, _ := attributes["db_cluster_instance_class"].(string)
+	if instanceClass == "" {
+		return nil, missingAttributeError("db_cluster_instance_class")
+	}
+	candidates := filterSKUs(ctx.PriceList, ctx.PriceList.Index().Lookup("AmazonRDS", ctx.Location, pricing.AttrInstanceClass, instanceClass), func(attr pricing.ProductAttributes) bool {
+		return matchesOptional(attr.DatabaseEngine, engine.DatabaseEngine) &&
+			attr.DeploymentOption == rdsMultiAZClusterDeploymentOption
+	})
+	description := engine.describe(instanceClass, rdsMultiAZClusterDeploymentOption)
+	sku, price, err := ctx.MatchSKU("RDS instance class: "+description, candidates)
+	if err != nil {
+		return nil, err
+	}
+	components := []CostComponent{hourlyComponent(fmt.Sprintf("Database cluster usage (%s)", description), sku, 1, price)}
+
+	storage, err := rdsStorageComponents(ctx, attributes, engine, rdsMultiAZClusterDeploymentOption)
+	if err != nil {
+		return nil, err
+	}
+	components = append(components, storage...)
+
+	return newCost(components...), nil
+}
+
+// costForRDSClusterInstance calculates the cost of an AWS RDS cluster instance.
+// Provisioned instances are priced by instance class and engine, at the I/O-Optimized rate if their cluster uses
+// that configuration. Serverless v2 instances (db.serverless) are priced by the ACUs they consume: the average in
+// the aurora.serverless_v2_average_acus usage estimate, or the minimum capacity of the cluster's
+// serverlessv2_scaling_configuration without one. They report the range of their cost between the minimum and
+// maximum capacity.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its plan is used to find the cluster of the instance.
+//   attributes: The attributes of the RDS cluster instance resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the RDS cluster instance.
+//   An error if the engine is unknown, the scaling configuration of a Serverless v2 instance cannot be found, or
+//   the pricing data cannot be found.
+func costForRDSClusterInstance(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	instanceClass, _ := attributes["instance_class"].(string)
+	if instanceClass == "" {
+		return nil, missingAttributeError("instance_class")
+	}
+	clusterIdentifier, _ := attributes["cluster_identifier"].(string)
+	cluster := findPlanResourceByAttribute(ctx, "aws_rds_cluster", "cluster_identifier", clusterIdentifier)
+
+	engine, err := resolveAuroraEngine(attributes)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := attributes["engine"].(string); !ok && cluster != nil {
+		if engine, err = resolveAuroraEngine(cluster); err != nil {
+			return nil, err
+		}
+	}
+	storageType, _ := cluster["storage_type"].(string)
+	ioOptimized := storageType == auroraIOOptimizedStorageType
+	location := resourceLocation(ctx, cluster)
+
+	if instanceClass == auroraServerlessInstanceClass {
+		return costForServerlessV2(ctx, attributes, cluster, engine, location, ioOptimized)
+	}
+
+	candidates := filterSKUs(ctx.PriceList, ctx.PriceList.Index().Lookup("AmazonRDS", location, pricing.AttrInstanceClass, instanceClass), func(attr pricing.ProductAttributes) bool {
+		return matchesOptional(attr.DatabaseEngine, engine.DatabaseEngine) &&
+			matchesOptional(attr.DeploymentOption, "Single-AZ") &&
+			strings.Contains(attr.UsageType, "IOOptimized") == ioOptimized
+	})
+	description := engine.describe(instanceClass, "Single-AZ")
+	if ioOptimized {
+		description += ", I/O-Optimized"
+	}
+	sku, price, err := ctx.MatchSKU("RDS instance class: "+description, candidates)
+	if err != nil {
+		return nil, err
+	}
+	return newCost(hourlyComponent(fmt.Sprintf("Database instance usage (%s)", description), sku, 1, price)), nil
+}
+
+// costForServerlessV2 calculates the cost of an Aurora Serverless v2 instance from the ACUs it consumes.
+// Its cost range is priced with the aurora.serverless_v2_average_acus usage estimate set to the min_capacity and
+// max_capacity of the serverlessv2_scaling_configuration of the cluster.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   attributes: The attributes of the RDS cluster instance resource.
+//   cluster: The attributes of the cluster of the instance, or nil if it is not in the plan.
+//   engine: The engine of the instance.
+//   location: The AWS pricing location of the cluster.
+//   ioOptimized: Whether the cluster uses the I/O-Optimized configuration.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the instance, with its scaling range.
+//   An error if the cluster has no Serverless v2 scaling configuration or the pricing data cannot be found.
+func costForServerlessV2(ctx *CalculationContext, attributes, cluster map[string]interface{}, engine rdsEngine, location string, ioOptimized bool) (*Cost, error) {
+	scaling := firstBlock(cluster, "serverlessv2_scaling_configuration")
+	if scaling == nil {
+		return nil, missingAttributeError("serverlessv2_scaling_configuration")
+	}
+	minCapacity, _ := scaling["min_capacity"].(float64)
+	maxCapacity, _ := scaling["max_capacity"].(float64)
+	if maxCapacity < minCapacity {
+		maxCapacity = minCapacity
+	}
+
+	capacity := minCapacity
+	if ctx.Usage != nil && ctx.Usage.Aurora.ServerlessV2AverageACUs > 0 {
+		capacity = min(max(ctx.Usage.Aurora.ServerlessV2AverageACUs, minCapacity), maxCapacity)
+	}
+
+	usageType, name := auroraServerlessV2, "Serverless v2 capacity"
+	if ioOptimized {
+		usageType, name = auroraIOOptimizedServerlessV2, "Serverless v2 capacity (I/O-Optimized)"
+	}
+	components, err := auroraComponents(ctx, location, engine, name, "ACU-hour", usageType, capacity*hoursPerMonth)
+	if err != nil {
+		return nil, err
+	}
+	cost := newCost(components...)
+	cost.Scaling = &Scaling{Min: minCapacity, Max: maxCapacity, Usage: func(usage *UsageEstimates, capacity float64) {
+		usage.Aurora.ServerlessV2AverageACUs = capacity
+	}}
+	return cost, nil
+}
+
+// resolveAuroraEngine determines the database engine of an RDS cluster or cluster instance. Clusters without an
+// engine attribute run Aurora MySQL, the default engine of aws_rds_cluster.
+//
+// Parameters:
+//   attributes: The attributes of the RDS cluster or cluster instance resource.
+//
+// Returns:
+//   The engine of the cluster or instance.
+//   An error if the engine is unknown.
+func resolveAuroraEngine(attributes map[string]interface{}) (rdsEngine, error) {
+	if name, _ := attributes["engine"].(string); name == "" {
+		return rdsEngines["aurora"], nil
+	}
+	return resolveRDSEngine(attributes)
+}
+
+// auroraSecondaryClusters lists the secondary clusters of the global database that an Aurora cluster is the primary
+// cluster of. The primary cluster is the source_db_cluster_identifier of the aws_rds_global_cluster in the plan, and
+// every other cluster with the same global_cluster_identifier is a secondary cluster.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   attributes: The attributes of the RDS cluster resource.
+//
+// Returns:
+//   The regions of the secondary clusters, or their addresses if their region is not known. It is empty if the
+//   cluster is not the primary cluster of a global database.
+func auroraSecondaryClusters(ctx *CalculationContext, attributes map[string]interface{}) []string {
+	globalCluster, _ := attributes["global_cluster_identifier"].(string)
+	if globalCluster == "" || ctx.Plan == nil || !isAuroraSourceCluster(ctx, attributes, globalCluster) {
+		return nil
+	}
+	var secondaries []string
+	for _, rc := range ctx.Plan.ResourceChanges {
+		if rc.Type != "aws_rds_cluster" || rc.After == nil || rc == ctx.ResourceChange {
+			continue
+		}
+		if identifier, _ := rc.After["global_cluster_identifier"].(string); identifier != globalCluster {
+			continue
+		}
+		secondary := resourceRegion(rc.After)
+		if secondary == "" {
+			secondary = rc.Address
+		}
+		secondaries = append(secondaries, secondary)
+	}
+	return secondaries
+}
+
+// isAuroraSourceCluster reports whether an Aurora cluster is the source_db_cluster_identifier of its global database.
+// The source is the ARN of the cluster, which is often not known until apply, so it also matches the cluster by the
+// cluster_identifier at the end of the ARN.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   attributes: The attributes of the RDS cluster resource.
+//   globalCluster: The global_cluster_identifier of the cluster.
+//
+// Returns:
+//   True if the aws_rds_global_cluster in the plan was created from the cluster.
+func isAuroraSourceCluster(ctx *CalculationContext, attributes map[string]interface{}, globalCluster string) bool {
+	global := findPlanResourceByAttribute(ctx, "aws_rds_global_cluster", "global_cluster_identifier", globalCluster)
+	source, _ := global["source_db_cluster_identifier"].(string)
+	if source == "" {
+		return false
+	}
+	if arn, _ := attributes["arn"].(string); arn == source {
+		return true
+	}
+	identifier, _ := attributes["cluster_identifier"].(string)
+	return identifier != "" && (source == identifier || strings.HasSuffix(source, ":cluster:"+identifier))
+}
+
+// auroraComponents prices a monthly quantity of an Aurora usage type with its price tiers.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   location: The AWS pricing location of the region the usage is charged in.
+//   engine: The engine of the cluster. Products for any engine also match.
+//   name: The name of the line item (e.g., "Storage").
+//   unit: The unit the line item is priced in (e.g., "GB-month").
+//   usageType: The usage type without a region prefix (e.g., "Aurora:StorageUsage").
+//   monthlyQuantity: The number of units consumed per month.
+//
+// Returns:
+//   The cost components, or none if the quantity is zero.
+//   An error if the pricing data cannot be found.
+func auroraComponents(ctx *CalculationContext, location string, engine rdsEngine, name, unit, usageType string, monthlyQuantity float64) ([]CostComponent, error) {
+	if monthlyQuantity <= 0 {
+		return nil, nil
+	}
+	candidates := filterSKUs(ctx.PriceList, usageTypeSKUs(ctx, "AmazonRDS", location, usageType), func(attr pricing.ProductAttributes) bool {
+		return attr.DatabaseEngine == "" || attr.DatabaseEngine == "Any" || strings.EqualFold(attr.DatabaseEngine, engine.DatabaseEngine)
+	})
+	sku, _, err := ctx.MatchSKU(fmt.Sprintf("Aurora %s (%s) in %s", usageType, engine.DatabaseEngine, location), candidates)
+	if err != nil {
+		return nil, err
+	}
+	return tieredComponents(ctx, name, unit, sku, monthlyQuantity)
+}
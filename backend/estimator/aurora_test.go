@@ -0,0 +1,244 @@
+package estimator
+
+import (
+	"testing"
+
+	"cloudcostguard/backend/pricing"
+	"cloudcostguard/backend/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+// createAuroraPriceList creates a price list with Aurora prices in US East (N. Virginia) and US West (Oregon).
+func createAuroraPriceList() *pricing.PriceList {
+	priceList := pricing.NewPriceList()
+	usEast, usWest := "US East (N. Virginia)", "US West (Oregon)"
+	instance := func(sku, location, engine, usageType, price string) {
+		addMockProduct(priceList, sku, pricing.ProductAttributes{
+			ServiceCode:      "AmazonRDS",
+			Location:         location,
+			InstanceClass:    "db.r6g.large",
+			DatabaseEngine:   engine,
+			DeploymentOption: "Single-AZ",
+			UsageType:        usageType,
+		}, price)
+	}
+	instance("aurora-postgres", usEast, "Aurora PostgreSQL", "InstanceUsage:db.r6g.large", "0.26")
+	instance("aurora-postgres-iopt", usEast, "Aurora PostgreSQL", "InstanceUsageIOOptimized:db.r6g.large", "0.338")
+	instance("aurora-mysql", usEast, "Aurora MySQL", "InstanceUsage:db.r6g.large", "0.26")
+	instance("aurora-postgres-west", usWest, "Aurora PostgreSQL", "USW2-InstanceUsage:db.r6g.large", "0.26")
+	addMockProduct(priceList, "multi-az-cluster", pricing.ProductAttributes{
+		ServiceCode:      "AmazonRDS",
+		Location:         usEast,
+		InstanceClass:    "db.m6gd.large",
+		DatabaseEngine:   "PostgreSQL",
+		DeploymentOption: "Multi-AZ (readable standbys)",
+	}, "0.522")
+
+	usage := func(sku, location, engine, usageType, price string) {
+		addMockProduct(priceList, sku, pricing.ProductAttributes{
+			ServiceCode:    "AmazonRDS",
+			Location:       location,
+			DatabaseEngine: engine,
+			UsageType:      usageType,
+		}, price)
+	}
+	usage("storage", usEast, "Any", "Aurora:StorageUsage", "0.10")
+	usage("storage-iopt", usEast, "Any", "Aurora:IO-OptimizedStorageUsage", "0.225")
+	usage("io", usEast, "Any", "Aurora:StorageIOUsage", "0.0000002")
+	usage("replicated-writes", usEast, "Any", "Aurora:ReplicatedWriteIO", "0.0000002")
+	usage("serverless-postgres", usEast, "Aurora PostgreSQL", "Aurora:ServerlessV2Usage", "0.12")
+	usage("serverless-mysql", usEast, "Aurora MySQL", "Aurora:ServerlessV2Usage", "0.12")
+	usage("serverless-postgres-iopt", usEast, "Aurora PostgreSQL", "Aurora:ServerlessV2IOOptimizedUsage", "0.156")
+	usage("storage-west", usWest, "Any", "USW2-Aurora:StorageUsage", "0.10")
+	return priceList
+}
+
+func TestAuroraPricing(t *testing.T) {
+	priceList := createAuroraPriceList()
+	estimate := func(t *testing.T, usage *UsageEstimates, resources ...*terraform.ResourceChange) *EstimationResponse {
+		result, err := Estimate(&terraform.Plan{ResourceChanges: resources}, priceList, "us-east-1", usage)
+		assert.NoError(t, err)
+		return result
+	}
+	resource := func(address string, after map[string]interface{}) *terraform.ResourceChange {
+		return &terraform.ResourceChange{
+			Address: address,
+			Type:    address[:len(address)-len(".main")],
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   after,
+		}
+	}
+	componentCosts := func(resource ResourceCost) map[string]float64 {
+		costs := make(map[string]float64)
+		for _, component := range resource.Components {
+			costs[component.Name] = component.MonthlyCost
+		}
+		return costs
+	}
+
+	t.Run("prices cluster instances by class and engine, and storage and I/O from usage estimates", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{Aurora: AuroraUsage{StorageGB: 200, MonthlyIORequests: 50000000}},
+			resource("aws_rds_cluster.main", map[string]interface{}{"cluster_identifier": "orders", "engine": "aurora-postgresql"}),
+			resource("aws_rds_cluster_instance.main", map[string]interface{}{
+				"cluster_identifier": "orders",
+				"instance_class":     "db.r6g.large",
+				"engine":             "aurora-postgresql",
+			}),
+		)
+		if assert.Len(t, result.Resources, 2) {
+			cluster := componentCosts(result.Resources[0])
+			assert.Len(t, cluster, 2)
+			assert.InDelta(t, 20.0, cluster["Storage"], 1e-9)
+			assert.InDelta(t, 10.0, cluster["I/O requests"], 1e-9)
+
+			instance := result.Resources[1]
+			assert.Equal(t, "Database instance usage (db.r6g.large, Aurora PostgreSQL)", instance.Components[0].Name)
+			assert.Equal(t, "aurora-postgres", instance.Components[0].SKU)
+			assert.InDelta(t, 0.26*730, instance.MonthlyCost, 1e-9)
+		}
+	})
+
+	t.Run("prices the I/O-Optimized configuration without I/O charges", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{Aurora: AuroraUsage{StorageGB: 200, MonthlyIORequests: 50000000}},
+			resource("aws_rds_cluster.main", map[string]interface{}{
+				"cluster_identifier": "orders",
+				"engine":             "aurora-postgresql",
+				"storage_type":       "aurora-iopt1",
+			}),
+			resource("aws_rds_cluster_instance.main", map[string]interface{}{
+				"cluster_identifier": "orders",
+				"instance_class":     "db.r6g.large",
+				"engine":             "aurora-postgresql",
+			}),
+		)
+		if assert.Len(t, result.Resources, 2) {
+			cluster := componentCosts(result.Resources[0])
+			assert.Len(t, cluster, 1)
+			assert.InDelta(t, 45.0, cluster["Storage (I/O-Optimized)"], 1e-9)
+			assert.Equal(t, "aurora-postgres-iopt", result.Resources[1].Components[0].SKU)
+		}
+	})
+
+	t.Run("prices Serverless v2 instances at the expected capacity with a range between min and max ACUs", func(t *testing.T) {
+		cluster := resource("aws_rds_cluster.main", map[string]interface{}{
+			"cluster_identifier": "orders",
+			"engine":             "aurora-postgresql",
+			"serverlessv2_scaling_configuration": []interface{}{
+				map[string]interface{}{"min_capacity": 0.5, "max_capacity": float64(16)},
+			},
+		})
+		instance := resource("aws_rds_cluster_instance.main", map[string]interface{}{
+			"cluster_identifier": "orders",
+			"instance_class":     "db.serverless",
+			"engine":             "aurora-postgresql",
+		})
+
+		result := estimate(t, &UsageEstimates{Aurora: AuroraUsage{ServerlessV2AverageACUs: 4}}, cluster, instance)
+		if assert.Len(t, result.Resources, 1) {
+			serverless := result.Resources[0]
+			assert.Equal(t, "Serverless v2 capacity", serverless.Components[0].Name)
+			assert.Equal(t, "ACU-hour", serverless.Components[0].Unit)
+			assert.InDelta(t, 4*730*0.12, serverless.MonthlyCost, 1e-9)
+			if assert.NotNil(t, serverless.MonthlyCostRange) {
+				assert.Equal(t, 0.5, serverless.MonthlyCostRange.MinCapacity)
+				assert.Equal(t, float64(16), serverless.MonthlyCostRange.MaxCapacity)
+				assert.InDelta(t, 0.5*730*0.12, serverless.MonthlyCostRange.MinMonthlyCost, 1e-9)
+				assert.InDelta(t, 16*730*0.12, serverless.MonthlyCostRange.MaxMonthlyCost, 1e-9)
+			}
+		}
+
+		// Without an estimate, instances run at the minimum capacity.
+		result = estimate(t, &UsageEstimates{}, cluster, instance)
+		assert.InDelta(t, 0.5*730*0.12, result.TotalMonthlyCost, 1e-9)
+	})
+
+	t.Run("charges the primary cluster of a global database for replicated writes", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{Aurora: AuroraUsage{StorageGB: 100, MonthlyWriteIORequests: 10000000}},
+			resource("aws_rds_global_cluster.main", map[string]interface{}{
+				"global_cluster_identifier":    "orders",
+				"source_db_cluster_identifier": "arn:aws:rds:us-east-1:123456789012:cluster:orders-primary",
+			}),
+			&terraform.ResourceChange{
+				Address: "aws_rds_cluster.primary",
+				Type:    "aws_rds_cluster",
+				Change:  terraform.Change{Actions: []string{"create"}},
+				After: map[string]interface{}{
+					"cluster_identifier":        "orders-primary",
+					"engine":                    "aurora-postgresql",
+					"global_cluster_identifier": "orders",
+					"master_username":           "admin",
+				},
+			},
+			&terraform.ResourceChange{
+				Address: "aws_rds_cluster.secondary",
+				Type:    "aws_rds_cluster",
+				Change:  terraform.Change{Actions: []string{"create"}},
+				After: map[string]interface{}{
+					"cluster_identifier":        "orders-secondary",
+					"engine":                    "aurora-postgresql",
+					"global_cluster_identifier": "orders",
+					"availability_zones":        []interface{}{"us-west-2a", "us-west-2b"},
+				},
+			},
+			&terraform.ResourceChange{
+				Address: "aws_rds_cluster_instance.secondary",
+				Type:    "aws_rds_cluster_instance",
+				Change:  terraform.Change{Actions: []string{"create"}},
+				After: map[string]interface{}{
+					"cluster_identifier": "orders-secondary",
+					"instance_class":     "db.r6g.large",
+					"engine":             "aurora-postgresql",
+				},
+			},
+		)
+		if assert.Len(t, result.Resources, 3) {
+			primary := componentCosts(result.Resources[0])
+			assert.InDelta(t, 2.0, primary["Replicated write I/O (to us-west-2)"], 1e-9)
+			assert.NotContains(t, componentCosts(result.Resources[1]), "Replicated write I/O (to us-west-2)")
+			assert.Equal(t, "storage-west", result.Resources[1].Components[0].SKU)
+			assert.Equal(t, "aurora-postgres-west", result.Resources[2].Components[0].SKU)
+		}
+		assert.Equal(t, 4, result.Coverage.PricedResources)
+	})
+
+	t.Run("charges no replicated writes without the source cluster of the global database", func(t *testing.T) {
+		cluster := func(address, identifier string) *terraform.ResourceChange {
+			return resource(address, map[string]interface{}{
+				"cluster_identifier":        identifier,
+				"engine":                    "aurora-postgresql",
+				"global_cluster_identifier": "orders",
+				"master_username":           "admin",
+			})
+		}
+		result := estimate(t, &UsageEstimates{Aurora: AuroraUsage{StorageGB: 100, MonthlyWriteIORequests: 10000000}},
+			resource("aws_rds_global_cluster.main", map[string]interface{}{"global_cluster_identifier": "orders"}),
+			cluster("aws_rds_cluster.a", "orders-a"),
+			cluster("aws_rds_cluster.b", "orders-b"),
+		)
+		for _, priced := range result.Resources {
+			for name := range componentCosts(priced) {
+				assert.NotContains(t, name, "Replicated write I/O")
+			}
+		}
+	})
+
+	t.Run("prices Multi-AZ DB clusters by cluster instance class", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{}, resource("aws_rds_cluster.main", map[string]interface{}{
+			"engine":                    "postgres",
+			"db_cluster_instance_class": "db.m6gd.large",
+		}))
+		if assert.Len(t, result.Resources, 1) {
+			assert.Equal(t, "Database cluster usage (db.m6gd.large, PostgreSQL, Multi-AZ (readable standbys))", result.Resources[0].Components[0].Name)
+			assert.InDelta(t, 0.522*730, result.TotalMonthlyCost, 1e-9)
+		}
+	})
+
+	t.Run("skips Serverless v1 clusters", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{}, resource("aws_rds_cluster.main", map[string]interface{}{
+			"engine":      "aurora-mysql",
+			"engine_mode": "serverless",
+		}))
+		assert.Empty(t, result.Resources)
+		assert.Equal(t, SkipReasonMissingAttribute, result.Skipped[0].Reason)
+	})
+}
@@ -0,0 +1,301 @@
+package estimator
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+func init() {
+	MustRegister(NewCalculator("aws_autoscaling_group", []string{"AmazonEC2"}, []string{"min_size", "max_size"}, costForAutoScalingGroup))
+	// Cost is calculated as part of the Auto Scaling groups that launch instances from them, not standalone.
+	MustRegister(NewCalculator("aws_launch_template", nil, nil, zeroCost))
+	MustRegister(NewCalculator("aws_launch_configuration", nil, nil, zeroCost))
+}
+
+// launchTemplate describes the instances an Auto Scaling group launches, as read from its launch template or
+// launch configuration.
+type launchTemplate struct {
+	// InstanceType is the EC2 instance type (e.g., "m5.large"). It may be empty if a mixed instances policy sets it.
+	InstanceType string
+	// ImageID is the ID of the AMI the instances launch from.
+	ImageID string
+	// Tenancy is the tenancy of the instances ("default", "dedicated" or "host"); empty means "default".
+	Tenancy string
+	// Spot is true if the instances are launched as spot instances.
+	Spot bool
+	// BlockDevices lists the EBS volumes attached to each instance.
+	BlockDevices []ebsVolume
+}
+
+// instanceOverride is an instance type of a mixed instances policy and the number of capacity units each of its
+// instances counts for.
+type instanceOverride struct {
+	InstanceType string
+	Weight       float64
+}
+
+// costForAutoScalingGroup calculates the cost of an AWS Auto Scaling group.
+// The instances are described by the launch template or launch configuration in the plan that the group references.
+// The group is priced at its desired capacity, or at its minimum size if it has no desired capacity, and reports the
+// range of its cost between min_size and max_size. A mixed instances policy splits the capacity evenly between its
+// instance types, so an instance type with a weighted capacity of 2 runs half as many instances as one with 1, and
+// between on-demand and spot instances as set in its instances distribution.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its plan is used to resolve the launch template.
+//   attributes: The attributes of the Auto Scaling group resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the Auto Scaling group.
+//   An error if the launch template cannot be resolved or the pricing data cannot be found.
+func costForAutoScalingGroup(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	minSize, _ := attributes["min_size"].(float64)
+	maxSize, _ := attributes["max_size"].(float64)
+	desiredCapacity, ok := attributes["desired_capacity"].(float64)
+	if !ok {
+		desiredCapacity = minSize
+	}
+
+	policy := firstBlock(attributes, "mixed_instances_policy")
+	template, err := resolveLaunchTemplate(ctx, attributes, policy)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := instanceOverrides(template, policy)
+	if err != nil {
+		return nil, err
+	}
+	platform, err := resolveEC2Platform(ctx, map[string]interface{}{"ami": template.ImageID})
+	if err != nil {
+		return nil, err
+	}
+
+	onDemandCapacity, spotCapacity := splitCapacity(desiredCapacity, template.Spot, policy)
+	share := 1 / float64(len(overrides))
+	var components []CostComponent
+	instances := 0.0
+	for _, override := range overrides {
+		if onDemandCapacity > 0 {
+			instance, err := ec2InstanceComponent(ctx, override.InstanceType, platform, template.Tenancy, onDemandCapacity*share/override.Weight)
+			if err != nil {
+				return nil, err
+			}
+			components = append(components, instance)
+		}
+		if spotCapacity > 0 {
+			instance, err := spotInstanceComponent(ctx, override.InstanceType, platform, template.Tenancy, spotCapacity*share/override.Weight)
+			if err != nil {
+				return nil, err
+			}
+			components = append(components, instance)
+		}
+		instances += desiredCapacity * share / override.Weight
+	}
+
+	if instances > 0 {
+		blockDevices, err := ebsVolumesComponents(ctx, template.BlockDevices)
+		if err != nil {
+			return nil, err
+		}
+		for _, component := range blockDevices {
+			components = append(components, newComponent(component.Name, component.Unit, component.SKU, component.MonthlyQuantity*instances, component.UnitPrice))
+		}
+	}
+
+	cost := newCost(components...)
+	cost.Scaling = &Scaling{Attribute: "desired_capacity", Min: minSize, Max: maxSize}
+	return cost, nil
+}
+
+// resolveLaunchTemplate finds the launch template or launch configuration of an Auto Scaling group in the plan.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   attributes: The attributes of the Auto Scaling group resource.
+//   policy: The mixed instances policy of the group, or nil if it has none.
+//
+// Returns:
+//   The instances described by the launch template or launch configuration.
+//   An error if the group references none, or the plan does not contain the one it references.
+func resolveLaunchTemplate(ctx *CalculationContext, attributes map[string]interface{}, policy map[string]interface{}) (launchTemplate, error) {
+	if policy != nil {
+		specification := firstBlock(firstBlock(policy, "launch_template"), "launch_template_specification")
+		if specification == nil {
+			return launchTemplate{}, missingAttributeError("mixed_instances_policy.launch_template")
+		}
+		id, _ := specification["launch_template_id"].(string)
+		name, _ := specification["launch_template_name"].(string)
+		return findLaunchTemplate(ctx, id, name)
+	}
+	if reference := firstBlock(attributes, "launch_template"); reference != nil {
+		id, _ := reference["id"].(string)
+		name, _ := reference["name"].(string)
+		return findLaunchTemplate(ctx, id, name)
+	}
+	if _, ok := attributes["launch_configuration"]; ok {
+		name, _ := attributes["launch_configuration"].(string)
+		configuration := findPlanResource(ctx, "aws_launch_configuration", name)
+		if configuration == nil {
+			return launchTemplate{}, fmt.Errorf("%w: launch configuration %s not found in plan", ErrMissingAttribute, name)
+		}
+		instanceType, _ := configuration["instance_type"].(string)
+		imageID, _ := configuration["image_id"].(string)
+		tenancy, _ := configuration["placement_tenancy"].(string)
+		spotPrice, _ := configuration["spot_price"].(string)
+		return launchTemplate{
+			InstanceType: instanceType,
+			ImageID:      imageID,
+			Tenancy:      tenancy,
+			Spot:         spotPrice != "",
+			BlockDevices: instanceBlockDevices(configuration),
+		}, nil
+	}
+	return launchTemplate{}, missingAttributeError("launch_template")
+}
+
+// findLaunchTemplate reads the aws_launch_template in the plan with an ID or name.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   id: The ID of the launch template, or empty if unknown.
+//   name: The name of the launch template, or empty if unknown.
+//
+// Returns:
+//   The instances described by the launch template.
+//   An error if the plan does not contain the launch template.
+func findLaunchTemplate(ctx *CalculationContext, id, name string) (launchTemplate, error) {
+	attributes := findPlanResource(ctx, "aws_launch_template", id, name)
+	if attributes == nil {
+		return launchTemplate{}, fmt.Errorf("%w: launch template %s not found in plan", ErrMissingAttribute, strings.TrimSpace(id+" "+name))
+	}
+
+	template := launchTemplate{}
+	template.InstanceType, _ = attributes["instance_type"].(string)
+	template.ImageID, _ = attributes["image_id"].(string)
+	if placement := firstBlock(attributes, "placement"); placement != nil {
+		template.Tenancy, _ = placement["tenancy"].(string)
+	}
+	if marketOptions := firstBlock(attributes, "instance_market_options"); marketOptions != nil {
+		marketType, _ := marketOptions["market_type"].(string)
+		template.Spot = marketType == "spot"
+	}
+	for _, mapping := range blocks(attributes, "block_device_mappings") {
+		if ebs := firstBlock(mapping, "ebs"); ebs != nil {
+			template.BlockDevices = append(template.BlockDevices, newEBSVolume(blockDeviceLabel(mapping), ebs, "volume_type", "volume_size"))
+		}
+	}
+	return template, nil
+}
+
+// instanceOverrides lists the instance types an Auto Scaling group launches and their weighted capacity.
+//
+// Parameters:
+//   template: The launch template of the group.
+//   policy: The mixed instances policy of the group, or nil if it has none.
+//
+// Returns:
+//   The instance types of the overrides of the mixed instances policy, or the instance type of the launch template.
+//   An error if no instance type is known or a weighted capacity is invalid.
+func instanceOverrides(template launchTemplate, policy map[string]interface{}) ([]instanceOverride, error) {
+	var overrides []instanceOverride
+	for _, override := range blocks(firstBlock(policy, "launch_template"), "override") {
+		instanceType, _ := override["instance_type"].(string)
+		if instanceType == "" {
+			continue
+		}
+		weight := 1.0
+		if weightedCapacity, ok := override["weighted_capacity"]; ok && weightedCapacity != nil && weightedCapacity != "" {
+			var err error
+			weight, err = parseFloat(weightedCapacity)
+			if err != nil || weight <= 0 {
+				return nil, fmt.Errorf("%w: invalid weighted_capacity %v for %s", ErrMissingAttribute, weightedCapacity, instanceType)
+			}
+		}
+		overrides = append(overrides, instanceOverride{InstanceType: instanceType, Weight: weight})
+	}
+	if len(overrides) > 0 {
+		return overrides, nil
+	}
+	if template.InstanceType == "" {
+		return nil, missingAttributeError("instance_type")
+	}
+	return []instanceOverride{{InstanceType: template.InstanceType, Weight: 1}}, nil
+}
+
+// splitCapacity splits the capacity of an Auto Scaling group between on-demand and spot instances.
+//
+// Parameters:
+//   capacity: The capacity of the group.
+//   spot: True if the launch template launches spot instances.
+//   policy: The mixed instances policy of the group, or nil if it has none.
+//
+// Returns:
+//   The on-demand capacity and the spot capacity.
+func splitCapacity(capacity float64, spot bool, policy map[string]interface{}) (float64, float64) {
+	if policy == nil {
+		if spot {
+			return 0, capacity
+		}
+		return capacity, 0
+	}
+
+	// Without an instances distribution, mixed instances policies launch only on-demand instances.
+	base, percent := 0.0, 100.0
+	if distribution := firstBlock(policy, "instances_distribution"); distribution != nil {
+		base, _ = distribution["on_demand_base_capacity"].(float64)
+		if value, ok := distribution["on_demand_percentage_above_base_capacity"].(float64); ok {
+			percent = value
+		}
+	}
+	onDemand := math.Min(base, capacity) + math.Max(capacity-base, 0)*percent/100
+	return onDemand, capacity - onDemand
+}
+
+// spotInstanceComponent prices the instance hours of EC2 spot instances.
+// AWS does not publish spot prices in its price list, so they are estimated from the on-demand price and the
+// ec2.spot_discount_percent usage estimate. Without the estimate, spot instances are priced at on-demand rates and a
+// warning is reported.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   instanceType: The EC2 instance type (e.g., "t3.medium").
+//   platform: The operating system and licensed software of the instances.
+//   tenancy: The tenancy attribute of the instances; empty means "default".
+//   count: The number of instances.
+//
+// Returns:
+//   The cost component of the spot instance hours.
+//   An error if the spot discount is invalid or the pricing data cannot be found.
+func spotInstanceComponent(ctx *CalculationContext, instanceType string, platform ec2Platform, tenancy string, count float64) (CostComponent, error) {
+	discount := 0
+	if ctx.Usage != nil {
+		discount = ctx.Usage.EC2.SpotDiscountPercent
+	}
+	if discount < 0 || discount > 100 {
+		return CostComponent{}, fmt.Errorf("%w: ec2.spot_discount_percent %d must be between 0 and 100", ErrMissingAttribute, discount)
+	}
+
+	instance, err := ec2InstanceComponent(ctx, instanceType, platform, tenancy, count)
+	if err != nil {
+		return CostComponent{}, err
+	}
+	if discount == 0 {
+		ctx.AddWarning(Warning{
+			Code:    WarningSpotPricedOnDemand,
+			Message: fmt.Sprintf("spot instances of type %s are priced at on-demand rates; set the ec2.spot_discount_percent usage estimate to estimate spot savings", instanceType),
+		})
+	}
+	name := "Spot instance usage" + strings.TrimPrefix(instance.Name, "Instance usage")
+	component := newComponent(name, "hour", instance.SKU, instance.MonthlyQuantity, instance.UnitPrice*(1-float64(discount)/100))
+	component.spot = true
+	return component, nil
+}
+
+// firstBlock returns the first nested block of a resource attribute, or nil if there is none.
+func firstBlock(attributes map[string]interface{}, name string) map[string]interface{} {
+	if list := blocks(attributes, name); len(list) > 0 {
+		return list[0]
+	}
+	return nil
+}
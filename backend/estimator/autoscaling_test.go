@@ -0,0 +1,205 @@
+package estimator
+
+import (
+	"testing"
+
+	"cloudcostguard/backend/pricing"
+	"cloudcostguard/backend/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAutoScalingGroupPricing(t *testing.T) {
+	priceList := createEC2PriceList()
+	addEBSPrices(priceList)
+	launchTemplate := &terraform.ResourceChange{
+		Address: "aws_launch_template.web",
+		Type:    "aws_launch_template",
+		Change:  terraform.Change{Actions: []string{"create"}},
+		After: map[string]interface{}{
+			"name":          "web",
+			"instance_type": "m5.large",
+			"block_device_mappings": []interface{}{
+				map[string]interface{}{
+					"device_name": "/dev/xvda",
+					"ebs":         []interface{}{map[string]interface{}{"volume_size": float64(100), "volume_type": "gp3"}},
+				},
+			},
+		},
+	}
+	estimate := func(t *testing.T, usage *UsageEstimates, group map[string]interface{}, others ...*terraform.ResourceChange) *EstimationResponse {
+		plan := &terraform.Plan{ResourceChanges: append(others, &terraform.ResourceChange{
+			Address: "aws_autoscaling_group.web",
+			Type:    "aws_autoscaling_group",
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   group,
+		})}
+		result, err := Estimate(plan, priceList, "us-east-1", usage)
+		assert.NoError(t, err)
+		return result
+	}
+
+	t.Run("prices the desired capacity and reports the range between min and max size", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{}, map[string]interface{}{
+			"min_size":         float64(2),
+			"max_size":         float64(10),
+			"desired_capacity": float64(4),
+			"launch_template":  []interface{}{map[string]interface{}{"name": "web"}},
+		}, launchTemplate)
+
+		perInstance := 0.096*730 + 0.08*100
+		if assert.Len(t, result.Resources, 1) {
+			resource := result.Resources[0]
+			assert.Equal(t, "aws_autoscaling_group.web", resource.Address)
+			assert.InDelta(t, 4*perInstance, resource.MonthlyCost, 0.001)
+			assert.Equal(t, "Instance usage (m5.large)", resource.Components[0].Name)
+			assert.InDelta(t, 4*730, resource.Components[0].MonthlyQuantity, 0.001)
+			assert.Equal(t, "Block device /dev/xvda storage (gp3)", resource.Components[1].Name)
+			if assert.NotNil(t, resource.MonthlyCostRange) {
+				assert.Equal(t, float64(2), resource.MonthlyCostRange.MinCapacity)
+				assert.Equal(t, float64(10), resource.MonthlyCostRange.MaxCapacity)
+				assert.InDelta(t, 2*perInstance, resource.MonthlyCostRange.MinMonthlyCost, 0.001)
+				assert.InDelta(t, 10*perInstance, resource.MonthlyCostRange.MaxMonthlyCost, 0.001)
+			}
+		}
+		assert.Equal(t, 2, result.Coverage.PricedResources)
+	})
+
+	t.Run("uses the minimum size without a desired capacity", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{}, map[string]interface{}{
+			"min_size":        float64(3),
+			"max_size":        float64(6),
+			"launch_template": []interface{}{map[string]interface{}{}},
+		}, launchTemplate)
+		assert.InDelta(t, 3*(0.096*730+0.08*100), result.TotalMonthlyCost, 0.001)
+	})
+
+	t.Run("prices launch configurations and spot instances", func(t *testing.T) {
+		configuration := &terraform.ResourceChange{
+			Address: "aws_launch_configuration.web",
+			Type:    "aws_launch_configuration",
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   map[string]interface{}{"name": "web-lc", "instance_type": "m5.large", "spot_price": "0.05"},
+		}
+		group := map[string]interface{}{
+			"min_size":             float64(1),
+			"max_size":             float64(1),
+			"launch_configuration": "web-lc",
+		}
+
+		result := estimate(t, &UsageEstimates{EC2: EC2Usage{SpotDiscountPercent: 60}}, group, configuration)
+		if assert.Len(t, result.Resources, 1) {
+			assert.Equal(t, "Spot instance usage (m5.large)", result.Resources[0].Components[0].Name)
+			assert.Equal(t, "hour", result.Resources[0].Components[0].Unit)
+			assert.InDelta(t, 0.096*0.4*730, result.TotalMonthlyCost, 0.001)
+		}
+		assert.Empty(t, result.Warnings)
+
+		result = estimate(t, &UsageEstimates{}, group, configuration)
+		assert.InDelta(t, 0.096*730, result.TotalMonthlyCost, 0.001)
+		if assert.Len(t, result.Warnings, 1) {
+			assert.Equal(t, WarningSpotPricedOnDemand, result.Warnings[0].Code)
+		}
+	})
+
+	t.Run("keeps the spot price under instance type price overrides", func(t *testing.T) {
+		configuration := &terraform.ResourceChange{
+			Address: "aws_launch_configuration.web",
+			Type:    "aws_launch_configuration",
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   map[string]interface{}{"name": "web-lc", "instance_type": "m5.large", "spot_price": "0.05"},
+		}
+		plan := &terraform.Plan{ResourceChanges: []*terraform.ResourceChange{configuration, {
+			Address: "aws_autoscaling_group.web",
+			Type:    "aws_autoscaling_group",
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   map[string]interface{}{"min_size": float64(1), "max_size": float64(1), "launch_configuration": "web-lc"},
+		}}}
+		policy := &DiscountPolicy{
+			Services:  map[string]float64{"AmazonEC2": 10},
+			Overrides: []PriceOverride{{InstanceType: "m5.large", UnitPrice: 0.08}},
+		}
+		result, err := EstimateWithOptions(plan, priceList, "us-east-1", &UsageEstimates{EC2: EC2Usage{SpotDiscountPercent: 60}}, Options{Discounts: policy})
+		assert.NoError(t, err)
+		if assert.Len(t, result.Resources, 1) {
+			component := result.Resources[0].Components[0]
+			assert.Equal(t, "10% off AmazonEC2", component.Discount)
+			assert.InDelta(t, 0.096*0.4*0.9, component.UnitPrice, 1e-9)
+		}
+	})
+
+	t.Run("keeps the spot price under reserved pricing models", func(t *testing.T) {
+		reservedPrices := createEC2PriceList()
+		hourly := pricing.PriceDimension{Unit: "Hrs"}
+		hourly.PricePerUnit.USD = "0.06"
+		reservedPrices.Terms.Reserved = map[string]map[string]pricing.Term{
+			"linux": {"linux.4NA7Y494T4": {
+				OfferTermCode:   "4NA7Y494T4",
+				PriceDimensions: map[string]pricing.PriceDimension{"hourly": hourly},
+				TermAttributes:  pricing.TermAttributes{LeaseContractLength: "1yr", PurchaseOption: "No Upfront", OfferingClass: "standard"},
+			}},
+		}
+		configuration := &terraform.ResourceChange{
+			Address: "aws_launch_configuration.web",
+			Type:    "aws_launch_configuration",
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   map[string]interface{}{"name": "web-lc", "instance_type": "m5.large", "spot_price": "0.05"},
+		}
+		plan := &terraform.Plan{ResourceChanges: []*terraform.ResourceChange{configuration, {
+			Address: "aws_autoscaling_group.web",
+			Type:    "aws_autoscaling_group",
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   map[string]interface{}{"min_size": float64(1), "max_size": float64(1), "launch_configuration": "web-lc"},
+		}}}
+		opts := Options{PricingModels: map[string]PricingModel{"aws_autoscaling_group": {Term: PricingTermReserved}}}
+		result, err := EstimateWithOptions(plan, reservedPrices, "us-east-1", &UsageEstimates{EC2: EC2Usage{SpotDiscountPercent: 60}}, opts)
+		assert.NoError(t, err)
+		if assert.Len(t, result.Resources, 1) {
+			assert.Equal(t, "Spot instance usage (m5.large)", result.Resources[0].Components[0].Name)
+			assert.Nil(t, result.Resources[0].Commitment)
+			assert.InDelta(t, 0.096*0.4*730, result.TotalMonthlyCost, 0.001)
+		}
+	})
+
+	t.Run("prices mixed instances policies by weighted instance mix", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{EC2: EC2Usage{SpotDiscountPercent: 50}}, map[string]interface{}{
+			"min_size":         float64(4),
+			"max_size":         float64(8),
+			"desired_capacity": float64(8),
+			"mixed_instances_policy": []interface{}{map[string]interface{}{
+				"instances_distribution": []interface{}{map[string]interface{}{
+					"on_demand_base_capacity":                  float64(2),
+					"on_demand_percentage_above_base_capacity": float64(50),
+				}},
+				"launch_template": []interface{}{map[string]interface{}{
+					"launch_template_specification": []interface{}{map[string]interface{}{"launch_template_name": "web"}},
+					"override": []interface{}{
+						map[string]interface{}{"instance_type": "m5.large", "weighted_capacity": "1"},
+						map[string]interface{}{"instance_type": "t3.medium", "weighted_capacity": "2"},
+					},
+				}},
+			}},
+		}, launchTemplate)
+
+		// 8 capacity units: 2 + 50% of 6 = 5 on demand and 3 spot, split evenly between the instance types.
+		// m5.large runs 2.5 on-demand and 1.5 spot instances, t3.medium half as many.
+		costs := make(map[string]float64)
+		for _, component := range result.Resources[0].Components {
+			costs[component.Name] = component.MonthlyQuantity
+		}
+		assert.InDelta(t, 2.5*730, costs["Instance usage (m5.large)"], 0.001)
+		assert.InDelta(t, 1.5*730, costs["Spot instance usage (m5.large)"], 0.001)
+		assert.InDelta(t, 1.25*730, costs["Instance usage (t3.medium)"], 0.001)
+		assert.InDelta(t, 0.75*730, costs["Spot instance usage (t3.medium)"], 0.001)
+		assert.InDelta(t, 6*100, costs["Block device /dev/xvda storage (gp3)"], 0.001)
+	})
+
+	t.Run("skips groups whose launch template is not in the plan", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{}, map[string]interface{}{
+			"min_size":        float64(1),
+			"max_size":        float64(2),
+			"launch_template": []interface{}{map[string]interface{}{"id": "lt-0123456789abcdef0"}},
+		})
+		assert.Empty(t, result.Resources)
+		assert.Equal(t, SkipReasonMissingAttribute, result.Skipped[0].Reason)
+	})
+}
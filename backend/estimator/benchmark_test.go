@@ -0,0 +1,130 @@
+package estimator
+
+import (
+	"fmt"
+	"testing"
+
+	"cloudcostguard/backend/pricing"
+	"cloudcostguard/backend/terraform"
+)
+
+// createLargePriceList builds a synthetic price list shaped like real offer files: every region carries thousands
+// of EC2 instance types, each with several operating system variants, and thousands of S3, VPC, ECS, EKS and ELB
+// products, of which only a few price the resources of the plan.
+func createLargePriceList(instanceTypes int) *pricing.PriceList {
+	priceList := pricing.NewPriceList()
+	priceList.Terms.OnDemand = make(map[string]map[string]pricing.Term)
+	add := func(sku string, attributes pricing.ProductAttributes) {
+		priceList.Products[sku] = pricing.Product{SKU: sku, Attributes: attributes}
+		dim := pricing.PriceDimension{}
+		dim.PricePerUnit.USD = "0.10"
+		priceList.Terms.OnDemand[sku] = map[string]pricing.Term{
+			"term": {PriceDimensions: map[string]pricing.PriceDimension{"dim": dim}},
+		}
+	}
+
+	for _, location := range regionMap {
+		for i := 0; i < instanceTypes; i++ {
+			instanceType := fmt.Sprintf("m%d.large", i)
+			for _, os := range []string{"Linux", "Windows", "RHEL", "SUSE"} {
+				add(fmt.Sprintf("%s-%s-%s", location, instanceType, os), pricing.ProductAttributes{
+					ServiceCode:     "AmazonEC2",
+					InstanceType:    instanceType,
+					Location:        location,
+					OperatingSystem: os,
+					UsageType:       "BoxUsage:" + instanceType,
+				})
+			}
+			for _, serviceCode := range []string{"AmazonS3", "AmazonVPC", "AmazonECS", "AmazonEKS", "AWSELB"} {
+				add(fmt.Sprintf("%s-%s-%d", location, serviceCode, i), pricing.ProductAttributes{
+					ServiceCode: serviceCode,
+					Location:    location,
+					Group:       fmt.Sprintf("Group-%d", i),
+					UsageType:   fmt.Sprintf("Usage-%d", i),
+				})
+			}
+		}
+
+		for sku, attributes := range map[string]pricing.ProductAttributes{
+			"s3-storage":    {ServiceCode: "AmazonS3", StorageClass: "General Purpose", UsageType: "TimedStorage-ByteHrs"},
+			"s3-put":        {ServiceCode: "AmazonS3", Group: "S3-Request-Tier1"},
+			"nat-hours":     {ServiceCode: "AmazonVPC", Group: "NAT Gateway"},
+			"nat-bytes":     {ServiceCode: "AmazonVPC", UsageType: "NatGateway-Bytes"},
+			"fargate-vcpu":  {ServiceCode: "AmazonECS", UsageType: "Fargate-vCPU-Hours:perCPU"},
+			"fargate-gb":    {ServiceCode: "AmazonECS", UsageType: "Fargate-GB-Hours"},
+			"eks-cluster":   {ServiceCode: "AmazonEKS", UsageType: "AmazonEKS-Hours:perCluster"},
+			"alb-hours":     {ServiceCode: "AWSELB", Operation: "LoadBalancing:Application", UsageType: "LoadBalancerUsage"},
+			"alb-lcu-hours": {ServiceCode: "AWSELB", Operation: "LoadBalancing:Application", UsageType: "LCUUsage"},
+		} {
+			attributes.Location = location
+			add(location+"-"+sku, attributes)
+		}
+	}
+	return priceList
+}
+
+// createLargePlan builds a plan that mostly creates EC2 instances, with an S3 bucket, NAT gateway, Fargate service,
+// EKS cluster and load balancer among every ten resources.
+func createLargePlan(resources, instanceTypes int) *terraform.Plan {
+	plan := &terraform.Plan{ResourceChanges: []*terraform.ResourceChange{{
+		Address: "aws_ecs_task_definition.app",
+		Type:    "aws_ecs_task_definition",
+		Change:  terraform.Change{Actions: []string{"create"}},
+		After:   map[string]interface{}{"cpu": "256", "memory": "512"},
+	}}}
+	for i := 0; i < resources; i++ {
+		resourceType, after := "aws_instance", map[string]interface{}{"instance_type": fmt.Sprintf("m%d.large", i%instanceTypes)}
+		switch i % 10 {
+		case 5:
+			resourceType, after = "aws_s3_bucket", map[string]interface{}{}
+		case 6:
+			resourceType, after = "aws_nat_gateway", map[string]interface{}{}
+		case 7:
+			resourceType, after = "aws_ecs_service", map[string]interface{}{"launch_type": "FARGATE", "task_definition": "aws_ecs_task_definition.app"}
+		case 8:
+			resourceType, after = "aws_eks_cluster", map[string]interface{}{}
+		case 9:
+			resourceType, after = "aws_lb", map[string]interface{}{}
+		}
+		plan.ResourceChanges = append(plan.ResourceChanges, &terraform.ResourceChange{
+			Address: fmt.Sprintf("%s.res[%d]", resourceType, i),
+			Type:    resourceType,
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   after,
+		})
+	}
+	return plan
+}
+
+// BenchmarkEstimate prices a 1000-resource plan against roughly 170,000 EC2 products and 210,000 products of other services.
+func BenchmarkEstimate(b *testing.B) {
+	const instanceTypes = 2000
+	priceList := createLargePriceList(instanceTypes)
+	priceList.BuildIndex()
+	plan := createLargePlan(1000, instanceTypes)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Estimate(plan, priceList, "us-east-1", &UsageEstimates{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkEstimateSingleResource shows that the cost of pricing a resource of each type in the plan does not grow
+// with the price list.
+func BenchmarkEstimateSingleResource(b *testing.B) {
+	for _, instanceTypes := range []int{100, 1000, 4000} {
+		priceList := createLargePriceList(instanceTypes)
+		priceList.BuildIndex()
+		plan := createLargePlan(10, instanceTypes)
+
+		b.Run(fmt.Sprintf("products=%d", len(priceList.Products)), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := Estimate(plan, priceList, "us-east-1", &UsageEstimates{}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package estimator
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"cloudcostguard/backend/pricing"
+	"cloudcostguard/backend/terraform"
+)
+
+// ResourceCalculator calculates the cost of a single Terraform resource type.
+// Calculators are registered with a Registry and looked up by resource type when a plan is estimated.
+type ResourceCalculator interface {
+	// ResourceType returns the Terraform resource type priced by the calculator (e.g., "aws_instance").
+	ResourceType() string
+	// ServiceCodes returns the AWS service codes whose pricing data the calculator reads (e.g., "AmazonEC2").
+	ServiceCodes() []string
+	// RequiredAttributes returns the resource attributes that must be set before the calculator is invoked.
+	RequiredAttributes() []string
+	// Cost calculates the cost of the resource described by attributes.
+	Cost(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error)
+}
+
+// CalculationContext holds the inputs shared by all calculators while a resource change is priced.
+type CalculationContext struct {
+	// ResourceChange is the resource change being priced.
+	ResourceChange *terraform.ResourceChange
+	// PriceList is the list of AWS prices to use for the estimation.
+	PriceList *pricing.PriceList
+	// Location is the AWS pricing location (e.g., "US East (N. Virginia)").
+	Location string
+	// Usage contains the usage estimates that apply to the resource, with per-resource entries already applied.
+	Usage *UsageEstimates
+	// Plan is the full Terraform plan, for calculators that resolve references to other resources.
+	Plan *terraform.Plan
+	// PricingModel is the pricing model selected for the resource type.
+	PricingModel PricingModel
+	// Discounts is the discount policy applied to the list prices, or nil to use list prices.
+	Discounts *DiscountPolicy
+
+	warnings []Warning
+}
+
+// CostFunc calculates the cost of a resource from its attributes.
+type CostFunc func(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error)
+
+// calculator is a ResourceCalculator backed by a CostFunc.
+type calculator struct {
+	resourceType       string
+	serviceCodes       []string
+	requiredAttributes []string
+	cost               CostFunc
+}
+
+// NewCalculator creates a ResourceCalculator from a cost function.
+//
+// Parameters:
+//   resourceType: The Terraform resource type priced by the calculator.
+//   serviceCodes: The AWS service codes whose pricing data the calculator reads.
+//   requiredAttributes: The resource attributes that must be set before the cost function is invoked.
+//   cost: The function that calculates the cost of the resource.
+//
+// Returns:
+//   A ResourceCalculator for the resource type.
+func NewCalculator(resourceType string, serviceCodes []string, requiredAttributes []string, cost CostFunc) ResourceCalculator {
+	return &calculator{
+		resourceType:       resourceType,
+		serviceCodes:       serviceCodes,
+		requiredAttributes: requiredAttributes,
+		cost:               cost,
+	}
+}
+
+func (c *calculator) ResourceType() string         { return c.resourceType }
+func (c *calculator) ServiceCodes() []string       { return c.serviceCodes }
+func (c *calculator) RequiredAttributes() []string { return c.requiredAttributes }
+
+func (c *calculator) Cost(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	return c.cost(ctx, attributes)
+}
+
+// Registry maps Terraform resource types to the calculators that price them.
+// It is safe for concurrent use.
+type Registry struct {
+	mu          sync.RWMutex
+	calculators map[string]ResourceCalculator
+}
+
+// NewRegistry creates a new, empty registry.
+//
+// Returns:
+//   A pointer to a new, empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		calculators: make(map[string]ResourceCalculator),
+	}
+}
+
+// Register adds a calculator to the registry.
+//
+// Parameters:
+//   calc: The calculator to register.
+//
+// Returns:
+//   An error if the calculator has no resource type or its resource type is already registered, nil otherwise.
+func (r *Registry) Register(calc ResourceCalculator) error {
+	resourceType := calc.ResourceType()
+	if resourceType == "" {
+		return fmt.Errorf("calculator has no resource type")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.calculators[resourceType]; exists {
+		return fmt.Errorf("calculator already registered for resource type: %s", resourceType)
+	}
+	r.calculators[resourceType] = calc
+	return nil
+}
+
+// Lookup returns the calculator registered for a resource type.
+//
+// Parameters:
+//   resourceType: The Terraform resource type.
+//
+// Returns:
+//   The registered calculator and true, or nil and false if the resource type is not supported.
+func (r *Registry) Lookup(resourceType string) (ResourceCalculator, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	calc, ok := r.calculators[resourceType]
+	return calc, ok
+}
+
+// ResourceTypes returns the sorted list of resource types with a registered calculator.
+func (r *Registry) ResourceTypes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	types := make([]string, 0, len(r.calculators))
+	for resourceType := range r.calculators {
+		types = append(types, resourceType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// ServiceCodes returns the sorted, de-duplicated list of AWS service codes needed by the registered calculators.
+func (r *Registry) ServiceCodes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var codes []string
+	for _, calc := range r.calculators {
+		for _, code := range calc.ServiceCodes() {
+			if !seen[code] {
+				seen[code] = true
+				codes = append(codes, code)
+			}
+		}
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// DefaultRegistry is the registry used by Estimate. The built-in AWS calculators register themselves here.
+var DefaultRegistry = NewRegistry()
+
+// Register adds a calculator to the default registry.
+//
+// Parameters:
+//   calc: The calculator to register.
+//
+// Returns:
+//   An error if the calculator could not be registered, nil otherwise.
+func Register(calc ResourceCalculator) error {
+	return DefaultRegistry.Register(calc)
+}
+
+// MustRegister adds a calculator to the default registry and panics if it cannot be registered.
+// It is intended to be called from init functions.
+//
+// Parameters:
+//   calc: The calculator to register.
+func MustRegister(calc ResourceCalculator) {
+	if err := Register(calc); err != nil {
+		panic(err)
+	}
+}
+
+// ServiceCodes returns the AWS service codes needed by the calculators in the default registry.
+func ServiceCodes() []string {
+	return DefaultRegistry.ServiceCodes()
+}
+
+// checkRequiredAttributes verifies that every required attribute is set.
+//
+// Parameters:
+//   attributes: The attributes of the resource.
+//   required: The names of the required attributes.
+//
+// Returns:
+//   An error naming the first missing attribute, nil otherwise.
+func checkRequiredAttributes(attributes map[string]interface{}, required []string) error {
+	for _, name := range required {
+		value, ok := attributes[name]
+		if !ok || value == nil || value == "" {
+			return missingAttributeError(name)
+		}
+	}
+	return nil
+}
+
+// zeroCost is a CostFunc for resources whose cost is accounted for by another resource.
+func zeroCost(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	return newCost(), nil
+}
@@ -0,0 +1,118 @@
+package estimator
+
+import (
+	"testing"
+
+	"cloudcostguard/backend/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Run("registers and looks up calculators", func(t *testing.T) {
+		registry := NewRegistry()
+		calc := NewCalculator("custom_resource", []string{"CustomService"}, nil, zeroCost)
+
+		assert.NoError(t, registry.Register(calc))
+
+		found, ok := registry.Lookup("custom_resource")
+		assert.True(t, ok)
+		assert.Equal(t, calc, found)
+		assert.Equal(t, []string{"custom_resource"}, registry.ResourceTypes())
+		assert.Equal(t, []string{"CustomService"}, registry.ServiceCodes())
+	})
+
+	t.Run("rejects duplicate registrations", func(t *testing.T) {
+		registry := NewRegistry()
+		assert.NoError(t, registry.Register(NewCalculator("custom_resource", nil, nil, zeroCost)))
+		assert.Error(t, registry.Register(NewCalculator("custom_resource", nil, nil, zeroCost)))
+	})
+
+	t.Run("rejects calculators without a resource type", func(t *testing.T) {
+		registry := NewRegistry()
+		assert.Error(t, registry.Register(NewCalculator("", nil, nil, zeroCost)))
+	})
+
+	t.Run("registers the built-in calculators", func(t *testing.T) {
+		for _, resourceType := range []string{
+			"aws_instance", "aws_db_instance", "aws_ebs_volume", "aws_lb", "aws_elb", "aws_s3_bucket", "aws_nat_gateway",
+			"aws_lambda_function", "aws_ecs_service", "aws_eks_cluster", "aws_eks_node_group", "aws_elasticache_cluster",
+			"aws_autoscaling_group", "aws_launch_template", "aws_launch_configuration", "aws_dynamodb_table",
+			"aws_rds_cluster", "aws_rds_cluster_instance", "aws_elasticache_replication_group",
+			"aws_elasticache_serverless_cache", "aws_cloudfront_distribution", "aws_route53_zone", "aws_route53_record",
+			"aws_route53_health_check", "aws_eip", "aws_vpc_endpoint", "aws_ec2_transit_gateway_vpc_attachment",
+			"aws_vpn_connection", "aws_networkfirewall_firewall", "aws_s3_bucket_replication_configuration",
+			"aws_efs_file_system", "aws_fsx_lustre_file_system", "aws_fsx_windows_file_system", "aws_fsx_ontap_file_system",
+			"aws_fsx_openzfs_file_system", "aws_sqs_queue", "aws_sns_topic", "aws_kinesis_stream",
+			"aws_kinesis_firehose_delivery_stream", "aws_msk_cluster",
+		} {
+			_, ok := DefaultRegistry.Lookup(resourceType)
+			assert.True(t, ok, "expected a calculator for %s", resourceType)
+		}
+		assert.Contains(t, ServiceCodes(), "AmazonEC2")
+		assert.Contains(t, ServiceCodes(), "AWSDataTransfer")
+	})
+}
+
+// registerCustomCalculator registers a calculator for test_custom_resource that returns a cost without components.
+func registerCustomCalculator() {
+	if _, ok := DefaultRegistry.Lookup("test_custom_resource"); !ok {
+		MustRegister(NewCalculator("test_custom_resource", nil, []string{"units"}, func(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+			units, _ := attributes["units"].(float64)
+			return &Cost{Value: units * 2, Unit: "monthly", Breakdown: "custom"}, nil
+		}))
+	}
+}
+
+func TestEstimateWithCustomCalculator(t *testing.T) {
+	registerCustomCalculator()
+
+	plan := &terraform.Plan{
+		ResourceChanges: []*terraform.ResourceChange{
+			{
+				Address: "test_custom_resource.a",
+				Type:    "test_custom_resource",
+				Change:  terraform.Change{Actions: []string{"create"}},
+				After:   map[string]interface{}{"units": float64(5)},
+			},
+			{
+				Address: "test_custom_resource.missing",
+				Type:    "test_custom_resource",
+				Change:  terraform.Change{Actions: []string{"create"}},
+				After:   map[string]interface{}{},
+			},
+		},
+	}
+
+	result, err := Estimate(plan, createMockPriceList(), "us-east-1", &UsageEstimates{})
+	assert.NoError(t, err)
+	assert.Len(t, result.Resources, 1)
+	assert.InDelta(t, 10.0, result.TotalMonthlyCost, 0.001)
+	assert.Equal(t, "custom", result.Resources[0].CostBreakdown)
+}
+
+func TestEstimateWithCustomCalculatorAndDataTransfer(t *testing.T) {
+	registerCustomCalculator()
+
+	plan := &terraform.Plan{
+		ResourceChanges: []*terraform.ResourceChange{
+			{
+				Address: "test_custom_resource.a",
+				Type:    "test_custom_resource",
+				Change:  terraform.Change{Actions: []string{"create"}},
+				After:   map[string]interface{}{"units": float64(5)},
+			},
+		},
+	}
+	usage := &UsageEstimates{DataTransfer: []DataTransferFlow{{Source: "test_custom_resource.a", Destination: "internet", MonthlyGB: 100}}}
+
+	result, err := Estimate(plan, createDataTransferPriceList(), "us-east-1", usage)
+	assert.NoError(t, err)
+	if assert.Len(t, result.Resources, 1) {
+		components := result.Resources[0].Components
+		if assert.Len(t, components, 2) {
+			assert.Equal(t, "custom", components[0].Name)
+			assert.InDelta(t, 10.0, components[0].MonthlyCost, 0.001)
+		}
+		assert.InDelta(t, 10.0+100*0.09, result.TotalMonthlyCost, 0.001)
+	}
+}
@@ -0,0 +1,109 @@
+package estimator
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	MustRegister(NewCalculator("aws_cloudfront_distribution", []string{"AmazonCloudFront"}, nil, costForCloudFront))
+}
+
+// Usage types of CloudFront distributions, without the prefix of the edge region (e.g., "US-").
+const (
+	cloudFrontDataTransferOut = "DataTransfer-Out-Bytes"
+	cloudFrontHTTPRequests    = "Requests-Tier1"
+	cloudFrontHTTPSRequests   = "Requests-Tier2-HTTPS"
+)
+
+// cloudFrontPriceClasses maps the price_class attribute of a CloudFront distribution to the edge regions in the price
+// list that serve its viewers.
+var cloudFrontPriceClasses = map[string][]string{
+	"PriceClass_100": {"United States", "Canada", "Europe"},
+	"PriceClass_200": {"United States", "Canada", "Europe", "Japan", "Asia Pacific", "India", "Middle East", "South Africa"},
+	"PriceClass_All": {"United States", "Canada", "Europe", "Japan", "Asia Pacific", "India", "Middle East", "South Africa", "South America", "Australia"},
+}
+
+// costForCloudFront calculates the cost of an AWS CloudFront distribution.
+// Distributions are charged for the data transfer out and the HTTP and HTTPS requests in the usage estimates. The
+// share of traffic served by each edge region is not known, so the usage is priced at the rates of the most expensive
+// edge region in the distribution's price_class. Data transfer out is priced with its tiers.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its usage estimates may include the data transfer and
+//        requests of the distribution.
+//   attributes: The attributes of the CloudFront distribution resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the CloudFront distribution.
+//   An error if the price class is unknown or the pricing data cannot be found.
+func costForCloudFront(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	priceClass, _ := attributes["price_class"].(string)
+	if priceClass == "" {
+		priceClass = "PriceClass_All"
+	}
+	regions, ok := cloudFrontPriceClasses[priceClass]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown CloudFront price class %q", ErrMissingAttribute, priceClass)
+	}
+	usage := ctx.Usage
+	if usage == nil {
+		usage = &UsageEstimates{}
+	}
+
+	var components []CostComponent
+	add := func(name, unit, usageType string, quantity float64) error {
+		if quantity <= 0 {
+			return nil
+		}
+		region, sku, err := cloudFrontCostliestRegion(ctx, regions, usageType)
+		if err != nil {
+			return err
+		}
+		added, err := tieredComponents(ctx, fmt.Sprintf("%s in %s", name, region), unit, sku, quantity)
+		components = append(components, added...)
+		return err
+	}
+	if err := add("Data transfer out", "GB", cloudFrontDataTransferOut, float64(usage.CloudFront.MonthlyDataTransferGB)); err != nil {
+		return nil, err
+	}
+	if err := add("HTTP requests", "request", cloudFrontHTTPRequests, float64(usage.CloudFront.MonthlyHTTPRequests)); err != nil {
+		return nil, err
+	}
+	if err := add("HTTPS requests", "request", cloudFrontHTTPSRequests, float64(usage.CloudFront.MonthlyHTTPSRequests)); err != nil {
+		return nil, err
+	}
+
+	return newCost(components...), nil
+}
+
+// cloudFrontCostliestRegion finds the edge region with the highest price for a CloudFront usage type.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   regions: The edge regions in the price list (e.g., "United States" or "Europe").
+//   usageType: The usage type without the prefix of the edge region (e.g., "DataTransfer-Out-Bytes").
+//
+// Returns:
+//   The edge region with the highest price and the SKU of the usage type in that region.
+//   An error if the usage type has no price in any of the regions.
+func cloudFrontCostliestRegion(ctx *CalculationContext, regions []string, usageType string) (string, string, error) {
+	costliestRegion, costliestSKU, costliestPrice := "", "", -1.0
+	for _, region := range regions {
+		candidates := usageTypeSKUs(ctx, "AmazonCloudFront", region, usageType)
+		if len(candidates) == 0 {
+			continue
+		}
+		sku, price, err := ctx.MatchSKU(fmt.Sprintf("CloudFront %s in %s", usageType, region), candidates)
+		if err != nil {
+			return "", "", err
+		}
+		if price > costliestPrice {
+			costliestRegion, costliestSKU, costliestPrice = region, sku, price
+		}
+	}
+	if costliestSKU == "" {
+		return "", "", fmt.Errorf("%w for CloudFront %s in %s", ErrPriceNotFound, usageType, strings.Join(regions, ", "))
+	}
+	return costliestRegion, costliestSKU, nil
+}
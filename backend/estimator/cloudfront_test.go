@@ -0,0 +1,74 @@
+package estimator
+
+import (
+	"testing"
+
+	"cloudcostguard/backend/pricing"
+	"cloudcostguard/backend/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+// createCloudFrontPriceList creates a price list with CloudFront data transfer and request prices in three edge
+// regions.
+func createCloudFrontPriceList() *pricing.PriceList {
+	priceList := pricing.NewPriceList()
+	add := func(sku, region, usageType string, tiers ...[3]string) {
+		addTieredMockProduct(priceList, sku, pricing.ProductAttributes{ServiceCode: "AmazonCloudFront", Location: region, UsageType: usageType}, tiers...)
+	}
+	add("us-out", "United States", "US-DataTransfer-Out-Bytes", [3]string{"0", "10240", "0.085"}, [3]string{"10240", "Inf", "0.080"})
+	add("us-https", "United States", "US-Requests-Tier2-HTTPS", [3]string{"0", "Inf", "0.000001"})
+	add("eu-out", "Europe", "EU-DataTransfer-Out-Bytes", [3]string{"0", "10240", "0.085"}, [3]string{"10240", "Inf", "0.080"})
+	add("eu-https", "Europe", "EU-Requests-Tier2-HTTPS", [3]string{"0", "Inf", "0.0000012"})
+	add("sa-out", "South America", "SA-DataTransfer-Out-Bytes", [3]string{"0", "10240", "0.110"}, [3]string{"10240", "Inf", "0.105"})
+	add("sa-https", "South America", "SA-Requests-Tier2-HTTPS", [3]string{"0", "Inf", "0.0000022"})
+	add("sa-http", "South America", "SA-Requests-Tier1", [3]string{"0", "Inf", "0.0000016"})
+	add("sa-origin", "South America", "SA-DataTransfer-Out-OBytes", [3]string{"0", "Inf", "0.160"})
+	return priceList
+}
+
+func TestCloudFrontPricing(t *testing.T) {
+	priceList := createCloudFrontPriceList()
+	estimate := func(t *testing.T, usage *UsageEstimates, distribution map[string]interface{}) *EstimationResponse {
+		plan := &terraform.Plan{ResourceChanges: []*terraform.ResourceChange{{
+			Address: "aws_cloudfront_distribution.cdn",
+			Type:    "aws_cloudfront_distribution",
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   distribution,
+		}}}
+		result, err := Estimate(plan, priceList, "us-east-1", usage)
+		assert.NoError(t, err)
+		return result
+	}
+	usage := &UsageEstimates{CloudFront: CloudFrontUsage{MonthlyDataTransferGB: 20480, MonthlyHTTPSRequests: 10000000}}
+
+	t.Run("prices data transfer with tiers and requests in the price class", func(t *testing.T) {
+		result := estimate(t, usage, map[string]interface{}{"price_class": "PriceClass_100"})
+		if assert.Len(t, result.Resources, 1) {
+			costs := make(map[string]float64)
+			for _, component := range result.Resources[0].Components {
+				costs[component.Name] = component.MonthlyCost
+			}
+			assert.Len(t, costs, 3)
+			assert.InDelta(t, 10240*0.085, costs["Data transfer out in United States (first 10240 GB)"], 1e-9)
+			assert.InDelta(t, 10240*0.080, costs["Data transfer out in United States (over 10240 GB)"], 1e-9)
+			assert.InDelta(t, 12.0, costs["HTTPS requests in Europe"], 1e-9)
+		}
+	})
+
+	t.Run("prices the All price class at the most expensive edge region", func(t *testing.T) {
+		result := estimate(t, usage, map[string]interface{}{})
+		assert.InDelta(t, 10240*0.110+10240*0.105+22.0, result.TotalMonthlyCost, 1e-9)
+	})
+
+	t.Run("has no cost without usage estimates", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{}, map[string]interface{}{"price_class": "PriceClass_200"})
+		assert.Zero(t, result.TotalMonthlyCost)
+		assert.Equal(t, 1, result.Coverage.PricedResources)
+	})
+
+	t.Run("skips distributions with an unknown price class", func(t *testing.T) {
+		result := estimate(t, usage, map[string]interface{}{"price_class": "PriceClass_300"})
+		assert.Empty(t, result.Resources)
+		assert.Equal(t, SkipReasonMissingAttribute, result.Skipped[0].Reason)
+	})
+}
@@ -0,0 +1,151 @@
+package estimator
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"cloudcostguard/backend/pricing"
+)
+
+// newCost builds a monthly cost from its components.
+// The cost's value is the sum of the component costs, and its breakdown is derived from the components.
+//
+// Parameters:
+//   components: The priced line items of the cost.
+//
+// Returns:
+//   A pointer to a Cost struct holding the components.
+func newCost(components ...CostComponent) *Cost {
+	total := 0.0
+	for _, c := range components {
+		total += c.MonthlyCost
+	}
+	return &Cost{
+		Value:      total,
+		Unit:       "monthly",
+		Breakdown:  formatComponents(components),
+		Components: components,
+	}
+}
+
+// newComponent builds a cost component from a monthly quantity and a unit price.
+//
+// Parameters:
+//   name: The name of the line item (e.g., "Storage (gp3)").
+//   unit: The unit the line item is priced in (e.g., "GB-month").
+//   sku: The SKU the unit price comes from.
+//   monthlyQuantity: The number of units consumed per month.
+//   unitPrice: The price of a single unit.
+//
+// Returns:
+//   The cost component.
+func newComponent(name, unit, sku string, monthlyQuantity, unitPrice float64) CostComponent {
+	return CostComponent{
+		Name:            name,
+		Unit:            unit,
+		MonthlyQuantity: monthlyQuantity,
+		UnitPrice:       unitPrice,
+		SKU:             sku,
+		MonthlyCost:     monthlyQuantity * unitPrice,
+	}
+}
+
+// hourlyComponent builds a cost component for a resource billed per hour, running for the whole month.
+//
+// Parameters:
+//   name: The name of the line item (e.g., "Instance usage (t3.medium)").
+//   sku: The SKU the hourly price comes from.
+//   count: The number of resources billed, such as instances or nodes.
+//   hourlyPrice: The price of a single resource for one hour.
+//
+// Returns:
+//   The cost component.
+func hourlyComponent(name, sku string, count, hourlyPrice float64) CostComponent {
+	return newComponent(name, "hour", sku, count*hoursPerMonth, hourlyPrice)
+}
+
+// tieredComponents builds the cost components for a monthly quantity priced with the on-demand price tiers of a SKU.
+// A component is built for each tier the quantity reaches. When the SKU has more than one tier, the range of the
+// tier is appended to the component name, e.g. "Storage (Standard) (51200 to 512000 GB-month)".
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   name: The name of the line item (e.g., "Storage (Standard)").
+//   unit: The unit the line item is priced in (e.g., "GB-month").
+//   sku: The SKU of the tiered price.
+//   monthlyQuantity: The number of units consumed per month.
+//
+// Returns:
+//   The cost components, with at least one component even when the quantity is zero.
+//   An error if the SKU has no on-demand price.
+func tieredComponents(ctx *CalculationContext, name, unit, sku string, monthlyQuantity float64) ([]CostComponent, error) {
+	tiers, _, err := ctx.PriceList.OnDemandTiers(sku)
+	if err != nil {
+		return nil, fmt.Errorf("%w for %s: %v", ErrPriceNotFound, name, err)
+	}
+
+	usages := pricing.SplitTiers(tiers, monthlyQuantity)
+	if len(usages) == 0 {
+		return []CostComponent{newComponent(name, unit, sku, 0, tiers[0].Price)}, nil
+	}
+
+	components := make([]CostComponent, len(usages))
+	for i, usage := range usages {
+		tierName := name
+		if len(tiers) > 1 {
+			tierName = fmt.Sprintf("%s (%s)", name, formatTierRange(usage.Tier, unit))
+		}
+		components[i] = newComponent(tierName, unit, sku, usage.Quantity, usage.Tier.Price)
+	}
+	return components, nil
+}
+
+// formatTierRange renders the quantity range of a price tier, e.g. "first 51200 GB-month" or "over 512000 GB-month".
+func formatTierRange(tier pricing.Tier, unit string) string {
+	switch {
+	case tier.BeginRange == 0:
+		return fmt.Sprintf("first %s %s", formatQuantity(tier.EndRange), unit)
+	case math.IsInf(tier.EndRange, 1):
+		return fmt.Sprintf("over %s %s", formatQuantity(tier.BeginRange), unit)
+	default:
+		return fmt.Sprintf("%s to %s %s", formatQuantity(tier.BeginRange), formatQuantity(tier.EndRange), unit)
+	}
+}
+
+// formatComponents renders cost components as a single line, e.g. "Instance usage (t3.medium): 730 × $0.0416/hour".
+// Discounted components are followed by the discount rule that applied, e.g. "(10% off AmazonEC2)".
+//
+// Parameters:
+//   components: The cost components to render.
+//
+// Returns:
+//   The components joined with " + ".
+func formatComponents(components []CostComponent) string {
+	parts := make([]string, len(components))
+	for i, c := range components {
+		parts[i] = fmt.Sprintf("%s: %s × $%s/%s", c.Name, formatQuantity(c.MonthlyQuantity), formatPrice(c.UnitPrice), c.Unit)
+		if c.Discount != "" {
+			parts[i] += fmt.Sprintf(" (%s)", c.Discount)
+		}
+	}
+	return strings.Join(parts, " + ")
+}
+
+// formatQuantity renders a quantity without decimals when it is whole, and with two decimals otherwise.
+func formatQuantity(quantity float64) string {
+	if quantity == float64(int64(quantity)) {
+		return strconv.FormatInt(int64(quantity), 10)
+	}
+	return strconv.FormatFloat(quantity, 'f', 2, 64)
+}
+
+// formatPrice renders a unit price with at least four decimals, keeping the precision of sub-cent prices.
+func formatPrice(price float64) string {
+	s := strings.TrimRight(strconv.FormatFloat(price, 'f', 10, 64), "0")
+	if i := strings.Index(s, "."); len(s)-i-1 < 4 {
+		return strconv.FormatFloat(price, 'f', 4, 64)
+	}
+	return s
+}
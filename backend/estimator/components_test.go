@@ -0,0 +1,39 @@
+package estimator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCost(t *testing.T) {
+	t.Run("sums the component costs into a monthly cost", func(t *testing.T) {
+		cost := newCost(
+			hourlyComponent("Instance usage (t3.medium)", "sku-1", 2, 0.0416),
+			newComponent("Storage (gp3)", "GB-month", "sku-2", 100, 0.08),
+		)
+
+		assert.Equal(t, "monthly", cost.Unit)
+		assert.InDelta(t, 2*730*0.0416+100*0.08, cost.Monthly(), 0.0001)
+		assert.Len(t, cost.Components, 2)
+		assert.Equal(t, 1460.0, cost.Components[0].MonthlyQuantity)
+		assert.Equal(t, "hour", cost.Components[0].Unit)
+		assert.Equal(t, "Instance usage (t3.medium): 1460 × $0.0416/hour + Storage (gp3): 100 × $0.0800/GB-month", cost.Breakdown)
+	})
+
+	t.Run("returns a zero cost without components", func(t *testing.T) {
+		cost := newCost()
+		assert.Equal(t, 0.0, cost.Monthly())
+		assert.Empty(t, cost.Breakdown)
+	})
+}
+
+func TestFormatComponents(t *testing.T) {
+	t.Run("keeps the precision of sub-cent prices", func(t *testing.T) {
+		components := []CostComponent{
+			newComponent("Requests", "request", "", 2500000, 0.0000002),
+			newComponent("Duration", "GB-second", "", 1234.5, 0.0000166667),
+		}
+		assert.Equal(t, "Requests: 2500000 × $0.0000002/request + Duration: 1234.50 × $0.0000166667/GB-second", formatComponents(components))
+	})
+}
@@ -0,0 +1,247 @@
+package estimator
+
+import (
+	"fmt"
+	"strings"
+
+	"cloudcostguard/backend/pricing"
+	"cloudcostguard/backend/terraform"
+)
+
+func init() {
+	// Replication is charged to the source bucket, see s3ReplicationTransfers.
+	MustRegister(NewCalculator("aws_s3_bucket_replication_configuration", []string{"AWSDataTransfer"}, nil, zeroCost))
+}
+
+// dataTransferInternet is the destination of data transfer flows to the internet.
+const dataTransferInternet = "internet"
+
+// Transfer types and locations of the AWSDataTransfer products.
+const (
+	transferTypeInternetOut    = "AWS Outbound"
+	transferTypeInterRegionOut = "InterRegion Outbound"
+	transferTypeIntraRegion    = "IntraRegion"
+	transferLocationExternal   = "External"
+)
+
+// dataTransfer is a data transfer flow from the resource being priced.
+type dataTransfer struct {
+	// name is the name of the line items (e.g., "Data transfer" or "Replication data transfer").
+	name string
+	// destination is the address of the resource receiving the data, or "internet".
+	destination string
+	// gb is the GB of data sent per month.
+	gb float64
+	// crossAZ is true if the data is sent to another availability zone when the zones are not known.
+	crossAZ bool
+}
+
+// dataTransferComponents prices the data transfer flows whose source is the resource being priced: the flows
+// declared in the data_transfer usage estimates and, for S3 buckets, cross-region replication.
+// Flows that cannot be priced are reported as warnings and left out, so that the resource itself is still priced.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   attributes: The attributes of the source resource.
+//
+// Returns:
+//   The cost components of the data transfer, or none if the resource sends no priced data.
+func dataTransferComponents(ctx *CalculationContext, attributes map[string]interface{}) []CostComponent {
+	if ctx.ResourceChange == nil {
+		return nil
+	}
+	var transfers []dataTransfer
+	if ctx.Usage != nil {
+		for _, flow := range ctx.Usage.DataTransfer {
+			if flow.MonthlyGB > 0 && matchesDataTransferSource(flow.Source, ctx.ResourceChange.Address) {
+				transfers = append(transfers, dataTransfer{name: "Data transfer", destination: flow.Destination, gb: flow.MonthlyGB, crossAZ: flow.CrossAZ})
+			}
+		}
+	}
+	transfers = append(transfers, s3ReplicationTransfers(ctx, attributes)...)
+
+	var components []CostComponent
+	for _, transfer := range transfers {
+		priced, err := priceDataTransfer(ctx, attributes, transfer)
+		if err != nil {
+			ctx.AddWarning(Warning{
+				Code:    WarningDataTransferNotPriced,
+				Message: fmt.Sprintf("data transfer to %s is not priced: %v", transfer.destination, err),
+			})
+			continue
+		}
+		components = append(components, priced...)
+	}
+	return components
+}
+
+// priceDataTransfer prices a data transfer flow by the regions, and availability zones, of its source and
+// destination. Data sent to the internet or to another region is priced with its price tiers. Data sent to another
+// availability zone in the same region is charged both when it leaves the source and when it enters the
+// destination. Data sent within an availability zone is free.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   attributes: The attributes of the source resource.
+//   transfer: The data transfer flow to price.
+//
+// Returns:
+//   The cost components of the flow, or none if it is free.
+//   An error if the destination is not in the plan or the pricing data cannot be found.
+func priceDataTransfer(ctx *CalculationContext, attributes map[string]interface{}, transfer dataTransfer) ([]CostComponent, error) {
+	from := resourceLocation(ctx, attributes)
+	if strings.EqualFold(transfer.destination, dataTransferInternet) {
+		sku, err := dataTransferSKU(ctx, transferTypeInternetOut, from, transferLocationExternal)
+		if err != nil {
+			return nil, err
+		}
+		return tieredComponents(ctx, transfer.name+" out to internet", "GB", sku, transfer.gb)
+	}
+
+	destination, ok := planResourceAttributes(ctx.Plan, transfer.destination)
+	if !ok {
+		return nil, fmt.Errorf("%w: destination %s is not in the plan", ErrMissingAttribute, transfer.destination)
+	}
+	to := resourceLocation(ctx, destination)
+	if to != from {
+		sku, err := dataTransferSKU(ctx, transferTypeInterRegionOut, from, to)
+		if err != nil {
+			return nil, err
+		}
+		return tieredComponents(ctx, fmt.Sprintf("%s to %s in %s", transfer.name, transfer.destination, to), "GB", sku, transfer.gb)
+	}
+
+	crossAZ := transfer.crossAZ
+	if fromZone, toZone := resourceZone(attributes), resourceZone(destination); fromZone != "" && toZone != "" {
+		crossAZ = fromZone != toZone
+	}
+	if !crossAZ {
+		return nil, nil
+	}
+	sku, err := dataTransferSKU(ctx, transferTypeIntraRegion, from, from)
+	if err != nil {
+		return nil, err
+	}
+	price, err := getPriceFromTerms(sku, ctx.PriceList)
+	if err != nil {
+		return nil, err
+	}
+	name := "Inter-AZ " + strings.ToLower(transfer.name[:1]) + transfer.name[1:]
+	return []CostComponent{
+		newComponent(fmt.Sprintf("%s out to %s", name, transfer.destination), "GB", sku, transfer.gb, price),
+		newComponent(fmt.Sprintf("%s in to %s", name, transfer.destination), "GB", sku, transfer.gb, price),
+	}, nil
+}
+
+// s3ReplicationTransfers lists the buckets that the objects of an S3 bucket are replicated to by the enabled rules
+// of its aws_s3_bucket_replication_configuration. Each destination receives the GB in the s3.replication_monthly_gb
+// usage estimate of the bucket.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   attributes: The attributes of the resource being priced.
+//
+// Returns:
+//   The replication flows, or none if the resource is not a replicated S3 bucket.
+func s3ReplicationTransfers(ctx *CalculationContext, attributes map[string]interface{}) []dataTransfer {
+	bucket, _ := attributes["bucket"].(string)
+	if ctx.ResourceChange.Type != "aws_s3_bucket" || bucket == "" || ctx.Usage == nil || ctx.Usage.S3.ReplicationMonthlyGB <= 0 || ctx.Plan == nil {
+		return nil
+	}
+
+	var transfers []dataTransfer
+	seen := make(map[string]bool)
+	for _, rc := range ctx.Plan.ResourceChanges {
+		configuration := resourceAttributes(rc)
+		if rc.Type != "aws_s3_bucket_replication_configuration" || configuration["bucket"] != bucket {
+			continue
+		}
+		for _, rule := range blocks(configuration, "rule") {
+			if status, _ := rule["status"].(string); status == "Disabled" {
+				continue
+			}
+			arn, _ := firstBlock(rule, "destination")["bucket"].(string)
+			destination := strings.TrimPrefix(arn, "arn:aws:s3:::")
+			if destination == "" || seen[destination] {
+				continue
+			}
+			seen[destination] = true
+			transfers = append(transfers, dataTransfer{name: "Replication data transfer", destination: s3BucketAddress(ctx.Plan, destination), gb: float64(ctx.Usage.S3.ReplicationMonthlyGB)})
+		}
+	}
+	return transfers
+}
+
+// s3BucketAddress finds the address of the aws_s3_bucket resource of a bucket in the plan.
+//
+// Parameters:
+//   plan: The Terraform plan.
+//   bucket: The name of the bucket.
+//
+// Returns:
+//   The address of the bucket resource, or the bucket name if the bucket is not in the plan.
+func s3BucketAddress(plan *terraform.Plan, bucket string) string {
+	for _, rc := range plan.ResourceChanges {
+		if rc.Type == "aws_s3_bucket" && !rc.IsData() && resourceAttributes(rc)["bucket"] == bucket {
+			return rc.Address
+		}
+	}
+	return bucket
+}
+
+// dataTransferSKU finds the product for data transfer between two locations.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   transferType: The transfer type of the product (e.g., "InterRegion Outbound").
+//   from: The AWS pricing location the data is sent from.
+//   to: The AWS pricing location the data is sent to, or "External" for the internet.
+//
+// Returns:
+//   The SKU of the product.
+//   An error if the pricing data cannot be found.
+func dataTransferSKU(ctx *CalculationContext, transferType, from, to string) (string, error) {
+	candidates := filterSKUs(ctx.PriceList, ctx.PriceList.Index().Lookup("AWSDataTransfer", "", pricing.AttrFromLocation, from), func(attr pricing.ProductAttributes) bool {
+		return attr.TransferType == transferType && attr.ToLocation == to
+	})
+	sku, _, err := ctx.MatchSKU(fmt.Sprintf("data transfer from %s to %s", from, to), candidates)
+	return sku, err
+}
+
+// matchesDataTransferSource reports whether a data transfer flow declared for a source address applies to a
+// resource. A source without an instance key applies to every instance of the resource.
+func matchesDataTransferSource(source, address string) bool {
+	return source == address || source == trimInstanceKey(address)
+}
+
+// unmatchedDataTransfer warns about the declared data transfer flows whose source is not in the plan.
+//
+// Parameters:
+//   plan: The Terraform plan.
+//   usage: The usage estimates declaring the flows.
+//
+// Returns:
+//   A warning for each flow that is not charged to any resource.
+func unmatchedDataTransfer(plan *terraform.Plan, usage *UsageEstimates) []Warning {
+	if usage == nil {
+		return nil
+	}
+	var warnings []Warning
+	for _, flow := range usage.DataTransfer {
+		matched := false
+		for _, rc := range plan.ResourceChanges {
+			if !rc.IsData() && matchesDataTransferSource(flow.Source, rc.Address) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			warnings = append(warnings, Warning{
+				Address: flow.Source,
+				Code:    WarningDataTransferNotPriced,
+				Message: fmt.Sprintf("data transfer to %s is not priced: source %s is not in the plan", flow.Destination, flow.Source),
+			})
+		}
+	}
+	return warnings
+}
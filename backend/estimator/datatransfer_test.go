@@ -0,0 +1,150 @@
+package estimator
+
+import (
+	"testing"
+
+	"cloudcostguard/backend/pricing"
+	"cloudcostguard/backend/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+// createDataTransferPriceList creates a price list with internet, inter-region and inter-AZ data transfer prices,
+// and the Elastic IP and S3 prices of the resources sending the data.
+func createDataTransferPriceList() *pricing.PriceList {
+	usEast, euWest := "US East (N. Virginia)", "EU (Ireland)"
+	priceList := pricing.NewPriceList()
+	addTransfer := func(sku, transferType, from, to string, tiers ...[3]string) {
+		addTieredMockProduct(priceList, sku, pricing.ProductAttributes{ServiceCode: "AWSDataTransfer", TransferType: transferType, FromLocation: from, ToLocation: to}, tiers...)
+	}
+	addTransfer("internet-out", "AWS Outbound", usEast, "External", [3]string{"0", "10240", "0.09"}, [3]string{"10240", "51200", "0.085"}, [3]string{"51200", "Inf", "0.07"})
+	addTransfer("inter-region-out", "InterRegion Outbound", usEast, euWest, [3]string{"0", "Inf", "0.02"})
+	addTransfer("intra-region", "IntraRegion", usEast, usEast, [3]string{"0", "Inf", "0.01"})
+	addTransfer("internet-in", "AWS Inbound", "External", usEast, [3]string{"0", "Inf", "0"})
+
+	addTieredMockProduct(priceList, "ipv4", pricing.ProductAttributes{ServiceCode: "AmazonVPC", Location: usEast, UsageType: "USE1-PublicIPv4:InUseAddress"}, [3]string{"0", "Inf", "0.005"})
+	addMockProduct(priceList, "s3-storage", pricing.ProductAttributes{ServiceCode: "AmazonS3", Location: usEast, StorageClass: "General Purpose", UsageType: "TimedStorage-ByteHrs"}, "0.023")
+	addMockProduct(priceList, "s3-put", pricing.ProductAttributes{ServiceCode: "AmazonS3", Location: usEast, Group: "S3-Request-Tier1"}, "0.005")
+	return priceList
+}
+
+func TestDataTransferPricing(t *testing.T) {
+	priceList := createDataTransferPriceList()
+	const ipv4 = 0.005 * 730
+	resource := func(resourceType, name string, after map[string]interface{}) *terraform.ResourceChange {
+		return &terraform.ResourceChange{
+			Address: resourceType + "." + name,
+			Type:    resourceType,
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   after,
+		}
+	}
+	estimate := func(t *testing.T, usage *UsageEstimates, resources ...*terraform.ResourceChange) *EstimationResponse {
+		result, err := Estimate(&terraform.Plan{ResourceChanges: resources}, priceList, "us-east-1", usage)
+		assert.NoError(t, err)
+		return result
+	}
+	costs := func(resource ResourceCost) map[string]float64 {
+		costs := make(map[string]float64)
+		for _, component := range resource.Components {
+			costs[component.Name] = component.MonthlyCost
+		}
+		return costs
+	}
+
+	t.Run("charges data transfer to the internet to the source with tiers", func(t *testing.T) {
+		usage := &UsageEstimates{DataTransfer: []DataTransferFlow{{Source: "aws_eip.web", Destination: "internet", MonthlyGB: 20480}}}
+		result := estimate(t, usage, resource("aws_eip", "web", map[string]interface{}{}))
+		if assert.Len(t, result.Resources, 1) {
+			costs := costs(result.Resources[0])
+			assert.InDelta(t, 10240*0.09, costs["Data transfer out to internet (first 10240 GB)"], 1e-9)
+			assert.InDelta(t, 10240*0.085, costs["Data transfer out to internet (10240 to 51200 GB)"], 1e-9)
+			assert.InDelta(t, ipv4+10240*0.09+10240*0.085, result.TotalMonthlyCost, 1e-9)
+		}
+		assert.Empty(t, result.Warnings)
+	})
+
+	t.Run("charges data transfer to a resource in another region", func(t *testing.T) {
+		usage := &UsageEstimates{DataTransfer: []DataTransferFlow{{Source: "aws_eip.web", Destination: "aws_eip.eu", MonthlyGB: 1000}}}
+		result := estimate(t, usage,
+			resource("aws_eip", "web", map[string]interface{}{}),
+			resource("aws_eip", "eu", map[string]interface{}{"region": "eu-west-1"}),
+		)
+		if assert.Len(t, result.Resources, 2) {
+			assert.InDelta(t, 1000*0.02, costs(result.Resources[0])["Data transfer to aws_eip.eu in EU (Ireland)"], 1e-9)
+			assert.InDelta(t, ipv4, result.Resources[1].MonthlyCost, 1e-9)
+		}
+	})
+
+	t.Run("charges data transfer between availability zones in both directions", func(t *testing.T) {
+		usage := &UsageEstimates{DataTransfer: []DataTransferFlow{{Source: "aws_eip.web", Destination: "aws_eip.db", MonthlyGB: 500}}}
+		web := resource("aws_eip", "web", map[string]interface{}{"availability_zone": "us-east-1a"})
+		result := estimate(t, usage, web, resource("aws_eip", "db", map[string]interface{}{"availability_zone": "us-east-1b"}))
+		if assert.Len(t, result.Resources, 2) {
+			costs := costs(result.Resources[0])
+			assert.InDelta(t, 5.0, costs["Inter-AZ data transfer out to aws_eip.db"], 1e-9)
+			assert.InDelta(t, 5.0, costs["Inter-AZ data transfer in to aws_eip.db"], 1e-9)
+		}
+
+		result = estimate(t, usage, web, resource("aws_eip", "db", map[string]interface{}{"availability_zone": "us-east-1a"}))
+		assert.InDelta(t, 2*ipv4, result.TotalMonthlyCost, 1e-9)
+
+		// The availability zone of the destination is not known, so the flow declares whether it crosses zones.
+		usage.DataTransfer[0].CrossAZ = true
+		result = estimate(t, usage, web, resource("aws_eip", "db", map[string]interface{}{}))
+		assert.InDelta(t, 2*ipv4+10.0, result.TotalMonthlyCost, 1e-9)
+	})
+
+	t.Run("charges S3 cross-region replication to the source bucket", func(t *testing.T) {
+		usage := &UsageEstimates{S3: S3Usage{ReplicationMonthlyGB: 300}}
+		result := estimate(t, usage,
+			resource("aws_s3_bucket", "data", map[string]interface{}{"bucket": "data"}),
+			resource("aws_s3_bucket", "replica", map[string]interface{}{"bucket": "data-replica", "region": "eu-west-1"}),
+			resource("aws_s3_bucket_replication_configuration", "data", map[string]interface{}{
+				"bucket": "data",
+				"rule": []interface{}{
+					map[string]interface{}{
+						"status":      "Enabled",
+						"destination": []interface{}{map[string]interface{}{"bucket": "arn:aws:s3:::data-replica"}},
+					},
+					map[string]interface{}{
+						"status":      "Disabled",
+						"destination": []interface{}{map[string]interface{}{"bucket": "arn:aws:s3:::data-archive"}},
+					},
+				},
+			}),
+		)
+		if assert.Len(t, result.Resources, 1) {
+			assert.Equal(t, "aws_s3_bucket.data", result.Resources[0].Address)
+			assert.InDelta(t, 300*0.02, costs(result.Resources[0])["Replication data transfer to aws_s3_bucket.replica in EU (Ireland)"], 1e-9)
+		}
+		assert.Equal(t, 3, result.Coverage.PricedResources)
+		assert.Empty(t, result.Warnings)
+	})
+
+	t.Run("removes the data transfer of deleted resources", func(t *testing.T) {
+		usage := &UsageEstimates{DataTransfer: []DataTransferFlow{{Source: "aws_eip.web", Destination: "internet", MonthlyGB: 100}}}
+		deleted := &terraform.ResourceChange{
+			Address: "aws_eip.web",
+			Type:    "aws_eip",
+			Change:  terraform.Change{Actions: []string{"delete"}},
+			Before:  map[string]interface{}{},
+		}
+		result := estimate(t, usage, deleted)
+		assert.InDelta(t, -(ipv4 + 100*0.09), result.TotalMonthlyCost, 1e-9)
+	})
+
+	t.Run("warns about flows that cannot be priced", func(t *testing.T) {
+		usage := &UsageEstimates{DataTransfer: []DataTransferFlow{
+			{Source: "aws_eip.web", Destination: "aws_eip.missing", MonthlyGB: 100},
+			{Source: "aws_eip.gone", Destination: "internet", MonthlyGB: 100},
+		}}
+		result := estimate(t, usage, resource("aws_eip", "web", map[string]interface{}{}))
+		assert.InDelta(t, ipv4, result.TotalMonthlyCost, 1e-9)
+		if assert.Len(t, result.Warnings, 2) {
+			assert.Equal(t, "aws_eip.web", result.Warnings[0].Address)
+			assert.Equal(t, WarningDataTransferNotPriced, result.Warnings[0].Code)
+			assert.Contains(t, result.Warnings[0].Message, "aws_eip.missing is not in the plan")
+			assert.Equal(t, "aws_eip.gone", result.Warnings[1].Address)
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package estimator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DiscountPolicy describes the discounts an organization receives on AWS list prices, such as an Enterprise Discount
+// Program (EDP) percentage or private pricing for specific products.
+// At most one rule applies to each cost component, in order of precedence:
+//
+//  1. A price override for the SKU of the component.
+//  2. A price override for the instance type or instance class of the component's product.
+//  3. The percentage discount for the service of the component's product.
+//  4. The percentage discount on all services.
+type DiscountPolicy struct {
+	// Percent is the percentage discount on all services (e.g., 5 for 5% off).
+	Percent float64 `yaml:"percent,omitempty" json:"percent,omitempty"`
+	// Services maps AWS service codes (e.g., "AmazonEC2") to a percentage discount on that service.
+	Services map[string]float64 `yaml:"services,omitempty" json:"services,omitempty"`
+	// Overrides lists explicit unit prices for specific SKUs or instance types.
+	Overrides []PriceOverride `yaml:"overrides,omitempty" json:"overrides,omitempty"`
+}
+
+// PriceOverride replaces the list unit price of the products matching a SKU or an instance type.
+type PriceOverride struct {
+	// SKU is the SKU of the product to override.
+	SKU string `yaml:"sku,omitempty" json:"sku,omitempty"`
+	// InstanceType is the instance type or instance class of the products to override (e.g., "m5.large" or "db.r5.large").
+	InstanceType string `yaml:"instance_type,omitempty" json:"instance_type,omitempty"`
+	// UnitPrice is the price per unit of the component (e.g., per hour for instance usage), in USD.
+	UnitPrice float64 `yaml:"unit_price" json:"unit_price"`
+}
+
+// Validate checks that the discount policy is well-formed.
+//
+// Returns:
+//   An error describing the first invalid rule, or nil if the discount policy is valid.
+func (p *DiscountPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	if err := validatePercent(p.Percent); err != nil {
+		return err
+	}
+
+	services := make([]string, 0, len(p.Services))
+	for service := range p.Services {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+	for _, service := range services {
+		if err := validatePercent(p.Services[service]); err != nil {
+			return fmt.Errorf("service %s: %w", service, err)
+		}
+	}
+
+	for i, override := range p.Overrides {
+		if (override.SKU == "") == (override.InstanceType == "") {
+			return fmt.Errorf("override %d: exactly one of sku and instance_type must be set", i)
+		}
+		if override.UnitPrice < 0 {
+			return fmt.Errorf("override %d: unit price %g must not be negative", i, override.UnitPrice)
+		}
+	}
+	return nil
+}
+
+// validatePercent checks that a discount percentage is between 0 and 100.
+func validatePercent(percent float64) error {
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("discount percentage %g must be between 0 and 100", percent)
+	}
+	return nil
+}
+
+// applyDiscounts prices the components of a cost under the discount policy of the calculation context.
+// Discounted components keep their list price in ListUnitPrice and ListMonthlyCost and name the rule that applied.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   cost: The cost of the resource at list prices.
+//
+// Returns:
+//   The cost of the resource after discounts. It is the cost passed in if no rule applies to any component.
+func applyDiscounts(ctx *CalculationContext, cost *Cost) *Cost {
+	if ctx.Discounts == nil {
+		return cost
+	}
+
+	components := make([]CostComponent, len(cost.Components))
+	discounted := false
+	for i, component := range cost.Components {
+		components[i] = component
+		unitPrice, rule, ok := ctx.discountedPrice(component)
+		if !ok {
+			continue
+		}
+		discounted = true
+		components[i].UnitPrice = unitPrice
+		components[i].MonthlyCost = component.MonthlyQuantity * unitPrice
+		components[i].ListUnitPrice = component.UnitPrice
+		components[i].ListMonthlyCost = component.MonthlyCost
+		components[i].Discount = rule
+	}
+	if !discounted {
+		return cost
+	}
+
+	discountedCost := newCost(components...)
+	discountedCost.Commitment = cost.Commitment
+	return discountedCost
+}
+
+// discountedPrice finds the discount rule that applies to a cost component.
+// Price overrides replace the on-demand unit price of a SKU, so they only apply to components priced at the on-demand
+// term of their SKU. Spot instance hours and components priced under a reserved term or Savings Plan only get the
+// percentage discounts.
+//
+// Parameters:
+//   component: The cost component at list prices.
+//
+// Returns:
+//   The discounted unit price, a description of the rule that applied, and true if a rule applies.
+func (ctx *CalculationContext) discountedPrice(component CostComponent) (float64, string, bool) {
+	policy := ctx.Discounts
+	if component.SKU == "" {
+		return 0, "", false
+	}
+	_, onDemandTerm, _ := getOnDemandTerm(component.SKU, ctx.PriceList)
+	overridable := !component.spot && component.OfferTermCode == onDemandTerm
+	for _, override := range policy.Overrides {
+		if override.SKU == component.SKU && overridable {
+			return override.UnitPrice, "price override for SKU " + override.SKU, true
+		}
+	}
+
+	product, ok := ctx.PriceList.Products[component.SKU]
+	if !ok {
+		return 0, "", false
+	}
+	attributes := product.Attributes
+	for _, override := range policy.Overrides {
+		if override.InstanceType == "" || !overridable {
+			continue
+		}
+		if strings.EqualFold(override.InstanceType, attributes.InstanceType) || strings.EqualFold(override.InstanceType, attributes.InstanceClass) {
+			return override.UnitPrice, "price override for " + override.InstanceType, true
+		}
+	}
+
+	if percent, ok := policy.Services[attributes.ServiceCode]; ok {
+		return component.UnitPrice * (1 - percent/100), fmt.Sprintf("%g%% off %s", percent, attributes.ServiceCode), true
+	}
+	if policy.Percent > 0 {
+		return component.UnitPrice * (1 - policy.Percent/100), fmt.Sprintf("%g%% off all services", policy.Percent), true
+	}
+	return 0, "", false
+}
@@ -0,0 +1,135 @@
+package estimator
+
+import (
+	"testing"
+
+	"cloudcostguard/backend/pricing"
+	"cloudcostguard/backend/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateDiscounts(t *testing.T) {
+	priceList := createMockPriceList()
+	plan := &terraform.Plan{
+		ResourceChanges: []*terraform.ResourceChange{
+			{
+				Address: "aws_instance.web",
+				Type:    "aws_instance",
+				Change:  terraform.Change{Actions: []string{"create"}},
+				After:   map[string]interface{}{"instance_type": "t2.micro"},
+			},
+			{
+				Address: "aws_nat_gateway.nat",
+				Type:    "aws_nat_gateway",
+				Change:  terraform.Change{Actions: []string{"create"}},
+				After:   map[string]interface{}{},
+			},
+		},
+	}
+	reservedPrices := createMockPriceList()
+	hourly := pricing.PriceDimension{Unit: "Hrs"}
+	hourly.PricePerUnit.USD = "6.0"
+	reservedPrices.Terms.Reserved = map[string]map[string]pricing.Term{
+		"ec2-t2-micro-sku": {"ec2-t2-micro-sku.4NA7Y494T4": {
+			OfferTermCode:   "4NA7Y494T4",
+			PriceDimensions: map[string]pricing.PriceDimension{"hourly": hourly},
+			TermAttributes:  pricing.TermAttributes{LeaseContractLength: "1yr", PurchaseOption: "No Upfront", OfferingClass: "standard"},
+		}},
+	}
+
+	estimate := func(t *testing.T, policy *DiscountPolicy) *EstimationResponse {
+		result, err := EstimateWithOptions(plan, priceList, "us-east-1", &UsageEstimates{}, Options{Discounts: policy})
+		assert.NoError(t, err)
+		assert.Len(t, result.Resources, 2)
+		return result
+	}
+
+	t.Run("uses list prices without a policy", func(t *testing.T) {
+		result := estimate(t, nil)
+		assert.InDelta(t, result.TotalMonthlyCost, result.ListMonthlyCost, 0.001)
+		assert.Empty(t, result.Resources[0].Components[0].Discount)
+		assert.Zero(t, result.Resources[0].Components[0].ListUnitPrice)
+	})
+
+	t.Run("applies a percentage discount to every service", func(t *testing.T) {
+		result := estimate(t, &DiscountPolicy{Percent: 10})
+		instance := result.Resources[0]
+		assert.InDelta(t, 9.0*730, instance.MonthlyCost, 0.01)
+		assert.InDelta(t, 10.0*730, instance.ListMonthlyCost, 0.01)
+		assert.Equal(t, "10% off all services", instance.Components[0].Discount)
+		assert.InDelta(t, 10.0, instance.Components[0].ListUnitPrice, 1e-9)
+		assert.InDelta(t, 10.0*730, instance.Components[0].ListMonthlyCost, 0.01)
+		assert.Contains(t, instance.CostBreakdown, "(10% off all services)")
+		assert.InDelta(t, (9.0+0.0405)*730, result.TotalMonthlyCost, 0.01)
+		assert.InDelta(t, (10.0+0.045)*730, result.ListMonthlyCost, 0.01)
+	})
+
+	t.Run("prefers the service discount over the global one", func(t *testing.T) {
+		result := estimate(t, &DiscountPolicy{Percent: 10, Services: map[string]float64{"AmazonVPC": 20}})
+		assert.Equal(t, "10% off all services", result.Resources[0].Components[0].Discount)
+		assert.Equal(t, "20% off AmazonVPC", result.Resources[1].Components[0].Discount)
+		assert.InDelta(t, 0.036*730, result.Resources[1].MonthlyCost, 0.01)
+	})
+
+	t.Run("prefers price overrides over percentage discounts", func(t *testing.T) {
+		result := estimate(t, &DiscountPolicy{
+			Services: map[string]float64{"AmazonEC2": 20, "AmazonVPC": 20},
+			Overrides: []PriceOverride{
+				{InstanceType: "T2.MICRO", UnitPrice: 7},
+				{SKU: "nat-gateway-sku", UnitPrice: 0.03},
+			},
+		})
+		assert.Equal(t, "price override for T2.MICRO", result.Resources[0].Components[0].Discount)
+		assert.InDelta(t, 7.0*730, result.Resources[0].MonthlyCost, 0.01)
+		assert.Equal(t, "price override for SKU nat-gateway-sku", result.Resources[1].Components[0].Discount)
+		assert.InDelta(t, 0.03*730, result.Resources[1].MonthlyCost, 0.01)
+	})
+
+	t.Run("discounts the reserved price of resources with a reserved pricing model", func(t *testing.T) {
+		opts := Options{
+			PricingModels: map[string]PricingModel{"aws_instance": {Term: PricingTermReserved}},
+			Discounts:     &DiscountPolicy{Percent: 50},
+		}
+		result, err := EstimateWithOptions(plan, reservedPrices, "us-east-1", &UsageEstimates{}, opts)
+		assert.NoError(t, err)
+		instance := result.Resources[0]
+		assert.InDelta(t, 3.0*730, instance.MonthlyCost, 0.01)
+		assert.InDelta(t, 6.0*730, instance.ListMonthlyCost, 0.01)
+		assert.Equal(t, "4NA7Y494T4", instance.Components[0].OfferTermCode)
+		assert.NotNil(t, instance.Commitment)
+	})
+
+	t.Run("does not override the reserved price of resources with a reserved pricing model", func(t *testing.T) {
+		opts := Options{
+			PricingModels: map[string]PricingModel{"aws_instance": {Term: PricingTermReserved}},
+			Discounts:     &DiscountPolicy{Overrides: []PriceOverride{{InstanceType: "t2.micro", UnitPrice: 7}, {SKU: "ec2-t2-micro-sku", UnitPrice: 5}}},
+		}
+		result, err := EstimateWithOptions(plan, reservedPrices, "us-east-1", &UsageEstimates{}, opts)
+		assert.NoError(t, err)
+		instance := result.Resources[0]
+		assert.InDelta(t, 6.0*730, instance.MonthlyCost, 0.01)
+		assert.Empty(t, instance.Components[0].Discount)
+		assert.Zero(t, instance.Components[0].ListUnitPrice)
+		assert.Equal(t, "4NA7Y494T4", instance.Components[0].OfferTermCode)
+	})
+}
+
+func TestDiscountPolicyValidate(t *testing.T) {
+	t.Run("accepts valid policies", func(t *testing.T) {
+		var policy *DiscountPolicy
+		assert.NoError(t, policy.Validate())
+		assert.NoError(t, (&DiscountPolicy{
+			Percent:   5,
+			Services:  map[string]float64{"AmazonEC2": 12.5},
+			Overrides: []PriceOverride{{SKU: "ABC", UnitPrice: 0.1}, {InstanceType: "m5.large", UnitPrice: 0.08}},
+		}).Validate())
+	})
+
+	t.Run("rejects invalid policies", func(t *testing.T) {
+		assert.EqualError(t, (&DiscountPolicy{Percent: 120}).Validate(), "discount percentage 120 must be between 0 and 100")
+		assert.EqualError(t, (&DiscountPolicy{Services: map[string]float64{"AmazonEC2": -1}}).Validate(), "service AmazonEC2: discount percentage -1 must be between 0 and 100")
+		assert.EqualError(t, (&DiscountPolicy{Overrides: []PriceOverride{{UnitPrice: 1}}}).Validate(), "override 0: exactly one of sku and instance_type must be set")
+		assert.EqualError(t, (&DiscountPolicy{Overrides: []PriceOverride{{SKU: "ABC", InstanceType: "m5.large"}}}).Validate(), "override 0: exactly one of sku and instance_type must be set")
+		assert.EqualError(t, (&DiscountPolicy{Overrides: []PriceOverride{{SKU: "ABC", UnitPrice: -1}}}).Validate(), "override 0: unit price -1 must not be negative")
+	})
+}
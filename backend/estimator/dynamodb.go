@@ -0,0 +1,251 @@
+package estimator
+
+import (
+	"fmt"
+)
+
+func init() {
+	MustRegister(NewCalculator("aws_dynamodb_table", []string{"AmazonDynamoDB"}, nil, costForDynamoDBTable))
+	// Auto scaling targets change the capacity of the tables they scale, which is priced with the table.
+	MustRegister(NewCalculator("aws_appautoscaling_target", nil, nil, zeroCost))
+}
+
+// DynamoDB usage types, without the region prefix AWS adds outside us-east-1 (e.g., "USE2-").
+const (
+	dynamoDBReadCapacity          = "ReadCapacityUnit-Hrs"
+	dynamoDBWriteCapacity         = "WriteCapacityUnit-Hrs"
+	dynamoDBReplWriteCapacity     = "ReplWriteCapacityUnit-Hrs"
+	dynamoDBReadRequests          = "ReadRequestUnits"
+	dynamoDBWriteRequests         = "WriteRequestUnits"
+	dynamoDBReplWriteRequests     = "ReplWriteRequestUnits"
+	dynamoDBStorage               = "TimedStorage-ByteHrs"
+	dynamoDBPITRStorage           = "TimedPITRStorage-ByteHrs"
+	dynamoDBStreamReadRequests    = "Streams-ReadRequestUnits"
+	dynamoDBInfrequentAccessClass = "STANDARD_INFREQUENT_ACCESS"
+)
+
+// dynamoDBIndex is a global secondary index of a DynamoDB table.
+type dynamoDBIndex struct {
+	Name          string
+	ReadCapacity  float64
+	WriteCapacity float64
+}
+
+// costForDynamoDBTable calculates the cost of an AWS DynamoDB table.
+// Tables in provisioned mode are charged for the read and write capacity of the table and its global secondary
+// indexes, adjusted to the bounds of any aws_appautoscaling_target in the plan that scales them. Tables in
+// PAY_PER_REQUEST mode are charged for the read and write requests in the usage estimates; every write is also
+// charged for each global secondary index. Storage, point-in-time recovery and stream reads are priced from the usage
+// estimates, and every replica region of a global table is charged for replicated writes and its own storage.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its usage estimates may include the storage and requests
+//        of the table.
+//   attributes: The attributes of the DynamoDB table resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the DynamoDB table.
+//   An error if the billing mode is unknown or the pricing data cannot be found.
+func costForDynamoDBTable(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	billingMode, _ := attributes["billing_mode"].(string)
+	if billingMode == "" {
+		billingMode = "PROVISIONED"
+	}
+	if billingMode != "PROVISIONED" && billingMode != "PAY_PER_REQUEST" {
+		return nil, fmt.Errorf("%w: unknown DynamoDB billing mode %q", ErrMissingAttribute, billingMode)
+	}
+	usage := ctx.Usage
+	if usage == nil {
+		usage = &UsageEstimates{}
+	}
+
+	// Tables of the Standard-Infrequent Access class have their own capacity, request and storage prices.
+	classPrefix := ""
+	if tableClass, _ := attributes["table_class"].(string); tableClass == dynamoDBInfrequentAccessClass {
+		classPrefix = "IA-"
+	}
+	tableName, _ := attributes["name"].(string)
+	indexes := dynamoDBIndexes(ctx, tableName, attributes)
+	var replicaRegions []string
+	for _, replica := range blocks(attributes, "replica") {
+		if region, _ := replica["region_name"].(string); region != "" {
+			replicaRegions = append(replicaRegions, region)
+		}
+	}
+
+	// Global tables are charged for replicated writes in every region, including the one the table is created in.
+	writeCapacityUsageType, writeRequestsUsageType, writeLabel := dynamoDBWriteCapacity, dynamoDBWriteRequests, "Write"
+	if len(replicaRegions) > 0 {
+		writeCapacityUsageType, writeRequestsUsageType, writeLabel = dynamoDBReplWriteCapacity, dynamoDBReplWriteRequests, "Replicated write"
+	}
+
+	// regionComponents prices the capacity or requests and the storage of the table in one region.
+	regionComponents := func(location, suffix string) ([]CostComponent, error) {
+		var components []CostComponent
+		add := func(name, unit, usageType string, quantity float64) error {
+			added, err := dynamoDBComponents(ctx, location, name+suffix, unit, usageType, quantity)
+			components = append(components, added...)
+			return err
+		}
+
+		if billingMode == "PROVISIONED" {
+			readCapacity, _ := attributes["read_capacity"].(float64)
+			writeCapacity, _ := attributes["write_capacity"].(float64)
+			readCapacity = dynamoDBScaledCapacity(ctx, "table/"+tableName, "dynamodb:table:ReadCapacityUnits", readCapacity)
+			writeCapacity = dynamoDBScaledCapacity(ctx, "table/"+tableName, "dynamodb:table:WriteCapacityUnits", writeCapacity)
+			if err := add("Read capacity", "RCU-hour", classPrefix+dynamoDBReadCapacity, readCapacity*hoursPerMonth); err != nil {
+				return nil, err
+			}
+			if err := add(writeLabel+" capacity", "WCU-hour", classPrefix+writeCapacityUsageType, writeCapacity*hoursPerMonth); err != nil {
+				return nil, err
+			}
+			for _, index := range indexes {
+				if err := add(fmt.Sprintf("Read capacity (index %s)", index.Name), "RCU-hour", classPrefix+dynamoDBReadCapacity, index.ReadCapacity*hoursPerMonth); err != nil {
+					return nil, err
+				}
+				if err := add(fmt.Sprintf("%s capacity (index %s)", writeLabel, index.Name), "WCU-hour", classPrefix+writeCapacityUsageType, index.WriteCapacity*hoursPerMonth); err != nil {
+					return nil, err
+				}
+			}
+		} else {
+			// Reads are only charged in the region that serves them, which is assumed to be the table's own region.
+			if suffix == "" {
+				if err := add("Read request units", "RRU", classPrefix+dynamoDBReadRequests, float64(usage.DynamoDB.MonthlyReadRequests)); err != nil {
+					return nil, err
+				}
+			}
+			writes := float64(usage.DynamoDB.MonthlyWriteRequests)
+			if err := add(writeLabel+" request units", "WRU", classPrefix+writeRequestsUsageType, writes); err != nil {
+				return nil, err
+			}
+			for _, index := range indexes {
+				if err := add(fmt.Sprintf("%s request units (index %s)", writeLabel, index.Name), "WRU", classPrefix+writeRequestsUsageType, writes); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if err := add("Storage", "GB-month", classPrefix+dynamoDBStorage, float64(usage.DynamoDB.StorageGB)); err != nil {
+			return nil, err
+		}
+		return components, nil
+	}
+
+	components, err := regionComponents(ctx.Location, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if recovery := firstBlock(attributes, "point_in_time_recovery"); recovery != nil {
+		if enabled, _ := recovery["enabled"].(bool); enabled {
+			pitr, err := dynamoDBComponents(ctx, ctx.Location, "Point-in-time recovery", "GB-month", dynamoDBPITRStorage, float64(usage.DynamoDB.StorageGB))
+			if err != nil {
+				return nil, err
+			}
+			components = append(components, pitr...)
+		}
+	}
+	if streamEnabled, _ := attributes["stream_enabled"].(bool); streamEnabled {
+		streams, err := dynamoDBComponents(ctx, ctx.Location, "Stream read request units", "request", dynamoDBStreamReadRequests, float64(usage.DynamoDB.MonthlyStreamReadRequests))
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, streams...)
+	}
+
+	for _, region := range replicaRegions {
+		replica, err := regionComponents(toLocation(region), fmt.Sprintf(" (replica %s)", region))
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, replica...)
+	}
+
+	return newCost(components...), nil
+}
+
+// dynamoDBIndexes reads the global secondary indexes of a DynamoDB table, with their capacity adjusted to the bounds
+// of any aws_appautoscaling_target in the plan that scales them.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   tableName: The name of the table.
+//   attributes: The attributes of the DynamoDB table resource.
+//
+// Returns:
+//   The global secondary indexes of the table.
+func dynamoDBIndexes(ctx *CalculationContext, tableName string, attributes map[string]interface{}) []dynamoDBIndex {
+	var indexes []dynamoDBIndex
+	for _, block := range blocks(attributes, "global_secondary_index") {
+		index := dynamoDBIndex{}
+		index.Name, _ = block["name"].(string)
+		index.ReadCapacity, _ = block["read_capacity"].(float64)
+		index.WriteCapacity, _ = block["write_capacity"].(float64)
+		resourceID := fmt.Sprintf("table/%s/index/%s", tableName, index.Name)
+		index.ReadCapacity = dynamoDBScaledCapacity(ctx, resourceID, "dynamodb:index:ReadCapacityUnits", index.ReadCapacity)
+		index.WriteCapacity = dynamoDBScaledCapacity(ctx, resourceID, "dynamodb:index:WriteCapacityUnits", index.WriteCapacity)
+		indexes = append(indexes, index)
+	}
+	return indexes
+}
+
+// dynamoDBScaledCapacity adjusts the provisioned capacity of a DynamoDB table or index to the bounds of the
+// aws_appautoscaling_target in the plan that scales it. Capacity that is not set is assumed to be at the minimum.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   resourceID: The resource ID of the scaled table or index (e.g., "table/orders" or "table/orders/index/by-date").
+//   dimension: The scalable dimension (e.g., "dynamodb:table:ReadCapacityUnits").
+//   capacity: The capacity set on the table or index.
+//
+// Returns:
+//   The capacity within the bounds of the auto scaling target, or the capacity passed in if there is none.
+func dynamoDBScaledCapacity(ctx *CalculationContext, resourceID, dimension string, capacity float64) float64 {
+	if ctx.Plan == nil {
+		return capacity
+	}
+	for _, rc := range ctx.Plan.ResourceChanges {
+		if rc.Type != "aws_appautoscaling_target" || rc.After == nil {
+			continue
+		}
+		id, _ := rc.After["resource_id"].(string)
+		scalableDimension, _ := rc.After["scalable_dimension"].(string)
+		if id != resourceID || scalableDimension != dimension {
+			continue
+		}
+		minCapacity, _ := rc.After["min_capacity"].(float64)
+		maxCapacity, _ := rc.After["max_capacity"].(float64)
+		if capacity < minCapacity {
+			return minCapacity
+		}
+		if maxCapacity > 0 && capacity > maxCapacity {
+			return maxCapacity
+		}
+		return capacity
+	}
+	return capacity
+}
+
+// dynamoDBComponents prices a monthly quantity of a DynamoDB usage type with its price tiers.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   location: The AWS pricing location of the region the usage is charged in.
+//   name: The name of the line item (e.g., "Read capacity").
+//   unit: The unit the line item is priced in (e.g., "RCU-hour").
+//   usageType: The usage type without a region prefix (e.g., "ReadCapacityUnit-Hrs").
+//   monthlyQuantity: The number of units consumed per month.
+//
+// Returns:
+//   The cost components, or none if the quantity is zero.
+//   An error if the pricing data cannot be found.
+func dynamoDBComponents(ctx *CalculationContext, location, name, unit, usageType string, monthlyQuantity float64) ([]CostComponent, error) {
+	if monthlyQuantity <= 0 {
+		return nil, nil
+	}
+	sku, _, err := ctx.MatchSKU(fmt.Sprintf("DynamoDB %s in %s", usageType, location), usageTypeSKUs(ctx, "AmazonDynamoDB", location, usageType))
+	if err != nil {
+		return nil, err
+	}
+	return tieredComponents(ctx, name, unit, sku, monthlyQuantity)
+}
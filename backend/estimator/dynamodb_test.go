@@ -0,0 +1,153 @@
+package estimator
+
+import (
+	"testing"
+
+	"cloudcostguard/backend/pricing"
+	"cloudcostguard/backend/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+// createDynamoDBPriceList creates a price list with DynamoDB prices in US East (N. Virginia) and US West (Oregon).
+func createDynamoDBPriceList() *pricing.PriceList {
+	priceList := pricing.NewPriceList()
+	add := func(sku, location, usageType, price string) {
+		addMockProduct(priceList, sku, pricing.ProductAttributes{ServiceCode: "AmazonDynamoDB", Location: location, UsageType: usageType}, price)
+	}
+	usEast, usWest := "US East (N. Virginia)", "US West (Oregon)"
+	add("rcu", usEast, "ReadCapacityUnit-Hrs", "0.00013")
+	add("wcu", usEast, "WriteCapacityUnit-Hrs", "0.00065")
+	add("ia-rcu", usEast, "IA-ReadCapacityUnit-Hrs", "0.00016")
+	add("ia-wcu", usEast, "IA-WriteCapacityUnit-Hrs", "0.00081")
+	add("repl-wcu", usEast, "ReplWriteCapacityUnit-Hrs", "0.000975")
+	add("rru", usEast, "ReadRequestUnits", "0.00000025")
+	add("wru", usEast, "WriteRequestUnits", "0.00000125")
+	add("pitr", usEast, "TimedPITRStorage-ByteHrs", "0.20")
+	add("streams", usEast, "Streams-ReadRequestUnits", "0.0000002")
+	add("repl-wcu-west", usWest, "USW2-ReplWriteCapacityUnit-Hrs", "0.000975")
+	add("rcu-west", usWest, "USW2-ReadCapacityUnit-Hrs", "0.00013")
+	add("storage-west", usWest, "USW2-TimedStorage-ByteHrs", "0.25")
+
+	// Storage is free for the first 25 GB.
+	free := pricing.PriceDimension{BeginRange: "0", EndRange: "25", Unit: "GB-Mo"}
+	free.PricePerUnit.USD = "0"
+	paid := pricing.PriceDimension{BeginRange: "25", EndRange: "Inf", Unit: "GB-Mo"}
+	paid.PricePerUnit.USD = "0.25"
+	priceList.Products["storage"] = pricing.Product{SKU: "storage", Attributes: pricing.ProductAttributes{ServiceCode: "AmazonDynamoDB", Location: usEast, UsageType: "TimedStorage-ByteHrs"}}
+	priceList.Terms.OnDemand["storage"] = map[string]pricing.Term{
+		"storage.JRTCKXETXF": {PriceDimensions: map[string]pricing.PriceDimension{"free": free, "paid": paid}},
+	}
+	return priceList
+}
+
+func TestDynamoDBPricing(t *testing.T) {
+	priceList := createDynamoDBPriceList()
+	estimate := func(t *testing.T, usage *UsageEstimates, table map[string]interface{}, others ...*terraform.ResourceChange) *EstimationResponse {
+		plan := &terraform.Plan{ResourceChanges: append(others, &terraform.ResourceChange{
+			Address: "aws_dynamodb_table.orders",
+			Type:    "aws_dynamodb_table",
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   table,
+		})}
+		result, err := Estimate(plan, priceList, "us-east-1", usage)
+		assert.NoError(t, err)
+		return result
+	}
+	componentCosts := func(result *EstimationResponse) map[string]float64 {
+		costs := make(map[string]float64)
+		for _, component := range result.Resources[0].Components {
+			costs[component.Name] = component.MonthlyCost
+		}
+		return costs
+	}
+
+	t.Run("prices provisioned capacity of the table and its indexes", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{}, map[string]interface{}{
+			"name":           "orders",
+			"read_capacity":  float64(100),
+			"write_capacity": float64(20),
+			"global_secondary_index": []interface{}{
+				map[string]interface{}{"name": "by-date", "read_capacity": float64(10), "write_capacity": float64(5)},
+			},
+		})
+		costs := componentCosts(result)
+		assert.Len(t, costs, 4)
+		assert.InDelta(t, 100*730*0.00013, costs["Read capacity"], 1e-9)
+		assert.InDelta(t, 20*730*0.00065, costs["Write capacity"], 1e-9)
+		assert.InDelta(t, 10*730*0.00013, costs["Read capacity (index by-date)"], 1e-9)
+		assert.InDelta(t, 5*730*0.00065, costs["Write capacity (index by-date)"], 1e-9)
+	})
+
+	t.Run("adjusts provisioned capacity to auto scaling targets", func(t *testing.T) {
+		target := &terraform.ResourceChange{
+			Address: "aws_appautoscaling_target.orders_read",
+			Type:    "aws_appautoscaling_target",
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After: map[string]interface{}{
+				"resource_id":        "table/orders",
+				"scalable_dimension": "dynamodb:table:ReadCapacityUnits",
+				"min_capacity":       float64(50),
+				"max_capacity":       float64(500),
+			},
+		}
+		result := estimate(t, &UsageEstimates{}, map[string]interface{}{
+			"name":           "orders",
+			"read_capacity":  float64(5),
+			"write_capacity": float64(5),
+		}, target)
+		assert.InDelta(t, 50*730*0.00013, componentCosts(result)["Read capacity"], 1e-9)
+		assert.Equal(t, 2, result.Coverage.PricedResources)
+	})
+
+	t.Run("prices on-demand requests, storage, backups and streams from usage estimates", func(t *testing.T) {
+		usage := &UsageEstimates{
+			DynamoDB: DynamoDBUsage{StorageGB: 125, MonthlyReadRequests: 40000000, MonthlyWriteRequests: 8000000, MonthlyStreamReadRequests: 1000000},
+		}
+		result := estimate(t, usage, map[string]interface{}{
+			"name":                   "orders",
+			"billing_mode":           "PAY_PER_REQUEST",
+			"stream_enabled":         true,
+			"point_in_time_recovery": []interface{}{map[string]interface{}{"enabled": true}},
+			"global_secondary_index": []interface{}{map[string]interface{}{"name": "by-date"}},
+		})
+		costs := componentCosts(result)
+		assert.InDelta(t, 10.0, costs["Read request units"], 1e-9)
+		assert.InDelta(t, 10.0, costs["Write request units"], 1e-9)
+		assert.InDelta(t, 10.0, costs["Write request units (index by-date)"], 1e-9)
+		assert.InDelta(t, 0.0, costs["Storage (first 25 GB-month)"], 1e-9)
+		assert.InDelta(t, 25.0, costs["Storage (over 25 GB-month)"], 1e-9)
+		assert.InDelta(t, 25.0, costs["Point-in-time recovery"], 1e-9)
+		assert.InDelta(t, 0.2, costs["Stream read request units"], 1e-9)
+	})
+
+	t.Run("prices the Standard-Infrequent Access table class", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{}, map[string]interface{}{
+			"name":           "archive",
+			"table_class":    "STANDARD_INFREQUENT_ACCESS",
+			"read_capacity":  float64(10),
+			"write_capacity": float64(10),
+		})
+		assert.Equal(t, "ia-rcu", result.Resources[0].Components[0].SKU)
+		assert.Equal(t, "ia-wcu", result.Resources[0].Components[1].SKU)
+	})
+
+	t.Run("charges replicated writes and storage in every replica region", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{DynamoDB: DynamoDBUsage{StorageGB: 10}}, map[string]interface{}{
+			"name":           "orders",
+			"read_capacity":  float64(10),
+			"write_capacity": float64(10),
+			"replica":        []interface{}{map[string]interface{}{"region_name": "us-west-2"}},
+		})
+		costs := componentCosts(result)
+		assert.InDelta(t, 10*730*0.000975, costs["Replicated write capacity"], 1e-9)
+		assert.InDelta(t, 10*730*0.000975, costs["Replicated write capacity (replica us-west-2)"], 1e-9)
+		assert.InDelta(t, 10*730*0.00013, costs["Read capacity (replica us-west-2)"], 1e-9)
+		assert.InDelta(t, 10*0.25, costs["Storage (replica us-west-2)"], 1e-9)
+	})
+
+	t.Run("skips tables with an unknown billing mode", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{}, map[string]interface{}{"billing_mode": "ON_DEMAND"})
+		assert.Empty(t, result.Resources)
+		assert.Equal(t, SkipReasonMissingAttribute, result.Skipped[0].Reason)
+	})
+}
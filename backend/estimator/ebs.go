@@ -0,0 +1,326 @@
+package estimator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"cloudcostguard/backend/pricing"
+)
+
+func init() {
+	MustRegister(NewCalculator("aws_ebs_volume", []string{"AmazonEC2"}, []string{"size"}, costForEBS))
+}
+
+// ebsVolumeTypes is the set of EBS volume types that can be priced.
+var ebsVolumeTypes = map[string]bool{
+	"standard": true,
+	"gp2":      true,
+	"gp3":      true,
+	"io1":      true,
+	"io2":      true,
+	"st1":      true,
+	"sc1":      true,
+}
+
+const (
+	// gp3BaselineIOPS is the number of IOPS included in the price of gp3 storage.
+	gp3BaselineIOPS = 3000
+	// gp3BaselineThroughput is the throughput in MiB/s included in the price of gp3 storage.
+	gp3BaselineThroughput = 125
+)
+
+// ebsVolume describes the EBS storage of a volume or an instance block device.
+type ebsVolume struct {
+	// Label prefixes the component names (e.g., "Root volume"); it is empty for aws_ebs_volume resources.
+	Label string
+	// Type is the EBS volume type (e.g., "gp3").
+	Type string
+	// Size is the size of the volume in GB.
+	Size float64
+	// IOPS is the number of provisioned IOPS.
+	IOPS float64
+	// Throughput is the provisioned throughput in MiB/s.
+	Throughput float64
+}
+
+// costForEBS calculates the cost of an AWS EBS volume.
+// Volumes are charged for their storage, and io1, io2 and gp3 volumes for the IOPS provisioned above what is
+// included in the storage price. gp3 volumes are also charged for throughput provisioned above 125 MiB/s.
+// Volumes whose size is not positive are treated as missing their size.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   attributes: The attributes of the EBS volume resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the EBS volume.
+//   An error if the size is missing, the volume type is unknown or the pricing data cannot be found.
+func costForEBS(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	volume := newEBSVolume("", attributes, "type", "size")
+	if volume.Size <= 0 {
+		return nil, missingAttributeError("size")
+	}
+	components, err := ebsVolumeComponents(ctx, volume)
+	if err != nil {
+		return nil, err
+	}
+	return newCost(components...), nil
+}
+
+// newEBSVolume reads an EBS volume from the attributes of a volume resource or a block device block.
+//
+// Parameters:
+//   label: The prefix of the component names.
+//   attributes: The attributes of the volume or block device.
+//   typeAttribute: The name of the volume type attribute ("type" or "volume_type").
+//   sizeAttribute: The name of the size attribute ("size" or "volume_size").
+//
+// Returns:
+//   The EBS volume. Volumes without a type are gp2 volumes.
+func newEBSVolume(label string, attributes map[string]interface{}, typeAttribute, sizeAttribute string) ebsVolume {
+	volume := ebsVolume{Label: label}
+	volume.Type, _ = attributes[typeAttribute].(string)
+	if volume.Type == "" {
+		volume.Type = "gp2"
+	}
+	volume.Size, _ = attributes[sizeAttribute].(float64)
+	volume.IOPS, _ = attributes["iops"].(float64)
+	volume.Throughput, _ = attributes["throughput"].(float64)
+	return volume
+}
+
+// ebsVolumeComponents prices the storage, provisioned IOPS and provisioned throughput of an EBS volume.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   volume: The EBS volume to price.
+//
+// Returns:
+//   The cost components of the volume.
+//   An error if the volume type is unknown or the pricing data cannot be found.
+func ebsVolumeComponents(ctx *CalculationContext, volume ebsVolume) ([]CostComponent, error) {
+	if !ebsVolumeTypes[volume.Type] {
+		return nil, fmt.Errorf("%w: unknown EBS volume type %q", ErrMissingAttribute, volume.Type)
+	}
+
+	candidates := ebsProducts(ctx, volume.Type, func(usageType string) bool {
+		return usageType == "" || strings.Contains(usageType, "VolumeUsage")
+	})
+	sku, price, err := ctx.MatchSKU("EBS volume type: "+volume.Type, candidates)
+	if err != nil {
+		return nil, err
+	}
+	components := []CostComponent{newComponent(volume.name("Storage"), "GB-month", sku, volume.Size, price)}
+
+	iops, err := ebsIOPSComponents(ctx, volume)
+	if err != nil {
+		return nil, err
+	}
+	components = append(components, iops...)
+
+	if volume.Type == "gp3" && volume.Throughput > gp3BaselineThroughput {
+		throughput, err := ebsThroughputComponent(ctx, volume)
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, throughput)
+	}
+	return components, nil
+}
+
+// ebsIOPSComponents prices the provisioned IOPS of an EBS volume.
+// io1 volumes are charged for every provisioned IOPS, io2 volumes for every provisioned IOPS in tiers,
+// and gp3 volumes for the IOPS above the 3,000 included in the storage price.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   volume: The EBS volume to price.
+//
+// Returns:
+//   The cost components of the provisioned IOPS, or none if the volume is not charged for IOPS.
+//   An error if the pricing data cannot be found.
+func ebsIOPSComponents(ctx *CalculationContext, volume ebsVolume) ([]CostComponent, error) {
+	iops := volume.IOPS
+	if volume.Type == "gp3" {
+		iops -= gp3BaselineIOPS
+	}
+	if iops <= 0 || (volume.Type != "io1" && volume.Type != "io2" && volume.Type != "gp3") {
+		return nil, nil
+	}
+
+	candidates := ebsProducts(ctx, volume.Type, func(usageType string) bool {
+		return strings.Contains(usageType, "VolumeP-IOPS")
+	})
+	if volume.Type == "io2" {
+		return io2IOPSComponents(ctx, volume, candidates, iops)
+	}
+	sku, price, err := ctx.MatchSKU("EBS IOPS: "+volume.Type, candidates)
+	if err != nil {
+		return nil, err
+	}
+	return []CostComponent{newComponent(volume.name("Provisioned IOPS"), "IOPS-month", sku, iops, price)}, nil
+}
+
+// io2IOPSComponents prices the provisioned IOPS of an io2 volume in tiers.
+// AWS publishes the io2 IOPS tiers as ranged price dimensions, either of a single product or of one product per tier,
+// so the tiers of every io2 IOPS product are combined by the start of their range. When several products publish a
+// tier starting at the same quantity, the one with the lowest SKU wins.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   volume: The io2 volume to price.
+//   candidates: The SKUs of the io2 IOPS products.
+//   iops: The number of provisioned IOPS.
+//
+// Returns:
+//   The cost components of the provisioned IOPS, one per tier used.
+//   An error if no io2 IOPS product has a price.
+func io2IOPSComponents(ctx *CalculationContext, volume ebsVolume, candidates []string, iops float64) ([]CostComponent, error) {
+	sort.Strings(candidates)
+	var tiers []pricing.Tier
+	skus := make(map[float64]string)
+	for _, sku := range candidates {
+		productTiers, _, err := ctx.PriceList.OnDemandTiers(sku)
+		if err != nil {
+			continue
+		}
+		for _, tier := range productTiers {
+			if _, ok := skus[tier.BeginRange]; ok {
+				continue
+			}
+			skus[tier.BeginRange] = sku
+			tiers = append(tiers, tier)
+		}
+	}
+	if len(tiers) == 0 {
+		return nil, fmt.Errorf("%w for EBS IOPS: %s", ErrPriceNotFound, volume.Type)
+	}
+	sort.Slice(tiers, func(i, j int) bool {
+		return tiers[i].BeginRange < tiers[j].BeginRange
+	})
+
+	name := volume.name("Provisioned IOPS")
+	usages := pricing.SplitTiers(tiers, iops)
+	components := make([]CostComponent, len(usages))
+	for i, usage := range usages {
+		tierName := name
+		if len(usages) > 1 {
+			tierName = fmt.Sprintf("%s (%s)", name, formatTierRange(usage.Tier, "IOPS"))
+		}
+		components[i] = newComponent(tierName, "IOPS-month", skus[usage.Tier.BeginRange], usage.Quantity, usage.Tier.Price)
+	}
+	return components, nil
+}
+
+// ebsThroughputComponent prices the throughput of a gp3 volume above the 125 MiB/s included in the storage price.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   volume: The gp3 volume to price.
+//
+// Returns:
+//   The cost component of the provisioned throughput.
+//   An error if the pricing data cannot be found.
+func ebsThroughputComponent(ctx *CalculationContext, volume ebsVolume) (CostComponent, error) {
+	sku, price, err := ctx.MatchSKU("EBS throughput: "+volume.Type, ebsProducts(ctx, volume.Type, func(usageType string) bool {
+		return strings.Contains(usageType, "VolumeP-Throughput")
+	}))
+	if err != nil {
+		return CostComponent{}, err
+	}
+
+	// AWS publishes the throughput price per GiB/s-month.
+	quantity := volume.Throughput - gp3BaselineThroughput
+	if tiers, _, err := ctx.PriceList.OnDemandTiers(sku); err == nil && len(tiers) > 0 && strings.HasPrefix(tiers[0].Unit, "GiBps") {
+		price /= 1024
+	}
+	return newComponent(volume.name("Provisioned throughput"), "MiBps-month", sku, quantity, price), nil
+}
+
+// ebsProducts returns the EBS products of a volume type whose usage type matches a predicate.
+func ebsProducts(ctx *CalculationContext, volumeType string, usageType func(string) bool) []string {
+	candidates := ctx.PriceList.Index().Lookup("AmazonEC2", ctx.Location, pricing.AttrVolumeAPIName, volumeType)
+	return filterSKUs(ctx.PriceList, candidates, func(attr pricing.ProductAttributes) bool {
+		return usageType(attr.UsageType)
+	})
+}
+
+// name builds the name of a component of the volume, e.g. "Storage (gp3)" or "Root volume storage (gp3)".
+func (v ebsVolume) name(component string) string {
+	if v.Label == "" {
+		return fmt.Sprintf("%s (%s)", component, v.Type)
+	}
+	return fmt.Sprintf("%s %s (%s)", v.Label, strings.ToLower(component[:1])+component[1:], v.Type)
+}
+
+// instanceBlockDeviceComponents prices the EBS volumes attached to an EC2 instance through its root_block_device
+// and ebs_block_device blocks. Block devices without a known size are left out.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   attributes: The attributes of the EC2 instance resource.
+//
+// Returns:
+//   The cost components of the block devices.
+//   An error if a volume type is unknown or the pricing data cannot be found.
+func instanceBlockDeviceComponents(ctx *CalculationContext, attributes map[string]interface{}) ([]CostComponent, error) {
+	return ebsVolumesComponents(ctx, instanceBlockDevices(attributes))
+}
+
+// instanceBlockDevices reads the EBS volumes of the root_block_device and ebs_block_device blocks of an EC2 instance
+// or launch configuration.
+func instanceBlockDevices(attributes map[string]interface{}) []ebsVolume {
+	var volumes []ebsVolume
+	for _, device := range blocks(attributes, "root_block_device") {
+		volumes = append(volumes, newEBSVolume("Root volume", device, "volume_type", "volume_size"))
+	}
+	for _, device := range blocks(attributes, "ebs_block_device") {
+		volumes = append(volumes, newEBSVolume(blockDeviceLabel(device), device, "volume_type", "volume_size"))
+	}
+	return volumes
+}
+
+// blockDeviceLabel builds the label of a block device from its device name, e.g. "Block device /dev/sdf".
+func blockDeviceLabel(device map[string]interface{}) string {
+	if deviceName, _ := device["device_name"].(string); deviceName != "" {
+		return "Block device " + deviceName
+	}
+	return "Block device"
+}
+
+// ebsVolumesComponents prices several EBS volumes. Volumes without a positive size are left out.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   volumes: The EBS volumes to price.
+//
+// Returns:
+//   The cost components of the volumes.
+//   An error if a volume type is unknown or the pricing data cannot be found.
+func ebsVolumesComponents(ctx *CalculationContext, volumes []ebsVolume) ([]CostComponent, error) {
+	var components []CostComponent
+	for _, volume := range volumes {
+		if volume.Size <= 0 {
+			continue
+		}
+		volumeComponents, err := ebsVolumeComponents(ctx, volume)
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, volumeComponents...)
+	}
+	return components, nil
+}
+
+// blocks returns the nested blocks of a resource attribute, which Terraform plans encode as a list of objects.
+func blocks(attributes map[string]interface{}, name string) []map[string]interface{} {
+	list, _ := attributes[name].([]interface{})
+	var result []map[string]interface{}
+	for _, item := range list {
+		if block, ok := item.(map[string]interface{}); ok {
+			result = append(result, block)
+		}
+	}
+	return result
+}
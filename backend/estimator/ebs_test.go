@@ -0,0 +1,179 @@
+package estimator
+
+import (
+	"testing"
+
+	"cloudcostguard/backend/pricing"
+	"cloudcostguard/backend/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+// addEBSPrices adds EBS storage, IOPS and throughput prices to a price list.
+func addEBSPrices(priceList *pricing.PriceList) {
+	add := func(sku, volumeType, usageType, unit, price string) {
+		priceList.Products[sku] = pricing.Product{SKU: sku, Attributes: pricing.ProductAttributes{
+			ServiceCode:   "AmazonEC2",
+			Location:      "US East (N. Virginia)",
+			VolumeAPIName: volumeType,
+			UsageType:     usageType,
+		}}
+		dim := pricing.PriceDimension{Unit: unit}
+		dim.PricePerUnit.USD = price
+		priceList.Terms.OnDemand[sku] = map[string]pricing.Term{
+			sku + ".JRTCKXETXF": {OfferTermCode: "JRTCKXETXF", PriceDimensions: map[string]pricing.PriceDimension{"dim": dim}},
+		}
+	}
+	add("gp2", "gp2", "EBS:VolumeUsage.gp2", "GB-Mo", "0.10")
+	add("gp3", "gp3", "EBS:VolumeUsage.gp3", "GB-Mo", "0.08")
+	add("gp3-iops", "gp3", "EBS:VolumeP-IOPS.gp3", "IOPS-Mo", "0.005")
+	add("gp3-throughput", "gp3", "EBS:VolumeP-Throughput.gp3", "GiBps-mo", "40.96")
+	add("io1", "io1", "EBS:VolumeUsage.piops", "GB-Mo", "0.125")
+	add("io1-iops", "io1", "EBS:VolumeP-IOPS.piops", "IOPS-Mo", "0.065")
+	add("io2", "io2", "EBS:VolumeUsage.io2", "GB-Mo", "0.125")
+	// AWS publishes each io2 IOPS tier as a product with a ranged price dimension.
+	addIO2IOPS := func(sku, usageType string, tier [3]string) {
+		addTieredMockProduct(priceList, sku, pricing.ProductAttributes{
+			ServiceCode:   "AmazonEC2",
+			Location:      "US East (N. Virginia)",
+			VolumeAPIName: "io2",
+			UsageType:     usageType,
+		}, tier)
+	}
+	addIO2IOPS("io2-iops", "EBS:VolumeP-IOPS.io2", [3]string{"0", "32000", "0.065"})
+	addIO2IOPS("io2-iops-tier2", "EBS:VolumeP-IOPS.io2.tier2", [3]string{"32000", "64000", "0.0455"})
+	addIO2IOPS("io2-iops-tier3", "EBS:VolumeP-IOPS.io2.tier3", [3]string{"64000", "Inf", "0.032"})
+	add("st1", "st1", "EBS:VolumeUsage.st1", "GB-Mo", "0.045")
+	add("sc1", "sc1", "EBS:VolumeUsage.sc1", "GB-Mo", "0.015")
+	add("standard", "standard", "EBS:VolumeUsage", "GB-Mo", "0.05")
+}
+
+func TestEBSPricing(t *testing.T) {
+	priceList := createEC2PriceList()
+	addEBSPrices(priceList)
+	estimate := func(t *testing.T, resourceType string, after map[string]interface{}) *EstimationResponse {
+		plan := &terraform.Plan{ResourceChanges: []*terraform.ResourceChange{{
+			Address: resourceType + ".test",
+			Type:    resourceType,
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   after,
+		}}}
+		result, err := Estimate(plan, priceList, "us-east-1", &UsageEstimates{})
+		assert.NoError(t, err)
+		return result
+	}
+	componentCosts := func(result *EstimationResponse) map[string]float64 {
+		costs := make(map[string]float64)
+		for _, component := range result.Resources[0].Components {
+			costs[component.Name] = component.MonthlyCost
+		}
+		return costs
+	}
+
+	t.Run("prices the storage of every volume type", func(t *testing.T) {
+		prices := map[string]float64{"standard": 0.05, "gp2": 0.10, "gp3": 0.08, "io1": 0.125, "io2": 0.125, "st1": 0.045, "sc1": 0.015}
+		for volumeType, price := range prices {
+			result := estimate(t, "aws_ebs_volume", map[string]interface{}{"type": volumeType, "size": float64(500)})
+			if assert.Len(t, result.Resources, 1, volumeType) {
+				assert.Equal(t, volumeType, result.Resources[0].Components[0].SKU)
+				assert.InDelta(t, price*500, result.TotalMonthlyCost, 0.001, volumeType)
+			}
+		}
+	})
+
+	t.Run("skips volumes of an unknown type", func(t *testing.T) {
+		result := estimate(t, "aws_ebs_volume", map[string]interface{}{"type": "gp4", "size": float64(10)})
+		assert.Empty(t, result.Resources)
+		assert.Equal(t, SkipReasonMissingAttribute, result.Skipped[0].Reason)
+	})
+
+	t.Run("skips volumes without a size", func(t *testing.T) {
+		result := estimate(t, "aws_ebs_volume", map[string]interface{}{"type": "gp3", "snapshot_id": "snap-0123456789abcdef0"})
+		assert.Empty(t, result.Resources)
+		assert.Equal(t, 0, result.Coverage.PricedResources)
+		if assert.Len(t, result.Skipped, 1) {
+			assert.Equal(t, SkipReasonMissingAttribute, result.Skipped[0].Reason)
+		}
+
+		for _, size := range []float64{0, -10} {
+			result = estimate(t, "aws_ebs_volume", map[string]interface{}{"type": "gp3", "size": size})
+			assert.Empty(t, result.Resources)
+			if assert.Len(t, result.Skipped, 1) {
+				assert.Equal(t, SkipReasonMissingAttribute, result.Skipped[0].Reason)
+				assert.Equal(t, "missing attribute: size", result.Skipped[0].Message)
+			}
+		}
+	})
+
+	t.Run("charges gp3 IOPS and throughput above the baseline", func(t *testing.T) {
+		result := estimate(t, "aws_ebs_volume", map[string]interface{}{"type": "gp3", "size": float64(100), "iops": float64(6000), "throughput": float64(250)})
+		assert.Equal(t, map[string]float64{
+			"Storage (gp3)":                100 * 0.08,
+			"Provisioned IOPS (gp3)":       3000 * 0.005,
+			"Provisioned throughput (gp3)": 125 * 0.04,
+		}, componentCosts(result))
+
+		result = estimate(t, "aws_ebs_volume", map[string]interface{}{"type": "gp3", "size": float64(100), "iops": float64(3000), "throughput": float64(125)})
+		assert.Len(t, result.Resources[0].Components, 1)
+	})
+
+	t.Run("charges every provisioned IOPS of io1 volumes", func(t *testing.T) {
+		result := estimate(t, "aws_ebs_volume", map[string]interface{}{"type": "io1", "size": float64(100), "iops": float64(5000)})
+		assert.InDelta(t, 100*0.125+5000*0.065, result.TotalMonthlyCost, 0.001)
+	})
+
+	t.Run("charges io2 IOPS in tiers", func(t *testing.T) {
+		result := estimate(t, "aws_ebs_volume", map[string]interface{}{"type": "io2", "size": float64(100), "iops": float64(70000)})
+		costs := componentCosts(result)
+		assert.InDelta(t, 32000*0.065, costs["Provisioned IOPS (io2) (first 32000 IOPS)"], 0.001)
+		assert.InDelta(t, 32000*0.0455, costs["Provisioned IOPS (io2) (32000 to 64000 IOPS)"], 0.001)
+		assert.InDelta(t, 6000*0.032, costs["Provisioned IOPS (io2) (over 64000 IOPS)"], 0.001)
+
+		result = estimate(t, "aws_ebs_volume", map[string]interface{}{"type": "io2", "size": float64(100), "iops": float64(10000)})
+		assert.InDelta(t, 10000*0.065, componentCosts(result)["Provisioned IOPS (io2)"], 0.001)
+	})
+
+	t.Run("charges io2 IOPS in the tiers of a single product", func(t *testing.T) {
+		priceList := createEC2PriceList()
+		addEBSPrices(priceList)
+		for _, sku := range []string{"io2-iops", "io2-iops-tier2", "io2-iops-tier3"} {
+			delete(priceList.Products, sku)
+			delete(priceList.Terms.OnDemand, sku)
+		}
+		addTieredMockProduct(priceList, "io2-iops", pricing.ProductAttributes{
+			ServiceCode:   "AmazonEC2",
+			Location:      "US East (N. Virginia)",
+			VolumeAPIName: "io2",
+			UsageType:     "EBS:VolumeP-IOPS.io2",
+		}, [3]string{"0", "32000", "0.065"}, [3]string{"32000", "Inf", "0.0455"})
+		plan := &terraform.Plan{ResourceChanges: []*terraform.ResourceChange{{
+			Address: "aws_ebs_volume.test",
+			Type:    "aws_ebs_volume",
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   map[string]interface{}{"type": "io2", "size": float64(100), "iops": float64(40000)},
+		}}}
+		result, err := Estimate(plan, priceList, "us-east-1", &UsageEstimates{})
+		assert.NoError(t, err)
+		costs := componentCosts(result)
+		assert.InDelta(t, 32000*0.065, costs["Provisioned IOPS (io2) (first 32000 IOPS)"], 0.001)
+		assert.InDelta(t, 8000*0.0455, costs["Provisioned IOPS (io2) (over 32000 IOPS)"], 0.001)
+	})
+
+	t.Run("includes the block devices of EC2 instances", func(t *testing.T) {
+		result := estimate(t, "aws_instance", map[string]interface{}{
+			"instance_type": "m5.large",
+			"root_block_device": []interface{}{
+				map[string]interface{}{"volume_type": "gp3", "volume_size": float64(50), "iops": float64(3000), "throughput": float64(125)},
+			},
+			"ebs_block_device": []interface{}{
+				map[string]interface{}{"device_name": "/dev/sdf", "volume_type": "io1", "volume_size": float64(200), "iops": float64(1000)},
+				map[string]interface{}{"device_name": "/dev/sdg", "volume_type": "st1"},
+			},
+		})
+		assert.Equal(t, map[string]float64{
+			"Instance usage (m5.large)":                    0.096 * 730,
+			"Root volume storage (gp3)":                    50 * 0.08,
+			"Block device /dev/sdf storage (io1)":          200 * 0.125,
+			"Block device /dev/sdf provisioned IOPS (io1)": 1000 * 0.065,
+		}, componentCosts(result))
+	})
+}
@@ -0,0 +1,325 @@
+package estimator
+
+import (
+	"fmt"
+	"strings"
+
+	"cloudcostguard/backend/pricing"
+)
+
+func init() {
+	MustRegister(NewCalculator("aws_instance", []string{"AmazonEC2"}, []string{"instance_type"}, costForEC2))
+}
+
+// ec2Platform identifies the operating system and licensed software an EC2 instance is priced for.
+type ec2Platform struct {
+	// OperatingSystem is the operating system in the price list (e.g., "Linux" or "Windows").
+	OperatingSystem string
+	// PreInstalledSw is the pre-installed software in the price list (e.g., "NA" or "SQL Std").
+	PreInstalledSw string
+	// LicenseModel is the license model in the price list (e.g., "No License required").
+	LicenseModel string
+}
+
+const (
+	licenseIncluded = "No License required"
+	licenseBYOL     = "Bring your own license"
+)
+
+// linuxPlatform is the platform of instances whose AMI is unknown.
+var linuxPlatform = ec2Platform{OperatingSystem: "Linux", PreInstalledSw: "NA", LicenseModel: licenseIncluded}
+
+// ec2Platforms maps the platform details of AMIs (e.g., "Windows with SQL Server Standard") and the operating system
+// hints accepted in usage estimates (e.g., "rhel") to EC2 platforms. Keys are lower case.
+var ec2Platforms = map[string]ec2Platform{
+	"linux":                              linuxPlatform,
+	"linux/unix":                         linuxPlatform,
+	"red hat byol linux":                 linuxPlatform,
+	"windows":                            {OperatingSystem: "Windows", PreInstalledSw: "NA", LicenseModel: licenseIncluded},
+	"windows byol":                       {OperatingSystem: "Windows", PreInstalledSw: "NA", LicenseModel: licenseBYOL},
+	"windows with sql server standard":   {OperatingSystem: "Windows", PreInstalledSw: "SQL Std", LicenseModel: licenseIncluded},
+	"windows with sql server enterprise": {OperatingSystem: "Windows", PreInstalledSw: "SQL Ent", LicenseModel: licenseIncluded},
+	"windows with sql server web":        {OperatingSystem: "Windows", PreInstalledSw: "SQL Web", LicenseModel: licenseIncluded},
+	"rhel":                               {OperatingSystem: "RHEL", PreInstalledSw: "NA", LicenseModel: licenseIncluded},
+	"red hat enterprise linux":           {OperatingSystem: "RHEL", PreInstalledSw: "NA", LicenseModel: licenseIncluded},
+	"red hat enterprise linux with ha":   {OperatingSystem: "Red Hat Enterprise Linux with HA", PreInstalledSw: "NA", LicenseModel: licenseIncluded},
+	"suse":                               {OperatingSystem: "SUSE", PreInstalledSw: "NA", LicenseModel: licenseIncluded},
+	"suse linux":                         {OperatingSystem: "SUSE", PreInstalledSw: "NA", LicenseModel: licenseIncluded},
+	"ubuntu pro":                         {OperatingSystem: "Ubuntu Pro", PreInstalledSw: "NA", LicenseModel: licenseIncluded},
+	"ubuntu pro linux":                   {OperatingSystem: "Ubuntu Pro", PreInstalledSw: "NA", LicenseModel: licenseIncluded},
+}
+
+// ec2Tenancies maps the tenancy attribute of an instance to the tenancy and usage type prefix in the price list.
+// Instances with "host" tenancy are not in the map, because they are billed through the dedicated host they run on.
+var ec2Tenancies = map[string]struct {
+	Tenancy         string
+	UsageTypePrefix string
+}{
+	"default":   {Tenancy: "Shared", UsageTypePrefix: "BoxUsage:"},
+	"dedicated": {Tenancy: "Dedicated", UsageTypePrefix: "DedicatedUsage:"},
+}
+
+// costForEC2 calculates the cost of an AWS EC2 instance.
+// The operating system is taken from the ec2.operating_system usage estimate if set, and is otherwise derived from the
+// aws_ami resource or data source in the plan whose ID matches the instance's ami. Instances with an unknown AMI are
+// priced as Linux. Surplus CPU credits of T-family instances with unlimited credits are priced from the
+// ec2.surplus_cpu_credit_hours usage estimate, and the EBS volumes of the root_block_device and ebs_block_device blocks
+// as separate components.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   attributes: The attributes of the EC2 instance resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the EC2 instance.
+//   An error if the pricing data cannot be found.
+func costForEC2(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	instanceType, _ := attributes["instance_type"].(string)
+	if instanceType == "" {
+		return nil, missingAttributeError("instance_type")
+	}
+
+	platform, err := resolveEC2Platform(ctx, attributes)
+	if err != nil {
+		return nil, err
+	}
+	tenancy, _ := attributes["tenancy"].(string)
+	instance, err := ec2InstanceComponent(ctx, instanceType, platform, tenancy, 1)
+	if err != nil {
+		return nil, err
+	}
+	components := []CostComponent{instance}
+
+	if ctx.Usage != nil && ctx.Usage.EC2.SurplusCPUCreditHours > 0 && cpuCredits(instanceType, attributes) == "unlimited" {
+		credits, err := cpuCreditComponent(ctx, instanceType, platform, float64(ctx.Usage.EC2.SurplusCPUCreditHours))
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, credits)
+	}
+
+	blockDevices, err := instanceBlockDeviceComponents(ctx, attributes)
+	if err != nil {
+		return nil, err
+	}
+	components = append(components, blockDevices...)
+
+	return newCost(components...), nil
+}
+
+// ec2InstanceComponent prices the instance hours of EC2 instances.
+// Instances with "host" tenancy run on a dedicated host that is billed per host rather than per instance, so they are
+// not priced.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   instanceType: The EC2 instance type (e.g., "t3.medium").
+//   platform: The operating system and licensed software of the instances.
+//   tenancy: The tenancy attribute of the instances ("default", "dedicated" or "host"); empty means "default".
+//   count: The number of instances.
+//
+// Returns:
+//   The cost component of the instance hours.
+//   An error if the tenancy is "host" or unknown, or the pricing data cannot be found.
+func ec2InstanceComponent(ctx *CalculationContext, instanceType string, platform ec2Platform, tenancy string, count float64) (CostComponent, error) {
+	if tenancy == "" {
+		tenancy = "default"
+	}
+	if tenancy == "host" {
+		return CostComponent{}, fmt.Errorf("%w: host tenancy is billed per dedicated host", ErrBilledSeparately)
+	}
+	tenancyPricing, ok := ec2Tenancies[tenancy]
+	if !ok {
+		return CostComponent{}, fmt.Errorf("%w: unknown tenancy %q", ErrMissingAttribute, tenancy)
+	}
+
+	// Products missing an attribute are accepted, so that price lists without it still match.
+	candidates := filterSKUs(ctx.PriceList, ctx.PriceList.Index().Lookup("AmazonEC2", ctx.Location, pricing.AttrInstanceType, instanceType), func(attr pricing.ProductAttributes) bool {
+		return attr.OperatingSystem == platform.OperatingSystem &&
+			strings.Contains(attr.UsageType, tenancyPricing.UsageTypePrefix) &&
+			matchesOptional(attr.Tenancy, tenancyPricing.Tenancy) &&
+			matchesOptional(attr.PreInstalledSw, platform.PreInstalledSw) &&
+			matchesOptional(attr.LicenseModel, platform.LicenseModel) &&
+			matchesOptional(attr.CapacityStatus, "Used")
+	})
+	variant := instanceType
+	if platform != linuxPlatform || tenancy != "default" {
+		variant = fmt.Sprintf("%s, %s", instanceType, platform.describe(tenancyPricing.Tenancy))
+	}
+	sku, price, err := ctx.MatchSKU(fmt.Sprintf("EC2 instance type: %s", variant), candidates)
+	if err != nil {
+		return CostComponent{}, err
+	}
+	return hourlyComponent(fmt.Sprintf("Instance usage (%s)", variant), sku, count, price), nil
+}
+
+// cpuCreditComponent prices the surplus CPU credits of a T-family instance.
+// CPU credits are only priced for Linux and Windows, so instances of every other operating system, such as RHEL or
+// SUSE, are charged the Linux rate.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   instanceType: The EC2 instance type (e.g., "t3.medium").
+//   platform: The operating system of the instance.
+//   vcpuHours: The monthly vCPU-hours of surplus CPU credits.
+//
+// Returns:
+//   The cost component of the surplus CPU credits.
+//   An error if the pricing data cannot be found.
+func cpuCreditComponent(ctx *CalculationContext, instanceType string, platform ec2Platform, vcpuHours float64) (CostComponent, error) {
+	family := instanceFamily(instanceType)
+	operatingSystem := "Linux"
+	if platform.OperatingSystem == "Windows" {
+		operatingSystem = "Windows"
+	}
+	candidates := filterSKUs(ctx.PriceList, usageTypeSKUs(ctx, "AmazonEC2", ctx.Location, "CPUCredits:"+family), func(attr pricing.ProductAttributes) bool {
+		return attr.OperatingSystem == operatingSystem
+	})
+	sku, price, err := ctx.MatchSKU(fmt.Sprintf("EC2 CPU credits: %s (%s)", family, operatingSystem), candidates)
+	if err != nil {
+		return CostComponent{}, err
+	}
+	return newComponent("Surplus CPU credits (unlimited)", "vCPU-hour", sku, vcpuHours, price), nil
+}
+
+// resolveEC2Platform determines the operating system and licensed software of an EC2 instance.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   attributes: The attributes of the EC2 instance resource.
+//
+// Returns:
+//   The platform of the instance.
+//   An error if the ec2.operating_system usage estimate is not a known operating system.
+func resolveEC2Platform(ctx *CalculationContext, attributes map[string]interface{}) (ec2Platform, error) {
+	if ctx.Usage != nil && ctx.Usage.EC2.OperatingSystem != "" {
+		platform, ok := ec2Platforms[strings.ToLower(ctx.Usage.EC2.OperatingSystem)]
+		if !ok {
+			return ec2Platform{}, fmt.Errorf("%w: unknown ec2.operating_system %q", ErrMissingAttribute, ctx.Usage.EC2.OperatingSystem)
+		}
+		return platform, nil
+	}
+
+	ami, _ := attributes["ami"].(string)
+	if image := findAMI(ctx, ami); image != nil {
+		if platform, ok := amiPlatform(image); ok {
+			return platform, nil
+		}
+	}
+	if getPasswordData, _ := attributes["get_password_data"].(bool); getPasswordData {
+		return ec2Platforms["windows"], nil
+	}
+	return linuxPlatform, nil
+}
+
+// findAMI finds the attributes of the aws_ami resource or data source in the plan with an image ID.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   id: The ID of the AMI (e.g., "ami-0123456789abcdef0").
+//
+// Returns:
+//   The attributes of the AMI, or nil if the plan does not contain it.
+func findAMI(ctx *CalculationContext, id string) map[string]interface{} {
+	if id == "" || ctx.Plan == nil {
+		return nil
+	}
+	for _, rc := range ctx.Plan.ResourceChanges {
+		if rc.Type != "aws_ami" || rc.After == nil {
+			continue
+		}
+		if imageID, _ := rc.After["id"].(string); imageID == id {
+			return rc.After
+		}
+		if imageID, _ := rc.After["image_id"].(string); imageID == id {
+			return rc.After
+		}
+	}
+	return nil
+}
+
+// amiPlatform derives the platform of an AMI from its platform details, platform or name attributes.
+//
+// Parameters:
+//   image: The attributes of the aws_ami resource or data source.
+//
+// Returns:
+//   The platform of the AMI, and true if it could be derived.
+func amiPlatform(image map[string]interface{}) (ec2Platform, bool) {
+	if details, _ := image["platform_details"].(string); details != "" {
+		if platform, ok := ec2Platforms[strings.ToLower(details)]; ok {
+			return platform, true
+		}
+	}
+	if platform, _ := image["platform"].(string); strings.EqualFold(platform, "windows") {
+		return ec2Platforms["windows"], true
+	}
+
+	name, _ := image["name"].(string)
+	name = strings.ToLower(name)
+	switch {
+	case name == "":
+		return ec2Platform{}, false
+	case strings.Contains(name, "windows") && strings.Contains(name, "sql_20") && strings.Contains(name, "enterprise"):
+		return ec2Platforms["windows with sql server enterprise"], true
+	case strings.Contains(name, "windows") && strings.Contains(name, "sql_20") && strings.Contains(name, "standard"):
+		return ec2Platforms["windows with sql server standard"], true
+	case strings.Contains(name, "windows") && strings.Contains(name, "sql_20") && strings.Contains(name, "web"):
+		return ec2Platforms["windows with sql server web"], true
+	case strings.Contains(name, "windows"):
+		return ec2Platforms["windows"], true
+	case strings.HasPrefix(name, "rhel"):
+		return ec2Platforms["rhel"], true
+	case strings.HasPrefix(name, "suse") || strings.Contains(name, "sles"):
+		return ec2Platforms["suse"], true
+	case strings.Contains(name, "ubuntu-pro"):
+		return ec2Platforms["ubuntu pro"], true
+	default:
+		return linuxPlatform, true
+	}
+}
+
+// describe renders the platform and tenancy for a component name, e.g. "Windows, SQL Std" or "Linux, Dedicated".
+func (p ec2Platform) describe(tenancy string) string {
+	parts := []string{p.OperatingSystem}
+	if p.PreInstalledSw != "" && p.PreInstalledSw != "NA" {
+		parts = append(parts, p.PreInstalledSw)
+	}
+	if p.LicenseModel == licenseBYOL {
+		parts = append(parts, "BYOL")
+	}
+	if tenancy != "Shared" {
+		parts = append(parts, tenancy)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// cpuCredits returns the CPU credit option of an instance: "standard" or "unlimited" for T-family instances,
+// and an empty string otherwise. Without a credit_specification block, T2 instances default to "standard" and
+// later T families to "unlimited".
+func cpuCredits(instanceType string, attributes map[string]interface{}) string {
+	family := instanceFamily(instanceType)
+	if !strings.HasPrefix(family, "t") {
+		return ""
+	}
+	if specs := blocks(attributes, "credit_specification"); len(specs) > 0 {
+		if credits, _ := specs[0]["cpu_credits"].(string); credits != "" {
+			return credits
+		}
+	}
+	if family == "t2" {
+		return "standard"
+	}
+	return "unlimited"
+}
+
+// instanceFamily returns the family of an instance type (e.g., "t3" for "t3.medium").
+func instanceFamily(instanceType string) string {
+	family, _, _ := strings.Cut(instanceType, ".")
+	return family
+}
+
+// matchesOptional reports whether a product attribute equals a value, treating an empty attribute as a match.
+func matchesOptional(attribute, value string) bool {
+	return attribute == "" || strings.EqualFold(attribute, value)
+}
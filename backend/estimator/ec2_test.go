@@ -0,0 +1,197 @@
+package estimator
+
+import (
+	"testing"
+
+	"cloudcostguard/backend/pricing"
+	"cloudcostguard/backend/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func createEC2PriceList() *pricing.PriceList {
+	priceList := pricing.NewPriceList()
+	priceList.Terms.OnDemand = make(map[string]map[string]pricing.Term)
+	add := func(sku, price string, attributes pricing.ProductAttributes) {
+		attributes.ServiceCode = "AmazonEC2"
+		attributes.Location = "US East (N. Virginia)"
+		priceList.Products[sku] = pricing.Product{SKU: sku, Attributes: attributes}
+		dim := pricing.PriceDimension{}
+		dim.PricePerUnit.USD = price
+		priceList.Terms.OnDemand[sku] = map[string]pricing.Term{
+			sku + ".JRTCKXETXF": {OfferTermCode: "JRTCKXETXF", PriceDimensions: map[string]pricing.PriceDimension{"dim": dim}},
+		}
+	}
+	instance := func(os, sw, license, tenancy, usageType, capacity string) pricing.ProductAttributes {
+		return pricing.ProductAttributes{
+			InstanceType:    "m5.large",
+			OperatingSystem: os,
+			PreInstalledSw:  sw,
+			LicenseModel:    license,
+			Tenancy:         tenancy,
+			UsageType:       usageType,
+			CapacityStatus:  capacity,
+		}
+	}
+
+	add("linux", "0.096", instance("Linux", "NA", licenseIncluded, "Shared", "BoxUsage:m5.large", "Used"))
+	add("linux-reservation", "0.096", instance("Linux", "NA", licenseIncluded, "Shared", "Reservation:m5.large", "UnusedCapacityReservation"))
+	add("linux-unused", "0.2", instance("Linux", "NA", licenseIncluded, "Shared", "BoxUsage:m5.large", "UnusedCapacityReservation"))
+	add("linux-sql-web", "0.12", instance("Linux", "SQL Web", licenseIncluded, "Shared", "BoxUsage:m5.large", "Used"))
+	add("linux-dedicated", "0.106", instance("Linux", "NA", licenseIncluded, "Dedicated", "DedicatedUsage:m5.large", "Used"))
+	add("windows", "0.188", instance("Windows", "NA", licenseIncluded, "Shared", "BoxUsage:m5.large", "Used"))
+	add("windows-byol", "0.096", instance("Windows", "NA", licenseBYOL, "Shared", "BoxUsage:m5.large", "Used"))
+	add("windows-sql-std", "0.563", instance("Windows", "SQL Std", licenseIncluded, "Shared", "BoxUsage:m5.large", "Used"))
+	add("rhel", "0.1248", instance("RHEL", "NA", licenseIncluded, "Shared", "BoxUsage:m5.large", "Used"))
+	add("suse", "0.126", instance("SUSE", "NA", licenseIncluded, "Shared", "BoxUsage:m5.large", "Used"))
+	add("t3-linux", "0.0416", pricing.ProductAttributes{InstanceType: "t3.medium", OperatingSystem: "Linux", UsageType: "BoxUsage:t3.medium"})
+	add("t3-rhel", "0.0704", pricing.ProductAttributes{InstanceType: "t3.medium", OperatingSystem: "RHEL", UsageType: "BoxUsage:t3.medium"})
+	add("t3-credits-linux", "0.05", pricing.ProductAttributes{OperatingSystem: "Linux", UsageType: "CPUCredits:t3"})
+	add("t3-credits-windows", "0.096", pricing.ProductAttributes{OperatingSystem: "Windows", UsageType: "CPUCredits:t3"})
+	return priceList
+}
+
+func TestEC2Pricing(t *testing.T) {
+	priceList := createEC2PriceList()
+	estimateInstance := func(t *testing.T, after map[string]interface{}, usage *UsageEstimates, extra ...*terraform.ResourceChange) ResourceCost {
+		plan := &terraform.Plan{ResourceChanges: append([]*terraform.ResourceChange{{
+			Address: "aws_instance.app",
+			Type:    "aws_instance",
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   after,
+		}}, extra...)}
+		result, err := Estimate(plan, priceList, "us-east-1", usage)
+		assert.NoError(t, err)
+		assert.Empty(t, result.Skipped)
+		assert.Empty(t, result.Warnings)
+		if !assert.Len(t, result.Resources, 1) {
+			t.FailNow()
+		}
+		return result.Resources[0]
+	}
+	amiDataSource := func(attributes map[string]interface{}) *terraform.ResourceChange {
+		return &terraform.ResourceChange{
+			Address: "data.aws_ami.app",
+			Mode:    terraform.ModeData,
+			Type:    "aws_ami",
+			Change:  terraform.Change{Actions: []string{"read"}},
+			After:   attributes,
+		}
+	}
+
+	t.Run("prices Linux instances on shared tenancy by default", func(t *testing.T) {
+		resource := estimateInstance(t, map[string]interface{}{"instance_type": "m5.large"}, &UsageEstimates{})
+		assert.Equal(t, "linux", resource.Components[0].SKU)
+		assert.Equal(t, "Instance usage (m5.large)", resource.Components[0].Name)
+	})
+
+	t.Run("derives the operating system from the AMI platform details", func(t *testing.T) {
+		resource := estimateInstance(t,
+			map[string]interface{}{"instance_type": "m5.large", "ami": "ami-123"},
+			&UsageEstimates{},
+			amiDataSource(map[string]interface{}{"id": "ami-123", "platform_details": "Windows with SQL Server Standard"}),
+		)
+		assert.Equal(t, "windows-sql-std", resource.Components[0].SKU)
+		assert.Equal(t, "Instance usage (m5.large, Windows, SQL Std)", resource.Components[0].Name)
+		assert.InDelta(t, 0.563*730, resource.MonthlyCost, 0.01)
+	})
+
+	t.Run("derives the operating system from the AMI name", func(t *testing.T) {
+		resource := estimateInstance(t,
+			map[string]interface{}{"instance_type": "m5.large", "ami": "ami-456"},
+			&UsageEstimates{},
+			amiDataSource(map[string]interface{}{"id": "ami-456", "name": "RHEL-9.2.0_HVM-20230503-x86_64-41-Hourly2-GP2"}),
+		)
+		assert.Equal(t, "rhel", resource.Components[0].SKU)
+	})
+
+	t.Run("treats instances that expose their password data as Windows", func(t *testing.T) {
+		resource := estimateInstance(t, map[string]interface{}{"instance_type": "m5.large", "get_password_data": true}, &UsageEstimates{})
+		assert.Equal(t, "windows", resource.Components[0].SKU)
+	})
+
+	t.Run("prefers the operating system hint from the usage estimates", func(t *testing.T) {
+		usage := &UsageEstimates{Resources: map[string]UsageEstimates{"aws_instance.app": {EC2: EC2Usage{OperatingSystem: "SUSE"}}}}
+		resource := estimateInstance(t,
+			map[string]interface{}{"instance_type": "m5.large", "ami": "ami-123"},
+			usage,
+			amiDataSource(map[string]interface{}{"id": "ami-123", "platform_details": "Windows"}),
+		)
+		assert.Equal(t, "suse", resource.Components[0].SKU)
+
+		resource = estimateInstance(t, map[string]interface{}{"instance_type": "m5.large"}, &UsageEstimates{EC2: EC2Usage{OperatingSystem: "Windows BYOL"}})
+		assert.Equal(t, "windows-byol", resource.Components[0].SKU)
+		assert.Equal(t, "Instance usage (m5.large, Windows, BYOL)", resource.Components[0].Name)
+	})
+
+	t.Run("respects the tenancy of the instance", func(t *testing.T) {
+		resource := estimateInstance(t, map[string]interface{}{"instance_type": "m5.large", "tenancy": "dedicated"}, &UsageEstimates{})
+		assert.Equal(t, "linux-dedicated", resource.Components[0].SKU)
+		assert.Equal(t, "Instance usage (m5.large, Linux, Dedicated)", resource.Components[0].Name)
+	})
+
+	t.Run("skips instances on a dedicated host, which is billed per host", func(t *testing.T) {
+		plan := &terraform.Plan{ResourceChanges: []*terraform.ResourceChange{{
+			Address: "aws_instance.app",
+			Type:    "aws_instance",
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   map[string]interface{}{"instance_type": "m5.large", "tenancy": "host"},
+		}}}
+		result, err := Estimate(plan, priceList, "us-east-1", &UsageEstimates{})
+		assert.NoError(t, err)
+		assert.Zero(t, result.TotalMonthlyCost)
+		assert.Zero(t, result.Coverage.PricedResources)
+		assert.Empty(t, result.Warnings)
+		if assert.Len(t, result.Skipped, 1) {
+			assert.Equal(t, SkipReasonBilledSeparately, result.Skipped[0].Reason)
+			assert.Equal(t, "billed separately: host tenancy is billed per dedicated host", result.Skipped[0].Message)
+		}
+	})
+
+	t.Run("skips instances with an unknown operating system hint or tenancy", func(t *testing.T) {
+		plan := &terraform.Plan{ResourceChanges: []*terraform.ResourceChange{
+			{Address: "aws_instance.a", Type: "aws_instance", Change: terraform.Change{Actions: []string{"create"}}, After: map[string]interface{}{"instance_type": "m5.large", "tenancy": "shared"}},
+		}}
+		result, err := Estimate(plan, priceList, "us-east-1", &UsageEstimates{})
+		assert.NoError(t, err)
+		assert.Equal(t, SkipReasonMissingAttribute, result.Skipped[0].Reason)
+
+		result, err = Estimate(plan, priceList, "us-east-1", &UsageEstimates{EC2: EC2Usage{OperatingSystem: "BeOS"}})
+		assert.NoError(t, err)
+		assert.Equal(t, `missing attribute: unknown ec2.operating_system "BeOS"`, result.Skipped[0].Message)
+	})
+
+	t.Run("prices surplus CPU credits of unlimited T-family instances", func(t *testing.T) {
+		usage := &UsageEstimates{EC2: EC2Usage{SurplusCPUCreditHours: 100}}
+		resource := estimateInstance(t, map[string]interface{}{"instance_type": "t3.medium"}, usage)
+		assert.Len(t, resource.Components, 2)
+		assert.Equal(t, CostComponent{
+			Name:            "Surplus CPU credits (unlimited)",
+			Unit:            "vCPU-hour",
+			MonthlyQuantity: 100,
+			UnitPrice:       0.05,
+			SKU:             "t3-credits-linux",
+			OfferTermCode:   "JRTCKXETXF",
+			MonthlyCost:     5,
+		}, resource.Components[1])
+		assert.InDelta(t, 0.0416*730+5, resource.MonthlyCost, 0.01)
+	})
+
+	t.Run("prices CPU credits of operating systems other than Windows at the Linux rate", func(t *testing.T) {
+		usage := &UsageEstimates{EC2: EC2Usage{OperatingSystem: "RHEL", SurplusCPUCreditHours: 100}}
+		resource := estimateInstance(t, map[string]interface{}{"instance_type": "t3.medium"}, usage)
+		if assert.Len(t, resource.Components, 2) {
+			assert.Equal(t, "t3-credits-linux", resource.Components[1].SKU)
+		}
+		assert.InDelta(t, 0.0704*730+5, resource.MonthlyCost, 0.01)
+	})
+
+	t.Run("does not price CPU credits of instances with standard credits", func(t *testing.T) {
+		usage := &UsageEstimates{EC2: EC2Usage{SurplusCPUCreditHours: 100}}
+		after := map[string]interface{}{
+			"instance_type":        "t3.medium",
+			"credit_specification": []interface{}{map[string]interface{}{"cpu_credits": "standard"}},
+		}
+		resource := estimateInstance(t, after, usage)
+		assert.Len(t, resource.Components, 1)
+	})
+}
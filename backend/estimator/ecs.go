@@ -0,0 +1,78 @@
+package estimator
+
+import (
+	"fmt"
+
+	"cloudcostguard/backend/terraform"
+)
+
+func init() {
+	MustRegister(NewCalculator("aws_ecs_service", []string{"AmazonECS"}, nil, costForECSService))
+	// Cost is calculated as part of the ECS service, not standalone.
+	MustRegister(NewCalculator("aws_ecs_task_definition", nil, nil, zeroCost))
+}
+
+// costForECSService calculates the cost of an AWS ECS service.
+// It currently only supports the Fargate launch type.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its plan is used to resolve the task definition.
+//   attributes: The attributes of the ECS service resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the ECS service.
+//   An error if the pricing data cannot be found.
+func costForECSService(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	launchType, _ := attributes["launch_type"].(string)
+	if launchType != "FARGATE" {
+		// For EC2 launch type, cost is in the EC2 instances, not the service.
+		return newCost(), nil
+	}
+
+	desiredCount, _ := attributes["desired_count"].(float64)
+	if desiredCount == 0 {
+		desiredCount = 1
+	}
+
+	taskDefinitionArn, _ := attributes["task_definition"].(string)
+	if taskDefinitionArn == "" {
+		return nil, fmt.Errorf("%w: task_definition for Fargate service", ErrMissingAttribute)
+	}
+
+	var taskDef *terraform.ResourceChange
+	for _, r := range ctx.Plan.ResourceChanges {
+		if r.Address == taskDefinitionArn {
+			taskDef = r
+			break
+		}
+	}
+
+	if taskDef == nil {
+		return nil, fmt.Errorf("%w: task definition %s not found in plan", ErrMissingAttribute, taskDefinitionArn)
+	}
+
+	cpu, err := parseFloat(taskDef.After["cpu"])
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not parse cpu from task definition: %v", ErrMissingAttribute, err)
+	}
+
+	memory, err := parseFloat(taskDef.After["memory"])
+	if err != nil {
+		return nil, fmt.Errorf("%w: could not parse memory from task definition: %v", ErrMissingAttribute, err)
+	}
+
+	vcpuSKU, vcpuPrice, err := ctx.MatchSKU("Fargate vCPU hours", usageTypeSKUs(ctx, "AmazonECS", ctx.Location, "Fargate-vCPU-Hours:perCPU"))
+	if err != nil {
+		return nil, err
+	}
+
+	memorySKU, memoryPrice, err := ctx.MatchSKU("Fargate memory hours", usageTypeSKUs(ctx, "AmazonECS", ctx.Location, "Fargate-GB-Hours"))
+	if err != nil {
+		return nil, err
+	}
+
+	return newCost(
+		hourlyComponent(fmt.Sprintf("Fargate vCPU (%d tasks x %.2f vCPU)", int(desiredCount), cpu/1024), vcpuSKU, desiredCount*cpu/1024, vcpuPrice),
+		hourlyComponent(fmt.Sprintf("Fargate memory (%d tasks x %.2f GB)", int(desiredCount), memory/1024), memorySKU, desiredCount*memory/1024, memoryPrice),
+	), nil
+}
@@ -0,0 +1,133 @@
+package estimator
+
+import (
+	"fmt"
+)
+
+func init() {
+	MustRegister(NewCalculator("aws_efs_file_system", []string{"AmazonEFS"}, nil, costForEFSFileSystem))
+}
+
+// Usage types of EFS file systems, without a region prefix.
+const (
+	efsStandardStorage          = "TimedStorage-ByteHrs"
+	efsInfrequentAccessStorage  = "IATimedStorage-ByteHrs"
+	efsArchiveStorage           = "ArchiveTimedStorage-ByteHrs"
+	efsOneZoneStorage           = "TimedStorage-Z-ByteHrs"
+	efsOneZoneInfrequentStorage = "IATimedStorage-Z-ByteHrs"
+	efsInfrequentAccessRead     = "IADataAccess-Bytes"
+	efsArchiveRead              = "ArchiveDataAccess-Bytes"
+	efsProvisionedThroughput    = "ProvisionedTP-MiBpsHrs"
+	efsElasticThroughputRead    = "ElasticThroughputRead-Bytes"
+	efsElasticThroughputWrite   = "ElasticThroughputWrite-Bytes"
+)
+
+// efsIncludedThroughputPerGB is the throughput, in MiB/s, that each GB stored in the Standard class entitles a file
+// system in provisioned throughput mode to without charge (50 KiB/s per GiB).
+const efsIncludedThroughputPerGB = 50.0 / 1024
+
+// costForEFSFileSystem calculates the cost of an AWS EFS file system.
+// The efs.storage_gb usage estimate is split between the Standard class and the Infrequent Access and Archive classes
+// that the lifecycle policies of the file system move data to, using the efs.infrequent_access_percent and
+// efs.archive_percent usage estimates. File systems with an availability_zone_name use the One Zone classes, which
+// have no Archive class. Reads from the Infrequent Access and Archive classes are charged per GB.
+// Throughput is charged by throughput_mode:
+//
+//   - bursting (the default): included in the storage price.
+//   - provisioned: provisioned_throughput_in_mibps beyond the throughput included with the Standard storage.
+//   - elastic: the efs.monthly_read_gb and efs.monthly_write_gb usage estimates.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its usage estimates may include the storage and access of
+//        the file system.
+//   attributes: The attributes of the EFS file system resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the file system.
+//   An error if the throughput mode or storage class mix is invalid, or the pricing data cannot be found.
+func costForEFSFileSystem(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	usage := ctx.Usage
+	if usage == nil {
+		usage = &UsageEstimates{}
+	}
+	zone, _ := attributes["availability_zone_name"].(string)
+	oneZone := zone != ""
+
+	var infrequentGB, archiveGB float64
+	storageGB := float64(usage.EFS.StorageGB)
+	for _, policy := range blocks(attributes, "lifecycle_policy") {
+		if transition, _ := policy["transition_to_ia"].(string); transition != "" {
+			infrequentGB = storageGB * float64(usage.EFS.InfrequentAccessPercent) / 100
+		}
+		if transition, _ := policy["transition_to_archive"].(string); transition != "" && !oneZone {
+			archiveGB = storageGB * float64(usage.EFS.ArchivePercent) / 100
+		}
+	}
+	standardGB := storageGB - infrequentGB - archiveGB
+	if standardGB < 0 {
+		return nil, fmt.Errorf("%w: efs.infrequent_access_percent and efs.archive_percent add up to more than 100", ErrInvalidUsage)
+	}
+
+	var components []CostComponent
+	add := func(name, unit, usageType string, quantity float64) error {
+		sku, _, err := ctx.MatchSKU("EFS "+usageType, usageTypeSKUs(ctx, "AmazonEFS", ctx.Location, usageType))
+		if err != nil {
+			return err
+		}
+		added, err := tieredComponents(ctx, name, unit, sku, quantity)
+		components = append(components, added...)
+		return err
+	}
+	addUsage := func(name, unit, usageType string, quantity float64) error {
+		if quantity <= 0 {
+			return nil
+		}
+		return add(name, unit, usageType, quantity)
+	}
+
+	if oneZone {
+		if err := add("Storage (One Zone)", "GB-month", efsOneZoneStorage, standardGB); err != nil {
+			return nil, err
+		}
+		if err := addUsage("Storage (One Zone-Infrequent Access)", "GB-month", efsOneZoneInfrequentStorage, infrequentGB); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := add("Storage (Standard)", "GB-month", efsStandardStorage, standardGB); err != nil {
+			return nil, err
+		}
+		if err := addUsage("Storage (Infrequent Access)", "GB-month", efsInfrequentAccessStorage, infrequentGB); err != nil {
+			return nil, err
+		}
+		if err := addUsage("Storage (Archive)", "GB-month", efsArchiveStorage, archiveGB); err != nil {
+			return nil, err
+		}
+	}
+	if err := addUsage("Infrequent Access reads", "GB", efsInfrequentAccessRead, float64(usage.EFS.InfrequentAccessReadGB)); err != nil {
+		return nil, err
+	}
+	if err := addUsage("Archive reads", "GB", efsArchiveRead, float64(usage.EFS.ArchiveReadGB)); err != nil {
+		return nil, err
+	}
+
+	mode, _ := attributes["throughput_mode"].(string)
+	switch mode {
+	case "", "bursting":
+	case "provisioned":
+		provisioned, _ := attributes["provisioned_throughput_in_mibps"].(float64)
+		if err := addUsage("Provisioned throughput", "MiBps-month", efsProvisionedThroughput, provisioned-standardGB*efsIncludedThroughputPerGB); err != nil {
+			return nil, err
+		}
+	case "elastic":
+		if err := addUsage("Elastic throughput reads", "GB", efsElasticThroughputRead, float64(usage.EFS.MonthlyReadGB)); err != nil {
+			return nil, err
+		}
+		if err := addUsage("Elastic throughput writes", "GB", efsElasticThroughputWrite, float64(usage.EFS.MonthlyWriteGB)); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown EFS throughput mode %q", ErrMissingAttribute, mode)
+	}
+
+	return newCost(components...), nil
+}
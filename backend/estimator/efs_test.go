@@ -0,0 +1,110 @@
+package estimator
+
+import (
+	"testing"
+
+	"cloudcostguard/backend/pricing"
+	"cloudcostguard/backend/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+// createEFSPriceList creates a price list with EFS storage, access and throughput prices.
+func createEFSPriceList() *pricing.PriceList {
+	priceList := pricing.NewPriceList()
+	add := func(sku, usageType, price string) {
+		addMockProduct(priceList, sku, pricing.ProductAttributes{ServiceCode: "AmazonEFS", Location: "US East (N. Virginia)", UsageType: usageType}, price)
+	}
+	add("standard", "USE1-TimedStorage-ByteHrs", "0.30")
+	add("ia", "USE1-IATimedStorage-ByteHrs", "0.016")
+	add("archive", "USE1-ArchiveTimedStorage-ByteHrs", "0.008")
+	add("one-zone", "USE1-TimedStorage-Z-ByteHrs", "0.16")
+	add("one-zone-ia", "USE1-IATimedStorage-Z-ByteHrs", "0.0133")
+	add("ia-read", "USE1-IADataAccess-Bytes", "0.01")
+	add("archive-read", "USE1-ArchiveDataAccess-Bytes", "0.03")
+	add("provisioned", "USE1-ProvisionedTP-MiBpsHrs", "6.00")
+	add("elastic-read", "USE1-ElasticThroughputRead-Bytes", "0.03")
+	add("elastic-write", "USE1-ElasticThroughputWrite-Bytes", "0.06")
+	return priceList
+}
+
+func TestEFSPricing(t *testing.T) {
+	priceList := createEFSPriceList()
+	estimate := func(t *testing.T, usage *UsageEstimates, after map[string]interface{}) *EstimationResponse {
+		plan := &terraform.Plan{ResourceChanges: []*terraform.ResourceChange{{
+			Address: "aws_efs_file_system.shared",
+			Type:    "aws_efs_file_system",
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   after,
+		}}}
+		result, err := Estimate(plan, priceList, "us-east-1", usage)
+		assert.NoError(t, err)
+		return result
+	}
+	costs := func(t *testing.T, result *EstimationResponse) map[string]float64 {
+		costs := make(map[string]float64)
+		if assert.Len(t, result.Resources, 1) {
+			for _, component := range result.Resources[0].Components {
+				costs[component.Name] = component.MonthlyCost
+			}
+		}
+		return costs
+	}
+	lifecycle := []interface{}{
+		map[string]interface{}{"transition_to_ia": "AFTER_30_DAYS"},
+		map[string]interface{}{"transition_to_archive": "AFTER_90_DAYS"},
+	}
+
+	t.Run("prices bursting file systems in the Standard class", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{EFS: EFSUsage{StorageGB: 100, InfrequentAccessPercent: 80}}, map[string]interface{}{})
+		assert.Equal(t, map[string]float64{"Storage (Standard)": 30}, costs(t, result))
+	})
+
+	t.Run("splits storage between the classes of the lifecycle policies", func(t *testing.T) {
+		usage := &UsageEstimates{EFS: EFSUsage{StorageGB: 1000, InfrequentAccessPercent: 60, ArchivePercent: 30, InfrequentAccessReadGB: 50, ArchiveReadGB: 10}}
+		costs := costs(t, estimate(t, usage, map[string]interface{}{"lifecycle_policy": lifecycle}))
+		assert.InDelta(t, 100*0.30, costs["Storage (Standard)"], 1e-9)
+		assert.InDelta(t, 600*0.016, costs["Storage (Infrequent Access)"], 1e-9)
+		assert.InDelta(t, 300*0.008, costs["Storage (Archive)"], 1e-9)
+		assert.InDelta(t, 0.5, costs["Infrequent Access reads"], 1e-9)
+		assert.InDelta(t, 0.3, costs["Archive reads"], 1e-9)
+	})
+
+	t.Run("uses the One Zone classes without an Archive class", func(t *testing.T) {
+		usage := &UsageEstimates{EFS: EFSUsage{StorageGB: 1000, InfrequentAccessPercent: 60, ArchivePercent: 30}}
+		costs := costs(t, estimate(t, usage, map[string]interface{}{"availability_zone_name": "us-east-1a", "lifecycle_policy": lifecycle}))
+		assert.Len(t, costs, 2)
+		assert.InDelta(t, 400*0.16, costs["Storage (One Zone)"], 1e-9)
+		assert.InDelta(t, 600*0.0133, costs["Storage (One Zone-Infrequent Access)"], 1e-9)
+	})
+
+	t.Run("skips file systems whose storage class percentages add up to more than 100", func(t *testing.T) {
+		usage := &UsageEstimates{EFS: EFSUsage{StorageGB: 1000, InfrequentAccessPercent: 80, ArchivePercent: 30}}
+		result := estimate(t, usage, map[string]interface{}{"lifecycle_policy": lifecycle})
+		assert.Empty(t, result.Resources)
+		if assert.Len(t, result.Skipped, 1) {
+			assert.Equal(t, SkipReasonInvalidUsage, result.Skipped[0].Reason)
+		}
+	})
+
+	t.Run("charges provisioned throughput beyond the throughput included with Standard storage", func(t *testing.T) {
+		// 1024 GB of Standard storage includes 50 MiB/s.
+		costs := costs(t, estimate(t, &UsageEstimates{EFS: EFSUsage{StorageGB: 1024}}, map[string]interface{}{
+			"throughput_mode":                 "provisioned",
+			"provisioned_throughput_in_mibps": float64(128),
+		}))
+		assert.InDelta(t, 78*6.00, costs["Provisioned throughput"], 1e-9)
+	})
+
+	t.Run("charges elastic throughput for reads and writes", func(t *testing.T) {
+		usage := &UsageEstimates{EFS: EFSUsage{StorageGB: 10, MonthlyReadGB: 1000, MonthlyWriteGB: 200}}
+		costs := costs(t, estimate(t, usage, map[string]interface{}{"throughput_mode": "elastic"}))
+		assert.InDelta(t, 30.0, costs["Elastic throughput reads"], 1e-9)
+		assert.InDelta(t, 12.0, costs["Elastic throughput writes"], 1e-9)
+	})
+
+	t.Run("skips file systems with an unknown throughput mode", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{EFS: EFSUsage{StorageGB: 10}}, map[string]interface{}{"throughput_mode": "turbo"})
+		assert.Empty(t, result.Resources)
+		assert.Equal(t, SkipReasonMissingAttribute, result.Skipped[0].Reason)
+	})
+}
@@ -0,0 +1,66 @@
+package estimator
+
+import "strings"
+
+func init() {
+	MustRegister(NewCalculator("aws_eks_cluster", []string{"AmazonEKS"}, nil, costForEKS))
+	MustRegister(NewCalculator("aws_eks_node_group", []string{"AmazonEC2"}, []string{"instance_types", "scaling_config"}, costForEKSNodeGroup))
+}
+
+// costForEKS calculates the cost of an AWS EKS cluster.
+// It includes the hourly price for the control plane.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   attributes: The attributes of the EKS cluster resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the EKS cluster.
+//   An error if the pricing data cannot be found.
+func costForEKS(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	sku, price, err := ctx.MatchSKU("EKS control plane in region: "+ctx.Location, usageTypeSKUs(ctx, "AmazonEKS", ctx.Location, "AmazonEKS-Hours:perCluster"))
+	if err != nil {
+		return nil, err
+	}
+
+	return newCost(hourlyComponent("EKS cluster usage", sku, 1, price)), nil
+}
+
+// costForEKSNodeGroup calculates the cost of an AWS EKS node group.
+// It calculates the cost of the EC2 instances in the node group at its desired size.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   attributes: The attributes of the EKS node group resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the EKS node group.
+//   An error if the pricing data cannot be found.
+func costForEKSNodeGroup(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	instanceTypes, ok := attributes["instance_types"].([]interface{})
+	if !ok || len(instanceTypes) == 0 {
+		return nil, missingAttributeError("instance_types")
+	}
+	instanceType := instanceTypes[0].(string)
+
+	scalingConfig, ok := attributes["scaling_config"].([]interface{})
+	if !ok || len(scalingConfig) == 0 {
+		return nil, missingAttributeError("scaling_config")
+	}
+	desiredSize, ok := scalingConfig[0].(map[string]interface{})["desired_size"].(float64)
+	if !ok {
+		return nil, missingAttributeError("scaling_config.desired_size")
+	}
+
+	// Node groups run Amazon Linux or Bottlerocket unless their AMI type is one of the Windows AMI types.
+	platform := linuxPlatform
+	if amiType, _ := attributes["ami_type"].(string); strings.HasPrefix(amiType, "WINDOWS_") {
+		platform = ec2Platforms["windows"]
+	}
+
+	instance, err := ec2InstanceComponent(ctx, instanceType, platform, "", desiredSize)
+	if err != nil {
+		return nil, err
+	}
+	return newCost(instance), nil
+}
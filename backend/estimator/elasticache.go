@@ -0,0 +1,225 @@
+package estimator
+
+import (
+	"fmt"
+	"strings"
+
+	"cloudcostguard/backend/pricing"
+)
+
+func init() {
+	MustRegister(NewCalculator("aws_elasticache_cluster", []string{"AmazonElastiCache"}, nil, costForElastiCache))
+	MustRegister(NewCalculator("aws_elasticache_replication_group", []string{"AmazonElastiCache"}, []string{"node_type"}, costForElastiCacheReplicationGroup))
+	MustRegister(NewCalculator("aws_elasticache_serverless_cache", []string{"AmazonElastiCache"}, []string{"engine"}, costForElastiCacheServerless))
+}
+
+// elastiCacheEngines maps the engine attribute of an ElastiCache resource to the cache engine in the price list.
+var elastiCacheEngines = map[string]string{
+	"redis":     "Redis",
+	"valkey":    "Valkey",
+	"memcached": "Memcached",
+}
+
+// Usage types of ElastiCache serverless caches, without a region prefix or engine.
+const (
+	elastiCacheServerlessStorage = "CachedData"
+	elastiCacheServerlessECPU    = "ElastiCacheProcessingUnits"
+)
+
+// elastiCacheServerlessMinimumStorageGB is the minimum data stored that serverless caches are charged for, by engine.
+var elastiCacheServerlessMinimumStorageGB = map[string]float64{
+	"Redis":     1,
+	"Valkey":    0.1,
+	"Memcached": 1,
+}
+
+// costForElastiCache calculates the cost of an AWS ElastiCache cluster.
+// Clusters are priced for num_cache_nodes nodes of their node type and engine. A cluster that joins a replication
+// group adds one node of the group's node type and engine.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its plan is used to resolve the replication group.
+//   attributes: The attributes of the ElastiCache cluster resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the ElastiCache cluster.
+//   An error if the node type or engine is unknown or the pricing data cannot be found.
+func costForElastiCache(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	nodeType, _ := attributes["node_type"].(string)
+	engineAttributes := attributes
+	if groupID, _ := attributes["replication_group_id"].(string); groupID != "" {
+		if group := findPlanResourceByAttribute(ctx, "aws_elasticache_replication_group", "replication_group_id", groupID); group != nil {
+			if nodeType == "" {
+				nodeType, _ = group["node_type"].(string)
+			}
+			if _, ok := attributes["engine"].(string); !ok {
+				engineAttributes = group
+			}
+		}
+	}
+	if nodeType == "" {
+		return nil, missingAttributeError("node_type")
+	}
+	engine, err := resolveElastiCacheEngine(engineAttributes)
+	if err != nil {
+		return nil, err
+	}
+	numCacheNodes, _ := attributes["num_cache_nodes"].(float64)
+	if numCacheNodes == 0 {
+		numCacheNodes = 1
+	}
+
+	component, err := elastiCacheNodeComponent(ctx, nodeType, engine, numCacheNodes)
+	if err != nil {
+		return nil, err
+	}
+	return newCost(component), nil
+}
+
+// costForElastiCacheReplicationGroup calculates the cost of an AWS ElastiCache replication group.
+// Groups with cluster mode enabled run num_node_groups shards of a primary and replicas_per_node_group replicas each.
+// Other groups run num_cache_clusters nodes.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   attributes: The attributes of the ElastiCache replication group resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the replication group.
+//   An error if the engine is unknown or the pricing data cannot be found.
+func costForElastiCacheReplicationGroup(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	nodeType, _ := attributes["node_type"].(string)
+	if nodeType == "" {
+		return nil, missingAttributeError("node_type")
+	}
+	engine, err := resolveElastiCacheEngine(attributes)
+	if err != nil {
+		return nil, err
+	}
+	if engine == "" {
+		engine = elastiCacheEngines["redis"]
+	}
+
+	nodes, _ := attributes["num_cache_clusters"].(float64)
+	if numNodeGroups, _ := attributes["num_node_groups"].(float64); numNodeGroups > 0 {
+		replicas, _ := attributes["replicas_per_node_group"].(float64)
+		nodes = numNodeGroups * (1 + replicas)
+	}
+	if nodes == 0 {
+		nodes = 1
+	}
+
+	component, err := elastiCacheNodeComponent(ctx, nodeType, engine, nodes)
+	if err != nil {
+		return nil, err
+	}
+	return newCost(component), nil
+}
+
+// costForElastiCacheServerless calculates the cost of an AWS ElastiCache serverless cache.
+// Serverless caches are charged for the data they store, from the elasticache.serverless_storage_gb usage estimate
+// but at least the minimum of their engine and at most the data_storage limit in cache_usage_limits, and for the
+// ElastiCache Processing Units (ECPUs) in the elasticache.serverless_monthly_ecpus usage estimate.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its usage estimates may include the storage and ECPUs of
+//        the cache.
+//   attributes: The attributes of the ElastiCache serverless cache resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the serverless cache.
+//   An error if the engine is unknown or the pricing data cannot be found.
+func costForElastiCacheServerless(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	engine, err := resolveElastiCacheEngine(attributes)
+	if err != nil {
+		return nil, err
+	}
+	if engine == "" {
+		return nil, missingAttributeError("engine")
+	}
+	usage := ctx.Usage
+	if usage == nil {
+		usage = &UsageEstimates{}
+	}
+
+	storageGB := max(float64(usage.ElastiCache.ServerlessStorageGB), elastiCacheServerlessMinimumStorageGB[engine])
+	if limit := firstBlock(firstBlock(attributes, "cache_usage_limits"), "data_storage"); limit != nil {
+		if maximum, _ := limit["maximum"].(float64); maximum > 0 {
+			storageGB = min(storageGB, maximum)
+		}
+	}
+
+	var components []CostComponent
+	add := func(name, unit, usageType string, quantity float64) error {
+		if quantity <= 0 {
+			return nil
+		}
+		candidates := filterSKUs(ctx.PriceList, usageTypeSKUs(ctx, "AmazonElastiCache", ctx.Location, usageType+":"+engine), func(attr pricing.ProductAttributes) bool {
+			return matchesOptional(attr.CacheEngine, engine)
+		})
+		sku, _, err := ctx.MatchSKU(fmt.Sprintf("ElastiCache Serverless %s (%s)", usageType, engine), candidates)
+		if err != nil {
+			return err
+		}
+		added, err := tieredComponents(ctx, name, unit, sku, quantity)
+		components = append(components, added...)
+		return err
+	}
+	if err := add("Data stored", "GB-hour", elastiCacheServerlessStorage, storageGB*hoursPerMonth); err != nil {
+		return nil, err
+	}
+	if err := add("ElastiCache Processing Units", "ECPU", elastiCacheServerlessECPU, float64(usage.ElastiCache.ServerlessMonthlyECPUs)); err != nil {
+		return nil, err
+	}
+
+	return newCost(components...), nil
+}
+
+// resolveElastiCacheEngine determines the cache engine of an ElastiCache resource.
+//
+// Parameters:
+//   attributes: The attributes of the ElastiCache resource.
+//
+// Returns:
+//   The cache engine in the price list (e.g., "Redis"), or an empty string if the resource has no engine attribute,
+//   which matches every engine.
+//   An error if the engine is unknown.
+func resolveElastiCacheEngine(attributes map[string]interface{}) (string, error) {
+	name, _ := attributes["engine"].(string)
+	if name == "" {
+		return "", nil
+	}
+	engine, ok := elastiCacheEngines[strings.ToLower(name)]
+	if !ok {
+		return "", fmt.Errorf("%w: unknown ElastiCache engine %q", ErrMissingAttribute, name)
+	}
+	return engine, nil
+}
+
+// elastiCacheNodeComponent prices a number of cache nodes of a node type and engine.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   nodeType: The node type (e.g., "cache.r6g.large").
+//   engine: The cache engine in the price list, or an empty string to match every engine.
+//   nodes: The number of nodes.
+//
+// Returns:
+//   The cost component of the nodes.
+//   An error if the pricing data cannot be found.
+func elastiCacheNodeComponent(ctx *CalculationContext, nodeType, engine string, nodes float64) (CostComponent, error) {
+	candidates := ctx.PriceList.Index().Lookup("AmazonElastiCache", ctx.Location, pricing.AttrInstanceType, nodeType)
+	description := nodeType
+	if engine != "" {
+		// Products missing the engine attribute are accepted, so that price lists without it still match.
+		candidates = filterSKUs(ctx.PriceList, candidates, func(attr pricing.ProductAttributes) bool {
+			return matchesOptional(attr.CacheEngine, engine)
+		})
+		description = fmt.Sprintf("%s, %s", nodeType, engine)
+	}
+	sku, price, err := ctx.MatchSKU("ElastiCache node type: "+description, candidates)
+	if err != nil {
+		return CostComponent{}, err
+	}
+	return hourlyComponent(fmt.Sprintf("Cache node usage (%s)", description), sku, nodes, price), nil
+}
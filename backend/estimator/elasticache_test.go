@@ -0,0 +1,142 @@
+package estimator
+
+import (
+	"testing"
+
+	"cloudcostguard/backend/pricing"
+	"cloudcostguard/backend/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+// createElastiCachePriceList creates a price list with ElastiCache node and serverless prices for each engine.
+func createElastiCachePriceList() *pricing.PriceList {
+	usEast := "US East (N. Virginia)"
+	priceList := pricing.NewPriceList()
+	node := func(sku, engine, price string) {
+		addMockProduct(priceList, sku, pricing.ProductAttributes{
+			ServiceCode:  "AmazonElastiCache",
+			Location:     usEast,
+			InstanceType: "cache.r6g.large",
+			CacheEngine:  engine,
+		}, price)
+	}
+	node("redis", "Redis", "0.206")
+	node("valkey", "Valkey", "0.1648")
+	node("memcached", "Memcached", "0.206")
+
+	serverless := func(sku, engine, usageType, price string) {
+		addMockProduct(priceList, sku, pricing.ProductAttributes{
+			ServiceCode: "AmazonElastiCache",
+			Location:    usEast,
+			UsageType:   usageType,
+			CacheEngine: engine,
+		}, price)
+	}
+	serverless("redis-storage", "Redis", "USE1-CachedData:Redis", "0.125")
+	serverless("redis-ecpu", "Redis", "USE1-ElastiCacheProcessingUnits:Redis", "0.0000000034")
+	serverless("valkey-storage", "Valkey", "USE1-CachedData:Valkey", "0.084")
+	serverless("valkey-ecpu", "Valkey", "USE1-ElastiCacheProcessingUnits:Valkey", "0.0000000023")
+	return priceList
+}
+
+func TestElastiCachePricing(t *testing.T) {
+	priceList := createElastiCachePriceList()
+	estimate := func(t *testing.T, usage *UsageEstimates, resources ...*terraform.ResourceChange) *EstimationResponse {
+		result, err := Estimate(&terraform.Plan{ResourceChanges: resources}, priceList, "us-east-1", usage)
+		assert.NoError(t, err)
+		return result
+	}
+	resource := func(resourceType string, after map[string]interface{}) *terraform.ResourceChange {
+		return &terraform.ResourceChange{
+			Address: resourceType + ".cache",
+			Type:    resourceType,
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   after,
+		}
+	}
+
+	t.Run("prices clusters for their engine", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{}, resource("aws_elasticache_cluster", map[string]interface{}{
+			"engine":          "memcached",
+			"node_type":       "cache.r6g.large",
+			"num_cache_nodes": float64(3),
+		}))
+		if assert.Len(t, result.Resources, 1) {
+			assert.Equal(t, "Cache node usage (cache.r6g.large, Memcached)", result.Resources[0].Components[0].Name)
+			assert.Equal(t, "memcached", result.Resources[0].Components[0].SKU)
+			assert.InDelta(t, 3*0.206*730, result.TotalMonthlyCost, 1e-9)
+		}
+	})
+
+	t.Run("prices replication groups by shards and replicas", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{}, resource("aws_elasticache_replication_group", map[string]interface{}{
+			"engine":                  "valkey",
+			"node_type":               "cache.r6g.large",
+			"num_node_groups":         float64(3),
+			"replicas_per_node_group": float64(2),
+		}))
+		if assert.Len(t, result.Resources, 1) {
+			assert.Equal(t, "valkey", result.Resources[0].Components[0].SKU)
+			assert.InDelta(t, 9*730, result.Resources[0].Components[0].MonthlyQuantity, 1e-9)
+		}
+
+		// Groups without cluster mode run num_cache_clusters nodes, and default to the Redis engine.
+		result = estimate(t, &UsageEstimates{}, resource("aws_elasticache_replication_group", map[string]interface{}{
+			"node_type":          "cache.r6g.large",
+			"num_cache_clusters": float64(2),
+		}))
+		assert.InDelta(t, 2*0.206*730, result.TotalMonthlyCost, 1e-9)
+		assert.Equal(t, "redis", result.Resources[0].Components[0].SKU)
+	})
+
+	t.Run("prices clusters that join a replication group with the group's node type and engine", func(t *testing.T) {
+		group := resource("aws_elasticache_replication_group", map[string]interface{}{
+			"replication_group_id": "sessions",
+			"engine":               "valkey",
+			"node_type":            "cache.r6g.large",
+			"num_cache_clusters":   float64(1),
+		})
+		member := &terraform.ResourceChange{
+			Address: "aws_elasticache_cluster.replica",
+			Type:    "aws_elasticache_cluster",
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   map[string]interface{}{"replication_group_id": "sessions"},
+		}
+		result := estimate(t, &UsageEstimates{}, group, member)
+		if assert.Len(t, result.Resources, 2) {
+			assert.Equal(t, "valkey", result.Resources[1].Components[0].SKU)
+		}
+	})
+
+	t.Run("prices serverless caches from storage and ECPU usage estimates", func(t *testing.T) {
+		usage := &UsageEstimates{ElastiCache: ElastiCacheUsage{ServerlessStorageGB: 10, ServerlessMonthlyECPUs: 1000000000}}
+		result := estimate(t, usage, resource("aws_elasticache_serverless_cache", map[string]interface{}{"engine": "redis"}))
+		if assert.Len(t, result.Resources, 1) {
+			components := result.Resources[0].Components
+			assert.Equal(t, "Data stored", components[0].Name)
+			assert.InDelta(t, 10*730*0.125, components[0].MonthlyCost, 1e-9)
+			assert.Equal(t, "ElastiCache Processing Units", components[1].Name)
+			assert.InDelta(t, 3.4, components[1].MonthlyCost, 1e-9)
+		}
+
+		// Caches are charged for the minimum data stored of their engine, within their data storage limit.
+		result = estimate(t, &UsageEstimates{}, resource("aws_elasticache_serverless_cache", map[string]interface{}{"engine": "valkey"}))
+		assert.InDelta(t, 0.1*730*0.084, result.TotalMonthlyCost, 1e-9)
+		result = estimate(t, &UsageEstimates{ElastiCache: ElastiCacheUsage{ServerlessStorageGB: 50}}, resource("aws_elasticache_serverless_cache", map[string]interface{}{
+			"engine": "redis",
+			"cache_usage_limits": []interface{}{map[string]interface{}{
+				"data_storage": []interface{}{map[string]interface{}{"maximum": float64(20), "unit": "GB"}},
+			}},
+		}))
+		assert.InDelta(t, 20*730*0.125, result.TotalMonthlyCost, 1e-9)
+	})
+
+	t.Run("skips resources with an unknown engine", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{}, resource("aws_elasticache_replication_group", map[string]interface{}{
+			"engine":    "dragonfly",
+			"node_type": "cache.r6g.large",
+		}))
+		assert.Empty(t, result.Resources)
+		assert.Equal(t, SkipReasonMissingAttribute, result.Skipped[0].Reason)
+	})
+}
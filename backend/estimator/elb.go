@@ -0,0 +1,182 @@
+package estimator
+
+import (
+	"fmt"
+	"math"
+
+	"cloudcostguard/backend/pricing"
+)
+
+func init() {
+	MustRegister(NewCalculator("aws_lb", []string{"AWSELB"}, nil, costForELB))
+	MustRegister(NewCalculator("aws_elb", []string{"AWSELB"}, nil, costForClassicELB))
+}
+
+// elbType describes how a type of Elastic Load Balancer is priced.
+type elbType struct {
+	// Operation is the operation of the load balancer's products in the price list (e.g., "LoadBalancing:Network").
+	Operation string
+	// Group is the group of the load balancer's hourly product in price lists without operations.
+	Group string
+	// CapacityUnit is the name of the load balancer's capacity unit (e.g., "NLCU"), or empty if it has none.
+	CapacityUnit string
+	// Capacity is the usage covered by one capacity unit for an hour.
+	Capacity elbCapacity
+}
+
+// elbCapacity is the usage covered by one load balancer capacity unit for an hour.
+// A dimension of zero is not part of the capacity unit.
+type elbCapacity struct {
+	NewConnectionsPerSecond  float64
+	ActiveConnections        float64
+	ProcessedGBPerHour       float64
+	RuleEvaluationsPerSecond float64
+}
+
+// elbTypes maps the load_balancer_type attribute of an aws_lb resource to how it is priced.
+// The capacity of network load balancer units is the one for TCP traffic.
+var elbTypes = map[string]elbType{
+	"application": {
+		Operation:    "LoadBalancing:Application",
+		Group:        "ELB-Application",
+		CapacityUnit: "LCU",
+		Capacity:     elbCapacity{NewConnectionsPerSecond: 25, ActiveConnections: 3000, ProcessedGBPerHour: 1, RuleEvaluationsPerSecond: 1000},
+	},
+	"network": {
+		Operation:    "LoadBalancing:Network",
+		Group:        "ELB-Network",
+		CapacityUnit: "NLCU",
+		Capacity:     elbCapacity{NewConnectionsPerSecond: 800, ActiveConnections: 100000, ProcessedGBPerHour: 1},
+	},
+	"gateway": {
+		Operation:    "LoadBalancing:Gateway",
+		Group:        "ELB-Gateway",
+		CapacityUnit: "GLCU",
+		Capacity:     elbCapacity{NewConnectionsPerSecond: 600, ActiveConnections: 60000, ProcessedGBPerHour: 1},
+	},
+	"classic": {
+		Operation: "LoadBalancing",
+		Group:     "ELB-Classic",
+	},
+}
+
+// costForELB calculates the cost of an AWS Elastic Load Balancer of the application, network or gateway type.
+// It includes the hourly price and the capacity units used, derived from the load balancer usage estimates.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its usage estimates may include the connections,
+//        processed data and rule evaluations of the load balancer.
+//   attributes: The attributes of the ELB resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the ELB.
+//   An error if the load balancer type is unknown or the pricing data cannot be found.
+func costForELB(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	lbType, _ := attributes["load_balancer_type"].(string)
+	if lbType == "" {
+		lbType = "application"
+	}
+	if lbType == "classic" {
+		return nil, fmt.Errorf("%w: unknown load balancer type %q", ErrMissingAttribute, lbType)
+	}
+	return elbCost(ctx, lbType)
+}
+
+// costForClassicELB calculates the cost of an AWS Classic Load Balancer.
+// It includes the hourly price and the data processing price.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its usage estimates may include the data processed by the
+//        load balancer.
+//   attributes: The attributes of the Classic Load Balancer resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the Classic Load Balancer.
+//   An error if the pricing data cannot be found.
+func costForClassicELB(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	return elbCost(ctx, "classic")
+}
+
+// elbCost prices a load balancer of a known type.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   lbType: The type of the load balancer: "application", "network", "gateway" or "classic".
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the load balancer.
+//   An error if the load balancer type is unknown or the pricing data cannot be found.
+func elbCost(ctx *CalculationContext, lbType string) (*Cost, error) {
+	elb, ok := elbTypes[lbType]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown load balancer type %q", ErrMissingAttribute, lbType)
+	}
+	hourlySKUs := elbUsageProducts(ctx, elb, "LoadBalancerUsage")
+	// Price lists without operations identify the hourly product of each type by its group.
+	hourlySKUs = append(hourlySKUs, filterSKUs(ctx.PriceList, ctx.PriceList.Index().Lookup("AWSELB", ctx.Location, pricing.AttrGroup, elb.Group), func(attr pricing.ProductAttributes) bool {
+		return attr.Operation == ""
+	})...)
+	sku, price, err := ctx.MatchSKU("Load Balancer type: "+lbType, hourlySKUs)
+	if err != nil {
+		return nil, err
+	}
+	components := []CostComponent{hourlyComponent(fmt.Sprintf("Load balancer usage (%s)", lbType), sku, 1, price)}
+	if ctx.Usage == nil {
+		return newCost(components...), nil
+	}
+
+	if elb.CapacityUnit == "" {
+		if ctx.Usage.LB.ProcessedGB > 0 {
+			dataSKU, _, err := ctx.MatchSKU("Load Balancer data processing: "+lbType, elbUsageProducts(ctx, elb, "DataProcessing-Bytes"))
+			if err != nil {
+				return nil, err
+			}
+			dataComponents, err := tieredComponents(ctx, "Data processed", "GB", dataSKU, float64(ctx.Usage.LB.ProcessedGB))
+			if err != nil {
+				return nil, err
+			}
+			components = append(components, dataComponents...)
+		}
+		return newCost(components...), nil
+	}
+
+	if units := elb.Capacity.units(ctx.Usage); units > 0 {
+		unitSKU, unitPrice, err := ctx.MatchSKU(fmt.Sprintf("Load Balancer %s: %s", elb.CapacityUnit, lbType), elbUsageProducts(ctx, elb, "LCUUsage"))
+		if err != nil {
+			return nil, err
+		}
+		component := hourlyComponent(fmt.Sprintf("Load balancer capacity units (%s)", elb.CapacityUnit), unitSKU, units, unitPrice)
+		component.Unit = elb.CapacityUnit + "-hour"
+		components = append(components, component)
+	}
+	return newCost(components...), nil
+}
+
+// elbUsageProducts returns the products of a load balancer type with a usage type, with or without a region prefix.
+func elbUsageProducts(ctx *CalculationContext, elb elbType, usageType string) []string {
+	return filterSKUs(ctx.PriceList, usageTypeSKUs(ctx, "AWSELB", ctx.Location, usageType), func(attr pricing.ProductAttributes) bool {
+		return attr.Operation == elb.Operation || attr.Group == elb.Group
+	})
+}
+
+// units calculates the average number of capacity units a load balancer uses per hour, which is the number needed
+// by the dimension with the highest usage.
+//
+// Parameters:
+//   usage: The usage estimates of the load balancer.
+//
+// Returns:
+//   The average number of capacity units, or 0 if no usage is estimated.
+func (c elbCapacity) units(usage *UsageEstimates) float64 {
+	units := 0.0
+	dimension := func(used, perUnit float64) {
+		if perUnit > 0 {
+			units = math.Max(units, used/perUnit)
+		}
+	}
+	dimension(float64(usage.LB.NewConnectionsPerSecond), c.NewConnectionsPerSecond)
+	dimension(float64(usage.LB.ActiveConnections), c.ActiveConnections)
+	dimension(float64(usage.LB.ProcessedGB)/hoursPerMonth, c.ProcessedGBPerHour)
+	dimension(float64(usage.LB.RuleEvaluationsPerSecond), c.RuleEvaluationsPerSecond)
+	return units
+}
@@ -0,0 +1,106 @@
+package estimator
+
+import (
+	"testing"
+
+	"cloudcostguard/backend/pricing"
+	"cloudcostguard/backend/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+// createELBPriceList creates a price list with hourly, capacity unit and data processing prices of every load balancer type.
+func createELBPriceList() *pricing.PriceList {
+	priceList := pricing.NewPriceList()
+	add := func(sku, operation, usageType, price string) {
+		addMockProduct(priceList, sku, pricing.ProductAttributes{
+			ServiceCode: "AWSELB",
+			Location:    "US East (N. Virginia)",
+			Operation:   operation,
+			UsageType:   usageType,
+		}, price)
+	}
+	add("alb", "LoadBalancing:Application", "LoadBalancerUsage", "0.0225")
+	add("alb-lcu", "LoadBalancing:Application", "LCUUsage", "0.008")
+	add("nlb", "LoadBalancing:Network", "LoadBalancerUsage", "0.0225")
+	add("nlb-lcu", "LoadBalancing:Network", "LCUUsage", "0.006")
+	add("gwlb", "LoadBalancing:Gateway", "LoadBalancerUsage", "0.0125")
+	add("gwlb-lcu", "LoadBalancing:Gateway", "LCUUsage", "0.004")
+	add("clb", "LoadBalancing", "LoadBalancerUsage", "0.025")
+	add("clb-data", "LoadBalancing", "DataProcessing-Bytes", "0.008")
+	return priceList
+}
+
+func TestELBPricing(t *testing.T) {
+	priceList := createELBPriceList()
+	estimate := func(t *testing.T, resourceType string, after map[string]interface{}, usage *UsageEstimates) *EstimationResponse {
+		plan := &terraform.Plan{ResourceChanges: []*terraform.ResourceChange{{
+			Address: resourceType + ".test",
+			Type:    resourceType,
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   after,
+		}}}
+		result, err := Estimate(plan, priceList, "us-east-1", usage)
+		assert.NoError(t, err)
+		return result
+	}
+
+	t.Run("prices every load balancer type", func(t *testing.T) {
+		skus := map[string]string{"": "alb", "application": "alb", "network": "nlb", "gateway": "gwlb"}
+		for lbType, sku := range skus {
+			result := estimate(t, "aws_lb", map[string]interface{}{"load_balancer_type": lbType}, &UsageEstimates{})
+			if assert.Len(t, result.Resources, 1, lbType) {
+				assert.Equal(t, sku, result.Resources[0].Components[0].SKU, lbType)
+			}
+		}
+
+		result := estimate(t, "aws_elb", map[string]interface{}{}, &UsageEstimates{})
+		assert.InDelta(t, 0.025*730, result.TotalMonthlyCost, 0.001)
+	})
+
+	t.Run("charges capacity units for the dimension with the highest usage", func(t *testing.T) {
+		// 73,000 GB per month is 100 GB per hour, which needs more NLCUs than 8,000 new connections per second.
+		usage := &UsageEstimates{LB: LBUsage{NewConnectionsPerSecond: 8000, ActiveConnections: 50000, ProcessedGB: 73000}}
+		result := estimate(t, "aws_lb", map[string]interface{}{"load_balancer_type": "network"}, usage)
+		components := result.Resources[0].Components
+		if assert.Len(t, components, 2) {
+			assert.Equal(t, "Load balancer capacity units (NLCU)", components[1].Name)
+			assert.Equal(t, "NLCU-hour", components[1].Unit)
+			assert.InDelta(t, 100*730, components[1].MonthlyQuantity, 0.001)
+			assert.InDelta(t, 0.0225*730+0.006*100*730, result.TotalMonthlyCost, 0.001)
+		}
+	})
+
+	t.Run("counts rule evaluations for application load balancers only", func(t *testing.T) {
+		usage := &UsageEstimates{LB: LBUsage{RuleEvaluationsPerSecond: 5000}}
+		result := estimate(t, "aws_lb", map[string]interface{}{"load_balancer_type": "application"}, usage)
+		assert.InDelta(t, 0.0225*730+0.008*5*730, result.TotalMonthlyCost, 0.001)
+
+		result = estimate(t, "aws_lb", map[string]interface{}{"load_balancer_type": "gateway"}, usage)
+		assert.Len(t, result.Resources[0].Components, 1)
+	})
+
+	t.Run("charges data processed by classic load balancers", func(t *testing.T) {
+		result := estimate(t, "aws_elb", map[string]interface{}{}, &UsageEstimates{LB: LBUsage{ProcessedGB: 1000}})
+		assert.InDelta(t, 0.025*730+0.008*1000, result.TotalMonthlyCost, 0.001)
+	})
+
+	t.Run("matches hourly products by group in price lists without operations", func(t *testing.T) {
+		legacy := pricing.NewPriceList()
+		addMockProduct(legacy, "ELB_APP", pricing.ProductAttributes{ServiceCode: "AWSELB", Group: "ELB-Application", Location: "US East (N. Virginia)"}, "0.0225")
+		plan := &terraform.Plan{ResourceChanges: []*terraform.ResourceChange{{
+			Address: "aws_lb.test",
+			Type:    "aws_lb",
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   map[string]interface{}{},
+		}}}
+		result, err := Estimate(plan, legacy, "us-east-1", &UsageEstimates{})
+		assert.NoError(t, err)
+		assert.InDelta(t, 0.0225*730, result.TotalMonthlyCost, 0.001)
+	})
+
+	t.Run("skips load balancers of an unknown type", func(t *testing.T) {
+		result := estimate(t, "aws_lb", map[string]interface{}{"load_balancer_type": "classic"}, &UsageEstimates{})
+		assert.Empty(t, result.Resources)
+		assert.Equal(t, SkipReasonMissingAttribute, result.Skipped[0].Reason)
+	})
+}
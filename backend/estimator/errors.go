@@ -0,0 +1,56 @@
+package estimator
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors returned when a resource cannot be priced.
+// Errors returned by calculators wrap one of these so that the estimator can classify why a resource was skipped.
+var (
+	// ErrUnsupportedType means no calculator is registered for the resource type.
+	ErrUnsupportedType = errors.New("unsupported resource type")
+	// ErrMissingAttribute means an attribute needed to price the resource is missing or invalid.
+	ErrMissingAttribute = errors.New("missing attribute")
+	// ErrPriceNotFound means no price matching the resource was found in the price list.
+	ErrPriceNotFound = errors.New("could not find pricing")
+	// ErrBilledSeparately means the resource is not billed itself, but through another resource it runs on.
+	ErrBilledSeparately = errors.New("billed separately")
+	// ErrInvalidUsage means the usage estimates of the resource are inconsistent.
+	ErrInvalidUsage = errors.New("invalid usage estimate")
+)
+
+// missingAttributeError returns an error wrapping ErrMissingAttribute for an attribute.
+//
+// Parameters:
+//   name: The name of the missing attribute.
+//
+// Returns:
+//   An error wrapping ErrMissingAttribute.
+func missingAttributeError(name string) error {
+	return fmt.Errorf("%w: %s", ErrMissingAttribute, name)
+}
+
+// skipReason classifies an estimation error into a skip reason code.
+//
+// Parameters:
+//   err: The error returned while pricing a resource.
+//
+// Returns:
+//   The skip reason code for the error.
+func skipReason(err error) string {
+	switch {
+	case errors.Is(err, ErrUnsupportedType):
+		return SkipReasonUnsupportedType
+	case errors.Is(err, ErrMissingAttribute):
+		return SkipReasonMissingAttribute
+	case errors.Is(err, ErrPriceNotFound):
+		return SkipReasonPriceNotFound
+	case errors.Is(err, ErrBilledSeparately):
+		return SkipReasonBilledSeparately
+	case errors.Is(err, ErrInvalidUsage):
+		return SkipReasonInvalidUsage
+	default:
+		return SkipReasonError
+	}
+}
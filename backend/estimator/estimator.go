@@ -4,19 +4,109 @@ package estimator
 import (
 	"fmt"
 	"strconv"
-	"strings"
+	"time"
+
 	"cloudcostguard/backend/pricing"
 	"cloudcostguard/backend/terraform"
 )
 
+// hoursPerMonth is the number of hours used to convert hourly prices to monthly costs.
+const hoursPerMonth = 730
+
 // Cost represents a monetary cost with a value, a unit and a breakdown.
+// Costs built from components with newCost are monthly, and their breakdown is derived from the components.
 type Cost struct {
-	Value    float64
-	Unit     string // "hourly" or "monthly"
-	Breakdown string
+	Value      float64
+	Unit       string // "hourly" or "monthly"
+	Breakdown  string
+	Components []CostComponent
+	// Commitment compares the cost with its cost under a reserved commitment, if the resource has reserved terms.
+	Commitment *CommitmentComparison
+	// Scaling is set by calculators of resources whose capacity scales between a minimum and a maximum.
+	Scaling *Scaling
+	// Range is the cost of the resource at its minimum and maximum capacity, if it has a Scaling.
+	Range *CostRange
+}
+
+// Scaling describes how the capacity of a resource, such as an Auto Scaling group, can change.
+// The cost of the resource is recalculated with the capacity attribute, or the usage estimate set by Usage, set to the
+// minimum and maximum capacity.
+type Scaling struct {
+	// Attribute is the resource attribute holding the capacity the cost was calculated for (e.g., "desired_capacity").
+	Attribute string
+	// Usage sets the capacity in the usage estimates of the resource, for resources whose capacity is estimated
+	// rather than held in an attribute. When it is set, Attribute is not used.
+	Usage func(usage *UsageEstimates, capacity float64)
+	// Min is the minimum capacity of the resource.
+	Min float64
+	// Max is the maximum capacity of the resource.
+	Max float64
+}
+
+// Monthly returns the cost converted to a monthly value.
+func (c *Cost) Monthly() float64 {
+	if c.Unit == "hourly" {
+		return c.Value * hoursPerMonth
+	}
+	return c.Value
+}
+
+// ListMonthly returns the monthly value of the cost at list prices, before discounts.
+func (c *Cost) ListMonthly() float64 {
+	if len(c.Components) == 0 {
+		return c.Monthly()
+	}
+	total := 0.0
+	for _, component := range c.Components {
+		if component.Discount != "" {
+			total += component.ListMonthlyCost
+		} else {
+			total += component.MonthlyCost
+		}
+	}
+	return total
+}
+
+// changeCost holds the cost of a resource before and after a change.
+type changeCost struct {
+	// Action is the change action (e.g., "create" or "update").
+	Action string
+	// Before is the cost of the resource before the change, or nil if the resource does not exist before it.
+	Before *Cost
+	// After is the cost of the resource after the change, or nil if the resource does not exist after it.
+	After *Cost
+}
+
+// Options configures how an estimate is calculated.
+type Options struct {
+	// MaxPricingAge is the age after which the pricing data is reported as stale. Zero disables the check.
+	MaxPricingAge time.Duration
+	// Now returns the current time. It defaults to time.Now.
+	Now func() time.Time
+	// PricingModels maps resource types (e.g., "aws_instance") to the pricing model used for them.
+	// Resource types without an entry are priced on demand.
+	PricingModels map[string]PricingModel
+	// Discounts is the discount policy applied to the list prices, or nil to use list prices.
+	Discounts *DiscountPolicy
+}
+
+// Estimate calculates the estimated monthly cost impact of a Terraform plan with the default options.
+// See EstimateWithOptions.
+//
+// Parameters:
+//   plan: The Terraform plan to estimate the cost of.
+//   priceList: The list of AWS prices to use for the estimation.
+//   region: The AWS region to use for pricing.
+//   usage: A struct containing usage estimates for various resources, optionally per resource address.
+//
+// Returns:
+//   A pointer to an EstimationResponse struct containing a detailed breakdown of the estimated monthly cost impact.
+//   An error if the estimation fails.
+func Estimate(plan *terraform.Plan, priceList *pricing.PriceList, region string, usage *UsageEstimates) (*EstimationResponse, error) {
+	return EstimateWithOptions(plan, priceList, region, usage, Options{})
 }
 
-// Estimate calculates the estimated monthly cost impact of a Terraform plan.
+// EstimateWithOptions calculates the estimated monthly cost impact of a Terraform plan.
 // It iterates through the resource changes in the plan, estimates the cost of each change,
 // and aggregates them into a total monthly cost.
 //
@@ -24,495 +114,426 @@ type Cost struct {
 //   plan: The Terraform plan to estimate the cost of.
 //   priceList: The list of AWS prices to use for the estimation.
 //   region: The AWS region to use for pricing.
-//   usage: A struct containing usage estimates for various resources.
+//   usage: A struct containing usage estimates for various resources, optionally per resource address.
+//   opts: The options for the estimate.
 //
 // Returns:
 //   A pointer to an EstimationResponse struct containing a detailed breakdown of the estimated monthly cost impact.
+//   Resources that cannot be priced are listed in the response's Skipped field and excluded from the totals.
 //   An error if the estimation fails.
-func Estimate(plan *terraform.Plan, priceList *pricing.PriceList, region string, usage *UsageEstimates) (*EstimationResponse, error) {
+func EstimateWithOptions(plan *terraform.Plan, priceList *pricing.PriceList, region string, usage *UsageEstimates, opts Options) (*EstimationResponse, error) {
 	location := toLocation(region)
 	response := &EstimationResponse{
 		Currency:  "USD",
 		Resources: []ResourceCost{},
+		Warnings:  []Warning{},
+		Skipped:   []SkippedResource{},
+		Pricing:   pricingSource(priceList),
+	}
+	if warning, stale := checkPricingAge(priceList, opts); stale {
+		response.Warnings = append(response.Warnings, warning)
 	}
 
 	for _, rc := range plan.ResourceChanges {
-		cost, err := estimateResourceChange(rc, priceList, location, usage, plan)
+		ctx := &CalculationContext{
+			ResourceChange: rc,
+			PriceList:      priceList,
+			Location:       location,
+			Usage:          usage.For(rc.Address),
+			Plan:           plan,
+			PricingModel:   opts.PricingModels[rc.Type],
+			Discounts:      opts.Discounts,
+		}
+		action := changeAction(rc)
+		change, err := estimateResourceChange(ctx, action)
+		if action == ActionNoOp {
+			// Unchanged resources only contribute to the baseline; resources that cannot be priced are left out of it.
+			if err == nil {
+				response.BaselineMonthlyCost += change.After.Monthly()
+				response.BaselineResources++
+			}
+			continue
+		}
+		response.Warnings = append(response.Warnings, ctx.warnings...)
 		if err != nil {
-			fmt.Printf("Warning: skipping unsupported resource %s: %v\n", rc.Address, err)
+			response.Coverage.TotalResources++
+			response.Skipped = append(response.Skipped, SkippedResource{
+				Address: rc.Address,
+				Type:    rc.Type,
+				Reason:  skipReason(err),
+				Message: err.Error(),
+			})
 			continue
 		}
-
-		monthlyCost := cost.Value
-		if cost.Unit == "hourly" {
-			monthlyCost *= 730
+		if change == nil {
+			continue
+		}
+		response.Coverage.TotalResources++
+		response.Coverage.PricedResources++
+
+		resource := ResourceCost{
+			Address:      rc.Address,
+			Action:       change.Action,
+			Imported:     rc.IsImport(),
+			PricingModel: ctx.PricingModel.String(),
+		}
+		if rc.IsMove() {
+			resource.PreviousAddress = rc.PreviousAddress
 		}
+		listMonthlyCost := 0.0
+		if change.Before != nil {
+			listMonthlyCost -= change.Before.ListMonthly()
+			resource.BeforeMonthlyCost = change.Before.Monthly()
+			resource.BeforeCostBreakdown = change.Before.Breakdown
+			resource.BeforeComponents = change.Before.Components
+			resource.CostBreakdown = change.Before.Breakdown
+			resource.Components = change.Before.Components
+			resource.Commitment = change.Before.Commitment
+			resource.MonthlyCostRange = change.Before.Range
+		}
+		if change.After != nil {
+			listMonthlyCost += change.After.ListMonthly()
+			resource.AfterMonthlyCost = change.After.Monthly()
+			resource.CostBreakdown = change.After.Breakdown
+			resource.Components = change.After.Components
+			resource.Commitment = change.After.Commitment
+			resource.MonthlyCostRange = change.After.Range
+		}
+		resource.MonthlyCost = resource.AfterMonthlyCost - resource.BeforeMonthlyCost
+		resource.ListMonthlyCost = listMonthlyCost
 
-		if monthlyCost != 0 {
-			response.Resources = append(response.Resources, ResourceCost{
-				Address:      rc.Address,
-				MonthlyCost:  monthlyCost,
-				CostBreakdown: cost.Breakdown,
-			})
+		// Resources that cost nothing before and after the change, such as launch templates, are left out. Updates
+		// that do not change the cost of a resource are listed.
+		if resource.BeforeMonthlyCost != 0 || resource.AfterMonthlyCost != 0 || resource.ListMonthlyCost != 0 {
+			response.Resources = append(response.Resources, resource)
 		}
 	}
 
 	for _, resource := range response.Resources {
-		response.TotalMonthlyCost += resource.MonthlyCost
+		response.ListMonthlyCost += resource.ListMonthlyCost
+		if resource.MonthlyCost > 0 {
+			response.MonthlyCostAdded += resource.MonthlyCost
+		} else {
+			response.MonthlyCostRemoved -= resource.MonthlyCost
+		}
 	}
+	response.Warnings = append(response.Warnings, unmatchedDataTransfer(plan, usage)...)
+	response.Coverage.SkippedResources = len(response.Skipped)
+	response.NetMonthlyCostChange = response.MonthlyCostAdded - response.MonthlyCostRemoved
+	response.TotalMonthlyCost = response.NetMonthlyCostChange
 
 	response.Recommendations = GenerateRecommendations(plan, usage)
 
 	return response, nil
 }
 
-// estimateResourceChange calculates the cost impact of a single resource change.
-// It determines whether the resource is being created, deleted, or updated, and calculates the cost delta accordingly.
+// changeAction classifies the Terraform actions of a resource change.
+// Data sources, reads and forgotten resources have no cost impact. Replacements are recognized in either order,
+// so create_before_destroy (["create", "delete"]) is treated like ["delete", "create"]. Imported resources already
+// exist, so an import that would otherwise create the resource is treated as unchanged.
 //
 // Parameters:
-//   rc: The resource change to estimate the cost of.
-//   priceList: The list of AWS prices to use for the estimation.
-//   region: The AWS region to use for pricing.
-//   usage: A struct containing usage estimates for various resources.
-//   plan: The full Terraform plan.
+//   rc: The resource change to classify.
+//
+// Returns:
+//   The change action, or an empty string if the change has no cost impact.
+func changeAction(rc *terraform.ResourceChange) string {
+	if rc.IsData() {
+		return ""
+	}
+
+	change := rc.Change
+	switch {
+	case change.Is(terraform.ActionNoOp):
+		return ActionNoOp
+	case change.Is(terraform.ActionCreate):
+		if rc.IsImport() {
+			return ActionNoOp
+		}
+		return ActionCreate
+	case change.Is(terraform.ActionDelete):
+		return ActionDelete
+	case change.Is(terraform.ActionUpdate):
+		return ActionUpdate
+	case change.IsReplace():
+		return ActionReplace
+	default:
+		return ""
+	}
+}
+
+// estimateResourceChange calculates the cost of a single resource change.
+// It determines whether the resource is being created, deleted, updated, replaced or left unchanged, and calculates
+// the cost of the resource before and after the change accordingly. Unchanged resources only have an after cost,
+// which counts toward the baseline.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change to estimate the cost of.
+//   action: The change action of the resource change, as returned by changeAction.
 //
 // Returns:
-//   A pointer to a Cost struct representing the cost delta of the resource change.
+//   A pointer to a changeCost struct holding the before and after costs, or nil if the change has no cost impact.
 //   An error if the estimation fails.
-func estimateResourceChange(rc *terraform.ResourceChange, priceList *pricing.PriceList, region string, usage *UsageEstimates, plan *terraform.Plan) (*Cost, error) {
-	costChange := &Cost{Value: 0, Unit: "monthly"} // Default to monthly for aggregation
-	actions := rc.Change.Actions
-	isCreate := len(actions) == 1 && actions[0] == "create"
-	isDelete := len(actions) == 1 && actions[0] == "delete"
-	isUpdate := (len(actions) == 1 && actions[0] == "update") || (len(actions) == 2 && actions[0] == "delete" && actions[1] == "create")
-
-	if isCreate || isUpdate {
-		cost, err := getResourceCost(rc, rc.After, priceList, region, usage, plan)
+func estimateResourceChange(ctx *CalculationContext, action string) (*changeCost, error) {
+	rc := ctx.ResourceChange
+	if action == "" {
+		return nil, nil
+	}
+	change := &changeCost{Action: action}
+
+	if action == ActionNoOp {
+		attributes := rc.After
+		if attributes == nil {
+			attributes = rc.Before
+		}
+		cost, err := getResourceCost(ctx, attributes)
 		if err != nil {
 			return nil, err
 		}
-		if cost.Unit == "hourly" {
-			costChange.Value += cost.Value * 730
-		} else {
-			costChange.Value += cost.Value
-		}
+		change.After = cost
+		return change, nil
 	}
 
-	if isDelete || isUpdate {
-		cost, err := getResourceCost(rc, rc.Before, priceList, region, usage, plan)
+	if action != ActionDelete {
+		cost, err := getResourceCost(ctx, rc.After)
 		if err != nil {
 			return nil, err
 		}
-		if cost.Unit == "hourly" {
-			costChange.Value -= cost.Value * 730
-		} else {
-			costChange.Value -= cost.Value
+		change.After = cost
+	}
+
+	if action != ActionCreate {
+		cost, err := getResourceCost(ctx, rc.Before)
+		if err != nil {
+			return nil, err
 		}
+		change.Before = cost
 	}
 
-	return costChange, nil
+	return change, nil
 }
 
 // getResourceCost calculates the cost of a single resource based on its attributes.
-// It delegates to the appropriate cost function based on the resource type.
+// It delegates to the calculator registered for the resource type.
 //
 // Parameters:
-//   rc: The resource change to get the cost of.
+//   ctx: The calculation context for the resource change.
 //   attributes: The attributes of the resource.
-//   priceList: The list of AWS prices to use for the estimation.
-//   region: The AWS region to use for pricing.
-//   usage: A struct containing usage estimates for various resources.
-//   plan: The full Terraform plan.
 //
 // Returns:
 //   A pointer to a Cost struct representing the cost of the resource.
 //   An error if the estimation fails.
-func getResourceCost(rc *terraform.ResourceChange, attributes map[string]interface{}, priceList *pricing.PriceList, region string, usage *UsageEstimates, plan *terraform.Plan) (*Cost, error) {
-	if priceList == nil {
-		return nil, fmt.Errorf("pricing data is nil")
-	}
-
-	switch rc.Type {
-	case "aws_instance":
-		return costForEC2(attributes, priceList, region)
-	case "aws_db_instance":
-		price, err := costForRDS(attributes, priceList, region)
-		return &Cost{Value: price, Unit: "hourly"}, err
-	case "aws_ebs_volume":
-		price, err := costForEBS(attributes, priceList, region)
-		return &Cost{Value: price, Unit: "monthly"}, err
-	case "aws_lb":
-		price, err := costForELB(attributes, priceList, region)
-		return &Cost{Value: price, Unit: "hourly"}, err
-	case "aws_s3_bucket":
-		return costForS3(attributes, priceList, region, usage)
-	case "aws_nat_gateway":
-		price, err := costForNATGateway(attributes, priceList, region, usage)
-		return &Cost{Value: price, Unit: "hourly"}, err
-	case "aws_lambda_function":
-		return costForLambda(attributes, priceList, region, usage)
-	case "aws_ecs_service":
-		return costForECSService(rc, attributes, priceList, region, plan)
-	case "aws_ecs_task_definition":
-		// Cost is calculated as part of the ECS service, not standalone.
-		return &Cost{Value: 0, Unit: "monthly"}, nil
-	case "aws_eks_cluster":
-		return costForEKS(attributes, priceList, region)
-	case "aws_eks_node_group":
-		return costForEKSNodeGroup(attributes, priceList, region)
-	case "aws_elasticache_cluster":
-		return costForElastiCache(attributes, priceList, region)
-	default:
-		return nil, fmt.Errorf("unsupported resource type: %s", rc.Type)
+func getResourceCost(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	if ctx.PriceList == nil {
+		return nil, fmt.Errorf("%w: pricing data is nil", ErrPriceNotFound)
 	}
-}
-
-// costForNATGateway calculates the cost of an AWS NAT Gateway.
-// It includes both the hourly price and the data processing price.
-//
-// Parameters:
-//   attributes: The attributes of the NAT Gateway resource.
-//   priceList: The list of AWS prices.
-//   region: The AWS region.
-//   usage: Usage estimates, which may include NAT Gateway data processing volume.
-//
-// Returns:
-//   The estimated hourly cost of the NAT Gateway.
-//   An error if the pricing data cannot be found.
-func costForNATGateway(attributes map[string]interface{}, priceList *pricing.PriceList, region string, usage *UsageEstimates) (float64, error) {
-	var hourlyPrice, dataProcessingPrice float64
-	var err error
-
-	for sku, product := range priceList.Products {
-		attr := product.Attributes
-		if attr.ServiceCode != "AmazonVPC" || attr.Location != region {
-			continue
-		}
-
-		if attr.Group == "NAT Gateway" {
-			hourlyPrice, err = getPriceFromTerms(sku, priceList)
-			if err != nil {
-				return 0, fmt.Errorf("could not get hourly price for NAT Gateway: %w", err)
-			}
-		}
 
-		if strings.Contains(attr.UsageType, "NatGateway-Bytes") {
-			dataProcessingPrice, err = getPriceFromTerms(sku, priceList)
-			if err != nil {
-				return 0, fmt.Errorf("could not get data processing price for NAT Gateway: %w", err)
-			}
-		}
+	calc, ok := DefaultRegistry.Lookup(ctx.ResourceChange.Type)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, ctx.ResourceChange.Type)
 	}
 
-	if hourlyPrice == 0 {
-		return 0, fmt.Errorf("could not find hourly pricing for NAT Gateway")
+	if err := checkRequiredAttributes(attributes, calc.RequiredAttributes()); err != nil {
+		return nil, err
 	}
 
-	totalHourlyCost := hourlyPrice
-	if dataProcessingPrice > 0 && usage != nil {
-		// Convert monthly GB processed to hourly GB processed
-		hourlyGBProcessed := float64(usage.NATGatewayGBProcessed) / 730
-		totalHourlyCost += hourlyGBProcessed * dataProcessingPrice
+	cost, err := priceResource(ctx, calc, attributes)
+	if err != nil {
+		return nil, err
 	}
-
-	return totalHourlyCost, nil
+	if cost.Scaling != nil {
+		cost.Range, err = costRange(ctx, calc, attributes, cost.Scaling)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return cost, nil
 }
 
-// costForEC2 calculates the cost of an AWS EC2 instance.
+// priceResource calculates the cost of a resource with its calculator, adds the data transfer sent by the resource
+// and applies the pricing model and discounts.
 //
 // Parameters:
-//   attributes: The attributes of the EC2 instance resource.
-//   priceList: The list of AWS prices.
-//   region: The AWS region.
+//   ctx: The calculation context for the resource change.
+//   calc: The calculator registered for the resource type.
+//   attributes: The attributes of the resource.
 //
 // Returns:
-//   A pointer to a Cost struct representing the hourly cost of the EC2 instance.
-//   An error if the pricing data cannot be found.
-func costForEC2(attributes map[string]interface{}, priceList *pricing.PriceList, region string) (*Cost, error) {
-	instanceType, _ := attributes["instance_type"].(string)
-	if instanceType == "" {
-		return nil, fmt.Errorf("missing instance_type")
-	}
-
-	for sku, product := range priceList.Products {
-		attr := product.Attributes
-		if attr.ServiceCode == "AmazonEC2" && attr.InstanceType == instanceType && attr.Location == region && attr.OperatingSystem == "Linux" && strings.HasPrefix(attr.UsageType, "BoxUsage") {
-			price, err := getPriceFromTerms(sku, priceList)
-			if err != nil {
-				return nil, err
-			}
-			return &Cost{
-				Value:    price,
-				Unit:     "hourly",
-				Breakdown: fmt.Sprintf("%s @ $%.4f/hr", instanceType, price),
-			}, nil
+//   A pointer to a Cost struct representing the cost of the resource.
+//   An error if the estimation fails.
+func priceResource(ctx *CalculationContext, calc ResourceCalculator, attributes map[string]interface{}) (*Cost, error) {
+	cost, err := calc.Cost(ctx, attributes)
+	if err != nil {
+		return nil, err
+	}
+	if transfer := dataTransferComponents(ctx, attributes); len(transfer) > 0 {
+		scaling := cost.Scaling
+		cost = newCost(append(costComponents(ctx, cost), transfer...)...)
+		cost.Scaling = scaling
+	}
+	for i, component := range cost.Components {
+		if component.SKU != "" && component.OfferTermCode == "" {
+			_, cost.Components[i].OfferTermCode, _ = getOnDemandTerm(component.SKU, ctx.PriceList)
 		}
 	}
-	return nil, fmt.Errorf("could not find pricing for EC2 instance type: %s", instanceType)
+	scaling := cost.Scaling
+	cost = applyDiscounts(ctx, applyPricingModel(ctx, cost))
+	cost.Scaling = scaling
+	return cost, nil
 }
 
-// costForRDS calculates the cost of an AWS RDS instance.
+// costComponents returns the components of a cost. A cost without components, such as one returned by a custom
+// calculator, is turned into a single monthly component named after its breakdown, so that its value is kept when
+// other components are added to it.
 //
 // Parameters:
-//   attributes: The attributes of the RDS instance resource.
-//   priceList: The list of AWS prices.
-//   region: The AWS region.
+//   ctx: The calculation context for the resource change.
+//   cost: The cost calculated for the resource.
 //
 // Returns:
-//   The estimated hourly cost of the RDS instance.
-//   An error if the pricing data cannot be found.
-func costForRDS(attributes map[string]interface{}, priceList *pricing.PriceList, region string) (float64, error) {
-	instanceClass, _ := attributes["instance_class"].(string)
-	if instanceClass == "" { return 0, fmt.Errorf("missing instance_class") }
-
-	for sku, product := range priceList.Products {
-		attr := product.Attributes
-		if attr.ServiceCode == "AmazonRDS" && attr.InstanceClass == instanceClass && attr.Location == region {
-			return getPriceFromTerms(sku, priceList)
-		}
+//   The components of the cost, or nil if the cost has no components and no value.
+func costComponents(ctx *CalculationContext, cost *Cost) []CostComponent {
+	if len(cost.Components) > 0 || cost.Monthly() == 0 {
+		return cost.Components
 	}
-	return 0, fmt.Errorf("could not find pricing for RDS instance class: %s", instanceClass)
+	name := cost.Breakdown
+	if name == "" {
+		name = ctx.ResourceChange.Type
+	}
+	return []CostComponent{newComponent(name, "month", "", 1, cost.Monthly())}
 }
 
-// costForEBS calculates the cost of an AWS EBS volume.
+// costRange calculates the cost of a scaling resource at its minimum and maximum capacity.
 //
 // Parameters:
-//   attributes: The attributes of the EBS volume resource.
-//   priceList: The list of AWS prices.
-//   region: The AWS region.
+//   ctx: The calculation context for the resource change.
+//   calc: The calculator registered for the resource type.
+//   attributes: The attributes of the resource.
+//   scaling: The scaling of the resource reported by the calculator.
 //
 // Returns:
-//   The estimated monthly cost of the EBS volume.
-//   An error if the pricing data cannot be found.
-func costForEBS(attributes map[string]interface{}, priceList *pricing.PriceList, region string) (float64, error) {
-	volumeType, _ := attributes["type"].(string)
-	if volumeType == "" { volumeType = "gp2" }
-	size, _ := attributes["size"].(float64)
-
-	apiName := "gp2"
-	if volumeType == "gp3" { apiName = "gp3" }
-
-	for sku, product := range priceList.Products {
-		attr := product.Attributes
-		if attr.ServiceCode == "AmazonEC2" && attr.VolumeAPIName == apiName && attr.Location == region {
-			price, err := getPriceFromTerms(sku, priceList)
-			if err != nil { continue }
-			return price * size, nil
+//   A pointer to a CostRange struct with the monthly cost at the minimum and maximum capacity.
+//   An error if the estimation fails.
+func costRange(ctx *CalculationContext, calc ResourceCalculator, attributes map[string]interface{}, scaling *Scaling) (*CostRange, error) {
+	costAt := func(capacity float64) (float64, error) {
+		scaledCtx, scaled := ctx, attributes
+		if scaling.Usage != nil {
+			usage := UsageEstimates{}
+			if ctx.Usage != nil {
+				usage = *ctx.Usage
+			}
+			scaling.Usage(&usage, capacity)
+			copied := *ctx
+			copied.Usage = &usage
+			scaledCtx = &copied
+		} else {
+			scaled = make(map[string]interface{}, len(attributes)+1)
+			for name, value := range attributes {
+				scaled[name] = value
+			}
+			scaled[scaling.Attribute] = capacity
+		}
+		cost, err := priceResource(scaledCtx, calc, scaled)
+		if err != nil {
+			return 0, err
 		}
+		return cost.Monthly(), nil
+	}
+
+	costRange := &CostRange{MinCapacity: scaling.Min, MaxCapacity: scaling.Max}
+	var err error
+	if costRange.MinMonthlyCost, err = costAt(scaling.Min); err != nil {
+		return nil, err
 	}
-	return 0, fmt.Errorf("could not find pricing for EBS volume type: %s", volumeType)
+	if costRange.MaxMonthlyCost, err = costAt(scaling.Max); err != nil {
+		return nil, err
+	}
+	return costRange, nil
 }
 
-// costForELB calculates the cost of an AWS Elastic Load Balancer.
+// pricingSource describes the price snapshot of a price list.
 //
 // Parameters:
-//   attributes: The attributes of the ELB resource.
-//   priceList: The list of AWS prices.
-//   region: The AWS region.
+//   priceList: The list of AWS prices used for the estimate.
 //
 // Returns:
-//   The estimated hourly cost of the ELB.
-//   An error if the pricing data cannot be found.
-func costForELB(attributes map[string]interface{}, priceList *pricing.PriceList, region string) (float64, error) {
-	lbType, _ := attributes["load_balancer_type"].(string)
-	if lbType == "" { lbType = "application" }
-
-	group := ""
-	if lbType == "application" { group = "ELB-Application" }
-
-	for sku, product := range priceList.Products {
-		attr := product.Attributes
-		if attr.ServiceCode == "AWSELB" && attr.Group == group && attr.Location == region {
-			return getPriceFromTerms(sku, priceList)
-		}
+//   The PricingSource for the price list.
+func pricingSource(priceList *pricing.PriceList) PricingSource {
+	source := PricingSource{Offers: []PricingOffer{}}
+	if priceList == nil {
+		return source
 	}
-	return 0, fmt.Errorf("could not find pricing for Load Balancer type: %s", lbType)
+	if !priceList.LastUpdated.IsZero() {
+		lastUpdated := priceList.LastUpdated
+		source.LastUpdated = &lastUpdated
+	}
+	for _, offer := range priceList.SortedOffers() {
+		source.Offers = append(source.Offers, PricingOffer{
+			OfferCode:       offer.OfferCode,
+			Version:         offer.Version,
+			PublicationDate: offer.PublicationDate,
+		})
+	}
+	return source
 }
 
-// getPriceFromTerms extracts the price from the terms of a product.
+// checkPricingAge reports whether the pricing data is older than the maximum age configured in the options.
+// Price lists without a last update time are never reported as stale.
 //
 // Parameters:
-//   sku: The SKU of the product.
-//   priceList: The list of AWS prices.
+//   priceList: The list of AWS prices used for the estimate.
+//   opts: The options for the estimate.
 //
 // Returns:
-//   The price of the product.
-//   An error if the price cannot be extracted.
-func getPriceFromTerms(sku string, priceList *pricing.PriceList) (float64, error) {
-	if terms, ok := priceList.Terms.OnDemand[sku]; ok {
-		for _, term := range terms {
-			for _, dim := range term.PriceDimensions {
-				price, err := strconv.ParseFloat(dim.PricePerUnit.USD, 64)
-				if err == nil {
-					return price, nil
-				}
-			}
-		}
-	}
-	return 0, fmt.Errorf("could not extract price for SKU %s", sku)
+//   A stale pricing warning, and true if the pricing data is stale.
+func checkPricingAge(priceList *pricing.PriceList, opts Options) (Warning, bool) {
+	if opts.MaxPricingAge <= 0 || priceList == nil || priceList.LastUpdated.IsZero() {
+		return Warning{}, false
+	}
+	now := time.Now
+	if opts.Now != nil {
+		now = opts.Now
+	}
+	age := now().Sub(priceList.LastUpdated)
+	if age <= opts.MaxPricingAge {
+		return Warning{}, false
+	}
+	return Warning{
+		Code:    WarningStalePricing,
+		Message: fmt.Sprintf("pricing data was last updated at %s, %s ago, which is older than the maximum age of %s", priceList.LastUpdated.UTC().Format(time.RFC3339), age.Truncate(time.Minute), opts.MaxPricingAge),
+	}, true
 }
 
-// costForLambda calculates the cost of an AWS Lambda function.
-// It includes both the request price and the GB-second price, and accounts for the free tier.
+// getPriceFromTerms extracts the price from the terms of a product.
 //
 // Parameters:
-//   attributes: The attributes of the Lambda function resource.
+//   sku: The SKU of the product.
 //   priceList: The list of AWS prices.
-//   region: The AWS region.
-//   usage: Usage estimates, which may include Lambda monthly requests and average duration.
 //
 // Returns:
-//   A pointer to a Cost struct representing the monthly cost of the Lambda function.
-//   An error if the pricing data cannot be found.
-func costForLambda(attributes map[string]interface{}, priceList *pricing.PriceList, region string, usage *UsageEstimates) (*Cost, error) {
-	memorySize, _ := attributes["memory_size"].(float64)
-	if memorySize == 0 {
-		memorySize = 128 // Default memory size
-	}
-
-	var requestPrice, gbSecondPrice float64
-	var err error
-
-	for sku, product := range priceList.Products {
-		attr := product.Attributes
-		if attr.ServiceCode != "AWSLambda" || attr.Location != region {
-			continue
-		}
-
-		if strings.Contains(attr.UsageType, "Request") {
-			requestPrice, err = getPriceFromTerms(sku, priceList)
-			if err != nil {
-				return nil, fmt.Errorf("could not get request price for Lambda: %w", err)
-			}
-		}
-
-		if strings.Contains(attr.UsageType, "GB-Second") {
-			gbSecondPrice, err = getPriceFromTerms(sku, priceList)
-			if err != nil {
-				return nil, fmt.Errorf("could not get GB-Second price for Lambda: %w", err)
-			}
-		}
-	}
-
-	if requestPrice == 0 || gbSecondPrice == 0 {
-		return nil, fmt.Errorf("could not find pricing for Lambda")
-	}
-
-	totalMonthlyCost := 0.0
-	breakdown := "No usage data provided"
-	if usage != nil {
-		// Free tier adjustment
-		monthlyRequests := float64(usage.LambdaMonthlyRequests)
-		gbSeconds := (memorySize / 1024) * (float64(usage.LambdaAvgDurationMS) / 1000) * monthlyRequests
-
-		requestCost := (monthlyRequests - 1000000) * requestPrice
-		if requestCost < 0 {
-			requestCost = 0
-		}
-
-		gbSecondCost := (gbSeconds - 400000) * gbSecondPrice
-		if gbSecondCost < 0 {
-			gbSecondCost = 0
-		}
-
-		totalMonthlyCost = requestCost + gbSecondCost
-		breakdown = fmt.Sprintf("%d requests/month @ %dms avg duration", usage.LambdaMonthlyRequests, usage.LambdaAvgDurationMS)
-	}
-
-	return &Cost{
-		Value:    totalMonthlyCost,
-		Unit:     "monthly",
-		Breakdown: breakdown,
-	}, nil
+//   The price of the product.
+//   An error if the price cannot be extracted.
+func getPriceFromTerms(sku string, priceList *pricing.PriceList) (float64, error) {
+	price, _, err := getOnDemandTerm(sku, priceList)
+	return price, err
 }
 
-// costForECSService calculates the cost of an AWS ECS service.
-// It currently only supports the Fargate launch type.
+// getOnDemandTerm extracts the price and offer term code of the on-demand term of a product.
+// For tiered prices, the price of the first tier is returned.
 //
 // Parameters:
-//   rc: The resource change for the ECS service.
-//   attributes: The attributes of the ECS service resource.
+//   sku: The SKU of the product.
 //   priceList: The list of AWS prices.
-//   region: The AWS region.
-//   plan: The full Terraform plan.
 //
 // Returns:
-//   A pointer to a Cost struct representing the hourly cost of the ECS service.
-//   An error if the pricing data cannot be found.
-func costForECSService(rc *terraform.ResourceChange, attributes map[string]interface{}, priceList *pricing.PriceList, region string, plan *terraform.Plan) (*Cost, error) {
-	launchType, _ := attributes["launch_type"].(string)
-	if launchType != "FARGATE" {
-		// For EC2 launch type, cost is in the EC2 instances, not the service.
-		return &Cost{Value: 0, Unit: "monthly"}, nil
-	}
-
-	desiredCount, _ := attributes["desired_count"].(float64)
-	if desiredCount == 0 {
-		desiredCount = 1
-	}
-
-	taskDefinitionArn, _ := attributes["task_definition"].(string)
-	if taskDefinitionArn == "" {
-		return nil, fmt.Errorf("missing task_definition for Fargate service")
-	}
-
-	var taskDef *terraform.ResourceChange
-	for _, r := range plan.ResourceChanges {
-		if r.Address == taskDefinitionArn {
-			taskDef = r
-			break
-		}
-	}
-
-	if taskDef == nil {
-		return nil, fmt.Errorf("could not find task definition: %s", taskDefinitionArn)
-	}
-
-	cpu, err := parseFloat(taskDef.After["cpu"])
-	if err != nil {
-		return nil, fmt.Errorf("could not parse cpu from task definition: %w", err)
-	}
-
-	memory, err := parseFloat(taskDef.After["memory"])
+//   The price of the product and the code of the term it comes from.
+//   An error if the price cannot be extracted.
+func getOnDemandTerm(sku string, priceList *pricing.PriceList) (float64, string, error) {
+	tiers, termCode, err := priceList.OnDemandTiers(sku)
 	if err != nil {
-		return nil, fmt.Errorf("could not parse memory from task definition: %w", err)
-	}
-
-	var vcpuPrice, memoryPrice float64
-
-	for sku, product := range priceList.Products {
-		attr := product.Attributes
-		if attr.ServiceCode != "AmazonECS" || attr.Location != region {
-			continue
-		}
-
-		if strings.Contains(attr.UsageType, "vCPU-Hours") {
-			vcpuPrice, err = getPriceFromTerms(sku, priceList)
-			if err != nil {
-				return nil, fmt.Errorf("could not get vCPU price for Fargate: %w", err)
-			}
-		}
-
-		if strings.Contains(attr.UsageType, "GB-Hours") {
-			memoryPrice, err = getPriceFromTerms(sku, priceList)
-			if err != nil {
-				return nil, fmt.Errorf("could not get memory price for Fargate: %w", err)
-			}
-		}
+		return 0, "", err
 	}
-
-	if vcpuPrice == 0 || memoryPrice == 0 {
-		return nil, fmt.Errorf("could not find pricing for Fargate")
-	}
-
-	hourlyCost := (cpu/1024)*vcpuPrice + (memory/1024)*memoryPrice
-	totalHourlyCost := hourlyCost * desiredCount
-
-	return &Cost{
-		Value:    totalHourlyCost,
-		Unit:     "hourly",
-		Breakdown: fmt.Sprintf("%d tasks @ %.2f vCPU / %.2f GB", int(desiredCount), cpu/1024, memory/1024),
-	}, nil
+	return tiers[0].Price, termCode, nil
 }
 
 // parseFloat converts a value to a float64.
@@ -534,167 +555,3 @@ func parseFloat(val interface{}) (float64, error) {
 		return 0, fmt.Errorf("unsupported type for float conversion")
 	}
 }
-
-// costForS3 calculates the cost of an AWS S3 bucket.
-// It includes both storage and request costs.
-//
-// Parameters:
-//   attributes: The attributes of the S3 bucket resource.
-//   priceList: The list of AWS prices.
-//   region: The AWS region.
-//   usage: Usage estimates, which may include S3 storage and request data.
-//
-// Returns:
-//   A pointer to a Cost struct representing the monthly cost of the S3 bucket.
-//   An error if the pricing data cannot be found.
-func costForS3(attributes map[string]interface{}, priceList *pricing.PriceList, region string, usage *UsageEstimates) (*Cost, error) {
-	var storagePrice, putRequestPrice float64
-	var err error
-
-	for sku, product := range priceList.Products {
-		attr := product.Attributes
-		if attr.ServiceCode != "AmazonS3" || attr.Location != region {
-			continue
-		}
-
-		if attr.StorageClass == "General Purpose" && strings.Contains(attr.UsageType, "TimedStorage-ByteHrs") {
-			storagePrice, err = getPriceFromTerms(sku, priceList)
-			if err != nil {
-				return nil, fmt.Errorf("could not get storage price for S3: %w", err)
-			}
-		}
-
-		if attr.Group == "S3-Request-Tier1" {
-			putRequestPrice, err = getPriceFromTerms(sku, priceList)
-			if err != nil {
-				return nil, fmt.Errorf("could not get put request price for S3: %w", err)
-			}
-		}
-	}
-
-	if storagePrice == 0 || putRequestPrice == 0 {
-		return nil, fmt.Errorf("could not find pricing for S3")
-	}
-
-	totalMonthlyCost := 0.0
-	breakdown := "No usage data provided"
-	if usage != nil {
-		storageCost := float64(usage.S3StorageGB) * storagePrice
-		requestCost := (float64(usage.S3MonthlyPutRequests) / 1000) * putRequestPrice
-		totalMonthlyCost = storageCost + requestCost
-		breakdown = fmt.Sprintf("%d GB storage @ $%.4f/GB + %d PUT requests @ $%.4f/1000", usage.S3StorageGB, storagePrice, usage.S3MonthlyPutRequests, putRequestPrice)
-	}
-
-	return &Cost{
-		Value:    totalMonthlyCost,
-		Unit:     "monthly",
-		Breakdown: breakdown,
-	}, nil
-}
-
-// costForEKS calculates the cost of an AWS EKS cluster.
-// It includes the hourly price for the control plane.
-//
-// Parameters:
-//   attributes: The attributes of the EKS cluster resource.
-//   priceList: The list of AWS prices.
-//   region: The AWS region.
-//
-// Returns:
-//   A pointer to a Cost struct representing the hourly cost of the EKS cluster.
-//   An error if the pricing data cannot be found.
-func costForEKS(attributes map[string]interface{}, priceList *pricing.PriceList, region string) (*Cost, error) {
-	for sku, product := range priceList.Products {
-		attr := product.Attributes
-		if attr.ServiceCode == "AmazonEKS" && attr.Location == region && strings.Contains(attr.UsageType, "EKS-Hours:perCluster") {
-			price, err := getPriceFromTerms(sku, priceList)
-			if err != nil {
-				return nil, err
-			}
-			return &Cost{
-				Value:    price,
-				Unit:     "hourly",
-				Breakdown: fmt.Sprintf("EKS Control Plane @ $%.4f/hr", price),
-			}, nil
-		}
-	}
-	return nil, fmt.Errorf("could not find pricing for EKS control plane in region: %s", region)
-}
-
-// costForEKSNodeGroup calculates the cost of an AWS EKS node group.
-// It calculates the cost of the EC2 instances in the node group.
-//
-// Parameters:
-//   attributes: The attributes of the EKS node group resource.
-//   priceList: The list of AWS prices.
-//   region: The AWS region.
-//
-// Returns:
-//   A pointer to a Cost struct representing the hourly cost of the EKS node group.
-//   An error if the pricing data cannot be found.
-func costForEKSNodeGroup(attributes map[string]interface{}, priceList *pricing.PriceList, region string) (*Cost, error) {
-	instanceTypes, ok := attributes["instance_types"].([]interface{})
-	if !ok || len(instanceTypes) == 0 {
-		return nil, fmt.Errorf("missing instance_types")
-	}
-	instanceType := instanceTypes[0].(string)
-
-	scalingConfig, ok := attributes["scaling_config"].([]interface{})
-	if !ok || len(scalingConfig) == 0 {
-		return nil, fmt.Errorf("missing scaling_config")
-	}
-	desiredSize, ok := scalingConfig[0].(map[string]interface{})["desired_size"].(float64)
-	if !ok {
-		return nil, fmt.Errorf("missing desired_size")
-	}
-
-	ec2Cost, err := costForEC2(map[string]interface{}{"instance_type": instanceType}, priceList, region)
-	if err != nil {
-		return nil, err
-	}
-
-	totalCost := ec2Cost.Value * desiredSize
-	return &Cost{
-		Value:    totalCost,
-		Unit:     "hourly",
-		Breakdown: fmt.Sprintf("%d x %s @ $%.4f/hr", int(desiredSize), instanceType, ec2Cost.Value),
-	}, nil
-}
-
-// costForElastiCache calculates the cost of an AWS ElastiCache cluster.
-//
-// Parameters:
-//   attributes: The attributes of the ElastiCache cluster resource.
-//   priceList: The list of AWS prices.
-//   region: The AWS region.
-//
-// Returns:
-//   A pointer to a Cost struct representing the hourly cost of the ElastiCache cluster.
-//   An error if the pricing data cannot be found.
-func costForElastiCache(attributes map[string]interface{}, priceList *pricing.PriceList, region string) (*Cost, error) {
-	nodeType, _ := attributes["node_type"].(string)
-	if nodeType == "" {
-		return nil, fmt.Errorf("missing node_type")
-	}
-	numCacheNodes, _ := attributes["num_cache_nodes"].(float64)
-	if numCacheNodes == 0 {
-		numCacheNodes = 1
-	}
-
-	for sku, product := range priceList.Products {
-		attr := product.Attributes
-		if attr.ServiceCode == "AmazonElastiCache" && attr.InstanceType == nodeType && attr.Location == region {
-			price, err := getPriceFromTerms(sku, priceList)
-			if err != nil {
-				return nil, err
-			}
-			totalCost := price * numCacheNodes
-			return &Cost{
-				Value:    totalCost,
-				Unit:     "hourly",
-				Breakdown: fmt.Sprintf("%d x %s @ $%.4f/hr", int(numCacheNodes), nodeType, price),
-			}, nil
-		}
-	}
-	return nil, fmt.Errorf("could not find pricing for ElastiCache node type: %s", nodeType)
-}
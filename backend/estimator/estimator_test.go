@@ -5,6 +5,7 @@ import (
 	"cloudcostguard/backend/terraform"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func createMockPriceList() *pricing.PriceList {
@@ -156,7 +157,7 @@ func createMockPriceList() *pricing.PriceList {
 		Attributes: pricing.ProductAttributes{
 			ServiceCode: "AmazonEKS",
 			Location:    "US East (N. Virginia)",
-			UsageType:   "EKS-Hours:perCluster",
+			UsageType:   "AmazonEKS-Hours:perCluster",
 		},
 	}
 	pd9 := pricing.PriceDimension{}
@@ -277,6 +278,241 @@ func TestEstimate(t *testing.T) {
 		assert.NoError(t, err)
 		assert.InDelta(t, expectedCost, result.TotalMonthlyCost, 0.01)
 		assert.Len(t, result.Resources, 1)
+
+		resource := result.Resources[0]
+		assert.Equal(t, ActionUpdate, resource.Action)
+		assert.InDelta(t, 10.0*730, resource.BeforeMonthlyCost, 0.01)
+		assert.InDelta(t, 20.0*730, resource.AfterMonthlyCost, 0.01)
+		assert.Contains(t, resource.BeforeCostBreakdown, "t2.micro")
+		assert.Contains(t, resource.CostBreakdown, "t2.small")
+	})
+
+	t.Run("lists updates that do not change the cost", func(t *testing.T) {
+		plan := &terraform.Plan{
+			ResourceChanges: []*terraform.ResourceChange{
+				{
+					Address: "aws_instance.web",
+					Type:    "aws_instance",
+					Change:  terraform.Change{Actions: []string{"update"}},
+					Before:  map[string]interface{}{"instance_type": "t2.micro", "tags": map[string]interface{}{"Name": "web"}},
+					After:   map[string]interface{}{"instance_type": "t2.micro", "tags": map[string]interface{}{"Name": "app"}},
+				},
+			},
+		}
+
+		result, err := Estimate(plan, mockPrices, usEastRegion, &UsageEstimates{})
+		assert.NoError(t, err)
+		assert.Zero(t, result.TotalMonthlyCost)
+		if assert.Len(t, result.Resources, 1) {
+			resource := result.Resources[0]
+			assert.Equal(t, ActionUpdate, resource.Action)
+			assert.Zero(t, resource.MonthlyCost)
+			assert.InDelta(t, 10.0*730, resource.BeforeMonthlyCost, 0.01)
+			assert.InDelta(t, 10.0*730, resource.AfterMonthlyCost, 0.01)
+		}
+	})
+
+	t.Run("estimates cost for create_before_destroy replacements", func(t *testing.T) {
+		plan := &terraform.Plan{
+			ResourceChanges: []*terraform.ResourceChange{
+				{
+					Address: "aws_instance.web",
+					Type:    "aws_instance",
+					Change:  terraform.Change{Actions: []string{"create", "delete"}},
+					Before:  map[string]interface{}{"instance_type": "t2.micro"},
+					After:   map[string]interface{}{"instance_type": "t2.small"},
+				},
+			},
+		}
+
+		result, err := Estimate(plan, mockPrices, usEastRegion, &UsageEstimates{})
+		assert.NoError(t, err)
+		assert.Len(t, result.Resources, 1)
+		assert.Equal(t, ActionReplace, result.Resources[0].Action)
+		assert.InDelta(t, (20.0-10.0)*730, result.TotalMonthlyCost, 0.01)
+	})
+
+	t.Run("counts unchanged resources toward the baseline", func(t *testing.T) {
+		plan := &terraform.Plan{
+			ResourceChanges: []*terraform.ResourceChange{
+				{
+					Address: "aws_instance.web",
+					Type:    "aws_instance",
+					Change:  terraform.Change{Actions: []string{"no-op"}},
+					Before:  map[string]interface{}{"instance_type": "t2.micro"},
+					After:   map[string]interface{}{"instance_type": "t2.micro"},
+				},
+				{
+					Address: "null_resource.foo",
+					Type:    "null_resource",
+					Change:  terraform.Change{Actions: []string{"no-op"}},
+					Before:  map[string]interface{}{},
+					After:   map[string]interface{}{},
+				},
+				{
+					Address: "aws_instance.new",
+					Type:    "aws_instance",
+					Change:  terraform.Change{Actions: []string{"create"}},
+					After:   map[string]interface{}{"instance_type": "t2.small"},
+				},
+			},
+		}
+
+		result, err := Estimate(plan, mockPrices, usEastRegion, &UsageEstimates{})
+		assert.NoError(t, err)
+		assert.InDelta(t, 20.0*730, result.TotalMonthlyCost, 0.01)
+		assert.InDelta(t, 10.0*730, result.BaselineMonthlyCost, 0.01)
+		assert.Equal(t, 1, result.BaselineResources)
+		assert.Len(t, result.Resources, 1)
+		assert.Empty(t, result.Skipped)
+		assert.Equal(t, Coverage{TotalResources: 1, PricedResources: 1}, result.Coverage)
+	})
+
+	t.Run("does not count imported or moved resources as new spend", func(t *testing.T) {
+		plan := &terraform.Plan{
+			ResourceChanges: []*terraform.ResourceChange{
+				{
+					Address: "aws_instance.imported",
+					Mode:    terraform.ModeManaged,
+					Type:    "aws_instance",
+					Change: terraform.Change{
+						Actions:   []string{"no-op"},
+						Importing: &terraform.Importing{ID: "i-0123456789"},
+					},
+					Before: map[string]interface{}{"instance_type": "t2.micro"},
+					After:  map[string]interface{}{"instance_type": "t2.micro"},
+				},
+				{
+					Address: "aws_instance.imported_without_state",
+					Mode:    terraform.ModeManaged,
+					Type:    "aws_instance",
+					Change: terraform.Change{
+						Actions:   []string{"create"},
+						Importing: &terraform.Importing{ID: "i-9876543210"},
+					},
+					After: map[string]interface{}{"instance_type": "t2.micro"},
+				},
+				{
+					Address:         "aws_instance.app",
+					PreviousAddress: "aws_instance.web",
+					Mode:            terraform.ModeManaged,
+					Type:            "aws_instance",
+					Change:          terraform.Change{Actions: []string{"no-op"}},
+					Before:          map[string]interface{}{"instance_type": "t2.micro"},
+					After:           map[string]interface{}{"instance_type": "t2.micro"},
+				},
+			},
+		}
+
+		result, err := Estimate(plan, mockPrices, usEastRegion, &UsageEstimates{})
+		assert.NoError(t, err)
+		assert.Equal(t, 0.0, result.TotalMonthlyCost)
+		assert.Empty(t, result.Resources)
+		assert.Equal(t, 3, result.BaselineResources)
+		assert.InDelta(t, 3*10.0*730, result.BaselineMonthlyCost, 0.01)
+	})
+
+	t.Run("reports the cost of moved and imported resources that are updated", func(t *testing.T) {
+		plan := &terraform.Plan{
+			ResourceChanges: []*terraform.ResourceChange{
+				{
+					Address:         "aws_instance.app",
+					PreviousAddress: "aws_instance.web",
+					Type:            "aws_instance",
+					Change:          terraform.Change{Actions: []string{"update"}},
+					Before:          map[string]interface{}{"instance_type": "t2.micro"},
+					After:           map[string]interface{}{"instance_type": "t2.small"},
+				},
+				{
+					Address: "aws_instance.imported",
+					Type:    "aws_instance",
+					Change: terraform.Change{
+						Actions:   []string{"update"},
+						Importing: &terraform.Importing{ID: "i-0123456789"},
+					},
+					Before: map[string]interface{}{"instance_type": "t2.micro"},
+					After:  map[string]interface{}{"instance_type": "t2.small"},
+				},
+			},
+		}
+
+		result, err := Estimate(plan, mockPrices, usEastRegion, &UsageEstimates{})
+		assert.NoError(t, err)
+		assert.Len(t, result.Resources, 2)
+		assert.Equal(t, "aws_instance.web", result.Resources[0].PreviousAddress)
+		assert.False(t, result.Resources[0].Imported)
+		assert.True(t, result.Resources[1].Imported)
+		assert.InDelta(t, 2*(20.0-10.0)*730, result.TotalMonthlyCost, 0.01)
+	})
+
+	t.Run("ignores data sources and forgotten resources", func(t *testing.T) {
+		plan := &terraform.Plan{
+			ResourceChanges: []*terraform.ResourceChange{
+				{
+					Address: "data.aws_instance.existing",
+					Mode:    terraform.ModeData,
+					Type:    "aws_instance",
+					Change:  terraform.Change{Actions: []string{"read"}},
+					After:   map[string]interface{}{"instance_type": "t2.micro"},
+				},
+				{
+					Address: "aws_instance.removed",
+					Mode:    terraform.ModeManaged,
+					Type:    "aws_instance",
+					Change:  terraform.Change{Actions: []string{"forget"}},
+					Before:  map[string]interface{}{"instance_type": "t2.micro"},
+				},
+			},
+		}
+
+		result, err := Estimate(plan, mockPrices, usEastRegion, &UsageEstimates{})
+		assert.NoError(t, err)
+		assert.Equal(t, 0.0, result.TotalMonthlyCost)
+		assert.Empty(t, result.Resources)
+		assert.Empty(t, result.Skipped)
+		assert.Equal(t, 0, result.BaselineResources)
+		assert.Equal(t, Coverage{}, result.Coverage)
+	})
+
+	t.Run("reports cost added, cost removed and net change", func(t *testing.T) {
+		plan := &terraform.Plan{
+			ResourceChanges: []*terraform.ResourceChange{
+				{
+					Address: "aws_instance.new",
+					Type:    "aws_instance",
+					Change:  terraform.Change{Actions: []string{"create"}},
+					After:   map[string]interface{}{"instance_type": "t2.small"},
+				},
+				{
+					Address: "aws_instance.old",
+					Type:    "aws_instance",
+					Change:  terraform.Change{Actions: []string{"delete"}},
+					Before:  map[string]interface{}{"instance_type": "t2.micro"},
+				},
+				{
+					Address: "aws_instance.replaced",
+					Type:    "aws_instance",
+					Change:  terraform.Change{Actions: []string{"delete", "create"}},
+					Before:  map[string]interface{}{"instance_type": "t2.small"},
+					After:   map[string]interface{}{"instance_type": "t2.micro"},
+				},
+			},
+		}
+
+		result, err := Estimate(plan, mockPrices, usEastRegion, &UsageEstimates{})
+		assert.NoError(t, err)
+		assert.Len(t, result.Resources, 3)
+		assert.Equal(t, ActionCreate, result.Resources[0].Action)
+		assert.Equal(t, ActionDelete, result.Resources[1].Action)
+		assert.InDelta(t, 10.0*730, result.Resources[1].BeforeMonthlyCost, 0.01)
+		assert.Equal(t, 0.0, result.Resources[1].AfterMonthlyCost)
+		assert.Equal(t, ActionReplace, result.Resources[2].Action)
+
+		// Added: t2.small ($20/hr). Removed: t2.micro ($10/hr) + t2.small replaced by t2.micro ($10/hr).
+		assert.InDelta(t, 20.0*730, result.MonthlyCostAdded, 0.01)
+		assert.InDelta(t, 20.0*730, result.MonthlyCostRemoved, 0.01)
+		assert.InDelta(t, 0.0, result.NetMonthlyCostChange, 0.01)
+		assert.InDelta(t, result.NetMonthlyCostChange, result.TotalMonthlyCost, 0.01)
 	})
 
 	t.Run("estimates cost for multiple resources", func(t *testing.T) {
@@ -328,6 +564,59 @@ func TestEstimate(t *testing.T) {
 		assert.NoError(t, err)
 		assert.InDelta(t, expectedCost, result.TotalMonthlyCost, 0.01)
 		assert.Len(t, result.Resources, 1)
+		assert.Equal(t, []SkippedResource{
+			{
+				Address: "null_resource.foo",
+				Type:    "null_resource",
+				Reason:  SkipReasonUnsupportedType,
+				Message: "unsupported resource type: null_resource",
+			},
+		}, result.Skipped)
+		assert.Equal(t, Coverage{TotalResources: 2, PricedResources: 1, SkippedResources: 1}, result.Coverage)
+	})
+
+	t.Run("reports why resources were skipped", func(t *testing.T) {
+		plan := &terraform.Plan{
+			ResourceChanges: []*terraform.ResourceChange{
+				{
+					Address: "aws_instance.no_type",
+					Type:    "aws_instance",
+					Change:  terraform.Change{Actions: []string{"create"}},
+					After:   map[string]interface{}{},
+				},
+				{
+					Address: "aws_instance.unpriced",
+					Type:    "aws_instance",
+					Change:  terraform.Change{Actions: []string{"create"}},
+					After:   map[string]interface{}{"instance_type": "x9.huge"},
+				},
+				{
+					Address: "aws_instance.web",
+					Type:    "aws_instance",
+					Change:  terraform.Change{Actions: []string{"create"}},
+					After:   map[string]interface{}{"instance_type": "t2.micro"},
+				},
+				{
+					Address: "aws_instance.unchanged",
+					Type:    "aws_instance",
+					Change:  terraform.Change{Actions: []string{"no-op"}},
+					Before:  map[string]interface{}{"instance_type": "t2.micro"},
+					After:   map[string]interface{}{"instance_type": "t2.micro"},
+				},
+			},
+		}
+
+		result, err := Estimate(plan, mockPrices, usEastRegion, &UsageEstimates{})
+		assert.NoError(t, err)
+		assert.Len(t, result.Skipped, 2)
+		assert.Equal(t, "aws_instance.no_type", result.Skipped[0].Address)
+		assert.Equal(t, "aws_instance", result.Skipped[0].Type)
+		assert.Equal(t, SkipReasonMissingAttribute, result.Skipped[0].Reason)
+		assert.Equal(t, "missing attribute: instance_type", result.Skipped[0].Message)
+		assert.Equal(t, "aws_instance.unpriced", result.Skipped[1].Address)
+		assert.Equal(t, SkipReasonPriceNotFound, result.Skipped[1].Reason)
+		assert.Equal(t, "could not find pricing for EC2 instance type: x9.huge", result.Skipped[1].Message)
+		assert.Equal(t, Coverage{TotalResources: 3, PricedResources: 1, SkippedResources: 2}, result.Coverage)
 	})
 
 	t.Run("estimates cost for a new NAT Gateway", func(t *testing.T) {
@@ -374,6 +663,86 @@ func TestEstimate(t *testing.T) {
 		assert.Len(t, result.Resources, 1)
 	})
 
+	t.Run("reports cost components", func(t *testing.T) {
+		plan := &terraform.Plan{
+			ResourceChanges: []*terraform.ResourceChange{
+				{
+					Address: "aws_nat_gateway.gw",
+					Type:    "aws_nat_gateway",
+					Change:  terraform.Change{Actions: []string{"create"}},
+					After:   map[string]interface{}{},
+				},
+				{
+					Address: "aws_instance.old",
+					Type:    "aws_instance",
+					Change:  terraform.Change{Actions: []string{"update"}},
+					Before:  map[string]interface{}{"instance_type": "t2.micro"},
+					After:   map[string]interface{}{"instance_type": "t2.small"},
+				},
+			},
+		}
+
+		result, err := Estimate(plan, mockPrices, usEastRegion, &UsageEstimates{NATGatewayGBProcessed: 1000})
+		assert.NoError(t, err)
+		assert.Len(t, result.Resources, 2)
+
+		nat := result.Resources[0]
+		assert.Equal(t, []CostComponent{
+			{Name: "NAT gateway usage", Unit: "hour", MonthlyQuantity: 730, UnitPrice: 0.045, SKU: "nat-gateway-sku", OfferTermCode: "term1", MonthlyCost: 730 * 0.045},
+			{Name: "Data processed", Unit: "GB", MonthlyQuantity: 1000, UnitPrice: 0.045, SKU: "nat-gateway-dp-sku", OfferTermCode: "term1", MonthlyCost: 1000 * 0.045},
+		}, nat.Components)
+		assert.Equal(t, "NAT gateway usage: 730 × $0.0450/hour + Data processed: 1000 × $0.0450/GB", nat.CostBreakdown)
+		assert.Empty(t, nat.BeforeComponents)
+
+		instance := result.Resources[1]
+		assert.Len(t, instance.BeforeComponents, 1)
+		assert.Equal(t, "ec2-t2-micro-sku", instance.BeforeComponents[0].SKU)
+		assert.Len(t, instance.Components, 1)
+		assert.Equal(t, "Instance usage (t2.small)", instance.Components[0].Name)
+		assert.Equal(t, "ec2-t2-small-sku", instance.Components[0].SKU)
+		assert.InDelta(t, instance.AfterMonthlyCost, instance.Components[0].MonthlyCost, 0.01)
+	})
+
+	t.Run("applies per-resource usage estimates", func(t *testing.T) {
+		plan := &terraform.Plan{
+			ResourceChanges: []*terraform.ResourceChange{
+				{
+					Address: "aws_nat_gateway.busy",
+					Type:    "aws_nat_gateway",
+					Change:  terraform.Change{Actions: []string{"create"}},
+					After:   map[string]interface{}{},
+				},
+				{
+					Address: "module.edge.aws_nat_gateway.gw[0]",
+					Type:    "aws_nat_gateway",
+					Change:  terraform.Change{Actions: []string{"create"}},
+					After:   map[string]interface{}{},
+				},
+				{
+					Address: "aws_nat_gateway.default",
+					Type:    "aws_nat_gateway",
+					Change:  terraform.Change{Actions: []string{"create"}},
+					After:   map[string]interface{}{},
+				},
+			},
+		}
+
+		usage := &UsageEstimates{
+			NATGatewayGBProcessed: 100,
+			Resources: map[string]UsageEstimates{
+				"aws_nat_gateway.busy": {NATGatewayGBProcessed: 1000},
+				"module.edge.*":        {NATGatewayGBProcessed: 10},
+			},
+		}
+
+		result, err := Estimate(plan, mockPrices, usEastRegion, usage)
+		assert.NoError(t, err)
+		assert.Len(t, result.Resources, 3)
+		assert.InDelta(t, (0.045*730)+(1000*0.045), result.Resources[0].MonthlyCost, 0.01)
+		assert.InDelta(t, (0.045*730)+(10*0.045), result.Resources[1].MonthlyCost, 0.01)
+		assert.InDelta(t, (0.045*730)+(100*0.045), result.Resources[2].MonthlyCost, 0.01)
+	})
+
 	t.Run("uses the correct region for pricing", func(t *testing.T) {
 		plan := &terraform.Plan{
 			ResourceChanges: []*terraform.ResourceChange{
@@ -411,6 +780,8 @@ func TestEstimate(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, 0.0, result.TotalMonthlyCost)
 		assert.Len(t, result.Resources, 0)
+		assert.Len(t, result.Skipped, 1)
+		assert.Equal(t, SkipReasonPriceNotFound, result.Skipped[0].Reason)
 	})
 
 	t.Run("estimates cost for a new Lambda function", func(t *testing.T) {
@@ -440,13 +811,14 @@ func TestEstimate(t *testing.T) {
 				"lambda-gb-second": {
 					Attributes: pricing.ProductAttributes{
 						ServiceCode: "AWSLambda",
-						UsageType:   "GB-Second",
+						UsageType:   "Lambda-GB-Second",
 						Location:    "US East (N. Virginia)",
 					},
 				},
 			},
 			Terms: struct {
 				OnDemand map[string]map[string]pricing.Term `json:"OnDemand"`
+				Reserved map[string]map[string]pricing.Term `json:"Reserved,omitempty"`
 			}{
 				OnDemand: map[string]map[string]pricing.Term{
 					"lambda-request": {
@@ -574,3 +946,267 @@ func TestEstimate(t *testing.T) {
 		assert.Len(t, result.Resources, 2)
 	})
 }
+
+func TestEstimatePricingProvenance(t *testing.T) {
+	lastUpdated := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	publicationDate := time.Date(2024, 5, 30, 0, 0, 0, 0, time.UTC)
+
+	priceList := createMockPriceList()
+	priceList.LastUpdated = lastUpdated
+	priceList.AddOffer(pricing.Offer{OfferCode: "AmazonVPC", Version: "20240530000000", PublicationDate: publicationDate})
+	priceList.AddOffer(pricing.Offer{OfferCode: "AmazonEC2", Version: "20240529000000", PublicationDate: publicationDate})
+	pd := pricing.PriceDimension{}
+	pd.PricePerUnit.USD = "10.0"
+	priceList.Terms.OnDemand["ec2-t2-micro-sku"] = map[string]pricing.Term{
+		"ec2-t2-micro-sku.JRTCKXETXF": {
+			OfferTermCode:   "JRTCKXETXF",
+			PriceDimensions: map[string]pricing.PriceDimension{"dim1": pd},
+		},
+	}
+
+	plan := &terraform.Plan{
+		ResourceChanges: []*terraform.ResourceChange{
+			{
+				Address: "aws_instance.web",
+				Type:    "aws_instance",
+				Change:  terraform.Change{Actions: []string{"create"}},
+				After:   map[string]interface{}{"instance_type": "t2.micro"},
+			},
+		},
+	}
+
+	t.Run("reports the price snapshot and the term of each component", func(t *testing.T) {
+		result, err := Estimate(plan, priceList, "us-east-1", &UsageEstimates{})
+		assert.NoError(t, err)
+		assert.Equal(t, &lastUpdated, result.Pricing.LastUpdated)
+		assert.Equal(t, []PricingOffer{
+			{OfferCode: "AmazonEC2", Version: "20240529000000", PublicationDate: publicationDate},
+			{OfferCode: "AmazonVPC", Version: "20240530000000", PublicationDate: publicationDate},
+		}, result.Pricing.Offers)
+		assert.Equal(t, "ec2-t2-micro-sku", result.Resources[0].Components[0].SKU)
+		assert.Equal(t, "JRTCKXETXF", result.Resources[0].Components[0].OfferTermCode)
+		assert.Empty(t, result.Warnings)
+	})
+
+	t.Run("warns when the pricing data is older than the maximum age", func(t *testing.T) {
+		opts := Options{
+			MaxPricingAge: 48 * time.Hour,
+			Now:           func() time.Time { return lastUpdated.Add(72 * time.Hour) },
+		}
+		result, err := EstimateWithOptions(plan, priceList, "us-east-1", &UsageEstimates{}, opts)
+		assert.NoError(t, err)
+		assert.Len(t, result.Warnings, 1)
+		assert.Equal(t, WarningStalePricing, result.Warnings[0].Code)
+		assert.Equal(t, "pricing data was last updated at 2024-06-01T12:00:00Z, 72h0m0s ago, which is older than the maximum age of 48h0m0s", result.Warnings[0].Message)
+	})
+
+	t.Run("does not warn about fresh pricing data", func(t *testing.T) {
+		opts := Options{
+			MaxPricingAge: 48 * time.Hour,
+			Now:           func() time.Time { return lastUpdated.Add(time.Hour) },
+		}
+		result, err := EstimateWithOptions(plan, priceList, "us-east-1", &UsageEstimates{}, opts)
+		assert.NoError(t, err)
+		assert.Empty(t, result.Warnings)
+	})
+
+	t.Run("does not warn when the update time is unknown", func(t *testing.T) {
+		opts := Options{MaxPricingAge: time.Nanosecond}
+		result, err := EstimateWithOptions(plan, createMockPriceList(), "us-east-1", &UsageEstimates{}, opts)
+		assert.NoError(t, err)
+		assert.Empty(t, result.Warnings)
+		assert.Nil(t, result.Pricing.LastUpdated)
+		assert.Empty(t, result.Pricing.Offers)
+	})
+}
+
+func TestEstimateTieredPricing(t *testing.T) {
+	priceList := createMockPriceList()
+	tiers := map[string]struct{ begin, end, price string }{
+		"tier1": {"0", "51200", "0.023"},
+		"tier2": {"51200", "512000", "0.022"},
+		"tier3": {"512000", "Inf", "0.021"},
+	}
+	dims := map[string]pricing.PriceDimension{}
+	for key, tier := range tiers {
+		dim := pricing.PriceDimension{BeginRange: tier.begin, EndRange: tier.end, Unit: "GB-Mo"}
+		dim.PricePerUnit.USD = tier.price
+		dims[key] = dim
+	}
+	priceList.Terms.OnDemand["s3-storage-sku"] = map[string]pricing.Term{
+		"s3-storage-sku.JRTCKXETXF": {PriceDimensions: dims},
+	}
+
+	plan := &terraform.Plan{
+		ResourceChanges: []*terraform.ResourceChange{
+			{
+				Address: "aws_s3_bucket.archive",
+				Type:    "aws_s3_bucket",
+				Change:  terraform.Change{Actions: []string{"create"}},
+				After:   map[string]interface{}{},
+			},
+		},
+	}
+
+	t.Run("prices storage across every tier it reaches", func(t *testing.T) {
+		result, err := Estimate(plan, priceList, "us-east-1", &UsageEstimates{S3StorageGB: 600000})
+		assert.NoError(t, err)
+		assert.Len(t, result.Resources, 1)
+
+		expected := 51200*0.023 + 460800*0.022 + 88000*0.021
+		assert.InDelta(t, expected, result.TotalMonthlyCost, 0.01)
+
+		components := result.Resources[0].Components
+		assert.Len(t, components, 4)
+		assert.Equal(t, "Storage (Standard) (first 51200 GB-month)", components[0].Name)
+		assert.Equal(t, "Storage (Standard) (51200 to 512000 GB-month)", components[1].Name)
+		assert.Equal(t, 460800.0, components[1].MonthlyQuantity)
+		assert.Equal(t, 0.022, components[1].UnitPrice)
+		assert.Equal(t, "Storage (Standard) (over 512000 GB-month)", components[2].Name)
+		assert.Equal(t, "PUT requests", components[3].Name)
+	})
+
+	t.Run("prices small buckets at the first tier", func(t *testing.T) {
+		result, err := Estimate(plan, priceList, "us-east-1", &UsageEstimates{S3StorageGB: 100})
+		assert.NoError(t, err)
+		assert.InDelta(t, 100*0.023, result.TotalMonthlyCost, 0.01)
+		assert.Equal(t, "Storage (Standard) (first 51200 GB-month)", result.Resources[0].Components[0].Name)
+	})
+}
+
+func TestEstimatePricingModels(t *testing.T) {
+	priceList := createMockPriceList()
+	noUpfront := pricing.PriceDimension{Unit: "Hrs"}
+	noUpfront.PricePerUnit.USD = "6.0"
+	allUpfrontFee := pricing.PriceDimension{Unit: "Quantity"}
+	allUpfrontFee.PricePerUnit.USD = "43800"
+	allUpfrontHourly := pricing.PriceDimension{Unit: "Hrs"}
+	allUpfrontHourly.PricePerUnit.USD = "0"
+	priceList.Terms.Reserved = map[string]map[string]pricing.Term{
+		"ec2-t2-micro-sku": {
+			"ec2-t2-micro-sku.4NA7Y494T4": {
+				OfferTermCode:   "4NA7Y494T4",
+				PriceDimensions: map[string]pricing.PriceDimension{"hourly": noUpfront},
+				TermAttributes:  pricing.TermAttributes{LeaseContractLength: "1yr", PurchaseOption: "No Upfront", OfferingClass: "standard"},
+			},
+			"ec2-t2-micro-sku.6QCMYABX3D": {
+				OfferTermCode:   "6QCMYABX3D",
+				PriceDimensions: map[string]pricing.PriceDimension{"upfront": allUpfrontFee, "hourly": allUpfrontHourly},
+				TermAttributes:  pricing.TermAttributes{LeaseContractLength: "1yr", PurchaseOption: "All Upfront", OfferingClass: "standard"},
+			},
+		},
+	}
+
+	priceList.SavingsPlanRates = map[string][]pricing.SavingsPlanRate{
+		"ec2-t2-micro-sku": {
+			{PlanType: pricing.SavingsPlanCompute, LeaseContractLength: "1yr", PurchaseOption: "No Upfront", RateCode: "SPCOMPUTE.ec2-t2-micro-sku", Unit: "Hrs", Rate: 7.0},
+			{PlanType: pricing.SavingsPlanEC2Instance, LeaseContractLength: "1yr", PurchaseOption: "All Upfront", RateCode: "SPEC2.ec2-t2-micro-sku", Unit: "Hrs", Rate: 5.5},
+		},
+	}
+
+	plan := &terraform.Plan{
+		ResourceChanges: []*terraform.ResourceChange{
+			{
+				Address: "aws_instance.web",
+				Type:    "aws_instance",
+				Change:  terraform.Change{Actions: []string{"create"}},
+				After:   map[string]interface{}{"instance_type": "t2.micro"},
+			},
+		},
+	}
+
+	t.Run("compares on-demand resources with a 1yr No Upfront reservation", func(t *testing.T) {
+		result, err := Estimate(plan, priceList, "us-east-1", &UsageEstimates{})
+		assert.NoError(t, err)
+		resource := result.Resources[0]
+		assert.Equal(t, "on_demand", resource.PricingModel)
+		assert.InDelta(t, 10.0*730, resource.MonthlyCost, 0.01)
+		assert.Equal(t, &CommitmentComparison{
+			Model:                "1yr No Upfront standard reserved",
+			OnDemandMonthlyCost:  10.0 * 730,
+			CommittedMonthlyCost: 6.0 * 730,
+			MonthlySavings:       4.0 * 730,
+		}, resource.Commitment)
+	})
+
+	t.Run("prices resources under the selected reservation", func(t *testing.T) {
+		opts := Options{PricingModels: map[string]PricingModel{
+			"aws_instance": {Term: PricingTermReserved, PurchaseOption: "All Upfront"},
+		}}
+		result, err := EstimateWithOptions(plan, priceList, "us-east-1", &UsageEstimates{}, opts)
+		assert.NoError(t, err)
+		resource := result.Resources[0]
+		assert.Equal(t, "1yr All Upfront standard reserved", resource.PricingModel)
+		// $43,800 upfront over 8,760 hours is $5/hr.
+		assert.InDelta(t, 5.0*730, resource.MonthlyCost, 0.01)
+		assert.InDelta(t, 5.0*730, result.TotalMonthlyCost, 0.01)
+		assert.Equal(t, "6QCMYABX3D", resource.Components[0].OfferTermCode)
+		assert.InDelta(t, 5.0, resource.Components[0].UnitPrice, 1e-9)
+		assert.InDelta(t, 43800.0, resource.Commitment.UpfrontCost, 0.01)
+		assert.InDelta(t, 10.0*730, resource.Commitment.OnDemandMonthlyCost, 0.01)
+		assert.InDelta(t, 5.0*730, resource.Commitment.MonthlySavings, 0.01)
+	})
+
+	t.Run("prices resources under the selected Savings Plan", func(t *testing.T) {
+		opts := Options{PricingModels: map[string]PricingModel{
+			"aws_instance": {Term: PricingTermSavingsPlan},
+		}}
+		result, err := EstimateWithOptions(plan, priceList, "us-east-1", &UsageEstimates{}, opts)
+		assert.NoError(t, err)
+		resource := result.Resources[0]
+		assert.Equal(t, "1yr No Upfront Compute Savings Plan", resource.PricingModel)
+		assert.InDelta(t, 7.0*730, resource.MonthlyCost, 0.01)
+		assert.Equal(t, "SPCOMPUTE.ec2-t2-micro-sku", resource.Components[0].OfferTermCode)
+		assert.Equal(t, &CommitmentComparison{
+			Model:                "1yr No Upfront Compute Savings Plan",
+			OnDemandMonthlyCost:  10.0 * 730,
+			CommittedMonthlyCost: 7.0 * 730,
+			MonthlySavings:       3.0 * 730,
+		}, resource.Commitment)
+
+		opts.PricingModels["aws_instance"] = PricingModel{Term: PricingTermSavingsPlan, PurchaseOption: "All Upfront", SavingsPlanType: SavingsPlanTypeEC2Instance}
+		result, err = EstimateWithOptions(plan, priceList, "us-east-1", &UsageEstimates{}, opts)
+		assert.NoError(t, err)
+		resource = result.Resources[0]
+		assert.Equal(t, "1yr All Upfront EC2 Instance Savings Plan", resource.PricingModel)
+		assert.InDelta(t, 5.5*730, resource.MonthlyCost, 0.01)
+		// The whole hourly commitment of $5.50 for 8,760 hours is paid upfront.
+		assert.InDelta(t, 5.5*8760, resource.Commitment.UpfrontCost, 0.01)
+	})
+
+	t.Run("keeps on-demand prices when no Savings Plan rate matches", func(t *testing.T) {
+		opts := Options{PricingModels: map[string]PricingModel{
+			"aws_instance": {Term: PricingTermSavingsPlan, LeaseContractLength: "3yr"},
+		}}
+		result, err := EstimateWithOptions(plan, priceList, "us-east-1", &UsageEstimates{}, opts)
+		assert.NoError(t, err)
+		assert.InDelta(t, 10.0*730, result.Resources[0].MonthlyCost, 0.01)
+		assert.Nil(t, result.Resources[0].Commitment)
+	})
+
+	t.Run("keeps on-demand prices when no reservation matches", func(t *testing.T) {
+		opts := Options{PricingModels: map[string]PricingModel{
+			"aws_instance": {Term: PricingTermReserved, LeaseContractLength: "3yr"},
+		}}
+		result, err := EstimateWithOptions(plan, priceList, "us-east-1", &UsageEstimates{}, opts)
+		assert.NoError(t, err)
+		assert.InDelta(t, 10.0*730, result.Resources[0].MonthlyCost, 0.01)
+		assert.Nil(t, result.Resources[0].Commitment)
+	})
+
+	t.Run("omits the comparison for resources without reserved terms", func(t *testing.T) {
+		natPlan := &terraform.Plan{
+			ResourceChanges: []*terraform.ResourceChange{
+				{
+					Address: "aws_nat_gateway.nat",
+					Type:    "aws_nat_gateway",
+					Change:  terraform.Change{Actions: []string{"create"}},
+					After:   map[string]interface{}{},
+				},
+			},
+		}
+		result, err := Estimate(natPlan, priceList, "us-east-1", &UsageEstimates{})
+		assert.NoError(t, err)
+		assert.Nil(t, result.Resources[0].Commitment)
+	})
+}
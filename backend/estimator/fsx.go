@@ -0,0 +1,279 @@
+package estimator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"cloudcostguard/backend/pricing"
+)
+
+func init() {
+	MustRegister(NewCalculator("aws_fsx_lustre_file_system", []string{"AmazonFSx"}, []string{"storage_capacity"}, costForFSxLustre))
+	MustRegister(NewCalculator("aws_fsx_windows_file_system", []string{"AmazonFSx"}, []string{"storage_capacity", "throughput_capacity"}, costForFSxWindows))
+	MustRegister(NewCalculator("aws_fsx_ontap_file_system", []string{"AmazonFSx"}, []string{"storage_capacity"}, costForFSxONTAP))
+	MustRegister(NewCalculator("aws_fsx_openzfs_file_system", []string{"AmazonFSx"}, []string{"storage_capacity", "throughput_capacity"}, costForFSxOpenZFS))
+}
+
+// Product families of FSx products.
+const (
+	fsxFamilyStorage    = "Storage"
+	fsxFamilyThroughput = "Provisioned Throughput"
+	fsxFamilyIOPS       = "Provisioned IOPS"
+	fsxFamilyBackup     = "Backup"
+)
+
+// fsxIncludedIOPSPerGB is the number of SSD IOPS included with each GB of SSD storage of an FSx file system.
+const fsxIncludedIOPSPerGB = 3
+
+// fsxLustreDeploymentOptions maps the deployment types of FSx for Lustre file systems to the deployment option in the
+// price list.
+var fsxLustreDeploymentOptions = map[string]string{
+	"SCRATCH_1":    "Scratch_1",
+	"SCRATCH_2":    "Scratch_2",
+	"PERSISTENT_1": "Persistent_1",
+	"PERSISTENT_2": "Persistent_2",
+}
+
+// fsxFileSystem identifies the products an FSx file system is priced with.
+type fsxFileSystem struct {
+	// fileSystemType is the file system type in the price list (e.g., "Windows").
+	fileSystemType string
+	// deploymentOption is the deployment option in the price list (e.g., "Multi-AZ" or "Persistent_2").
+	deploymentOption string
+	// storageType is the storage type: "SSD" or "HDD".
+	storageType string
+	// throughputPerTiB is the throughput per TiB of storage of an FSx for Lustre file system, which is included in the
+	// storage price, or an empty string for other file systems.
+	throughputPerTiB string
+}
+
+// costForFSxLustre calculates the cost of an AWS FSx for Lustre file system.
+// Storage is charged for storage_capacity at the rate of the deployment_type (SCRATCH_1 by default), storage_type and,
+// for persistent file systems, per_unit_storage_throughput. Persistent file systems with automatic backups are charged
+// for the fsx.backup_storage_gb usage estimate.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its usage estimates may include the backup storage.
+//   attributes: The attributes of the FSx for Lustre file system resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the file system.
+//   An error if the deployment type is unknown or the pricing data cannot be found.
+func costForFSxLustre(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	deploymentType, _ := attributes["deployment_type"].(string)
+	if deploymentType == "" {
+		deploymentType = "SCRATCH_1"
+	}
+	deploymentOption, ok := fsxLustreDeploymentOptions[deploymentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown FSx for Lustre deployment type %q", ErrMissingAttribute, deploymentType)
+	}
+	fs := fsxFileSystem{fileSystemType: "Lustre", deploymentOption: deploymentOption, storageType: fsxStorageType(attributes)}
+	if throughput, _ := attributes["per_unit_storage_throughput"].(float64); throughput > 0 {
+		fs.throughputPerTiB = strconv.FormatFloat(throughput, 'f', -1, 64)
+	}
+
+	storageGB, _ := attributes["storage_capacity"].(float64)
+	storage, err := fsxComponent(ctx, fs, fsxFamilyStorage, fmt.Sprintf("Storage (%s)", fs.storageType), "GB-month", storageGB)
+	if err != nil {
+		return nil, err
+	}
+	components := []CostComponent{storage}
+
+	if strings.HasPrefix(deploymentType, "PERSISTENT") {
+		backup, err := fsxBackupComponents(ctx, fs, attributes, 0)
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, backup...)
+	}
+	return newCost(components...), nil
+}
+
+// costForFSxWindows calculates the cost of an AWS FSx for Windows File Server file system.
+// See costForFSxFileSystem. Automatic backups are kept for 7 days unless automatic_backup_retention_days says
+// otherwise.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its usage estimates may include the backup storage.
+//   attributes: The attributes of the FSx for Windows File Server file system resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the file system.
+//   An error if the pricing data cannot be found.
+func costForFSxWindows(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	throughput, _ := attributes["throughput_capacity"].(float64)
+	return costForFSxFileSystem(ctx, attributes, "Windows", throughput, 7)
+}
+
+// costForFSxONTAP calculates the cost of an AWS FSx for NetApp ONTAP file system.
+// See costForFSxFileSystem. The throughput is throughput_capacity, or throughput_capacity_per_ha_pair for each of the
+// ha_pairs of the file system.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its usage estimates may include the backup storage.
+//   attributes: The attributes of the FSx for NetApp ONTAP file system resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the file system.
+//   An error if the throughput capacity is missing or the pricing data cannot be found.
+func costForFSxONTAP(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	throughput, _ := attributes["throughput_capacity"].(float64)
+	if throughput == 0 {
+		perPair, _ := attributes["throughput_capacity_per_ha_pair"].(float64)
+		pairs, _ := attributes["ha_pairs"].(float64)
+		throughput = perPair * max(pairs, 1)
+	}
+	if throughput == 0 {
+		return nil, missingAttributeError("throughput_capacity")
+	}
+	return costForFSxFileSystem(ctx, attributes, "ONTAP", throughput, 0)
+}
+
+// costForFSxOpenZFS calculates the cost of an AWS FSx for OpenZFS file system.
+// See costForFSxFileSystem.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its usage estimates may include the backup storage.
+//   attributes: The attributes of the FSx for OpenZFS file system resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the file system.
+//   An error if the pricing data cannot be found.
+func costForFSxOpenZFS(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	throughput, _ := attributes["throughput_capacity"].(float64)
+	return costForFSxFileSystem(ctx, attributes, "OpenZFS", throughput, 0)
+}
+
+// costForFSxFileSystem calculates the cost of an FSx file system that is charged for its storage, throughput and
+// IOPS separately, at the rate of its deployment_type: Multi-AZ for MULTI_AZ types and Single-AZ otherwise.
+//
+//   - storage_capacity is charged in GB-months for the storage_type (SSD by default).
+//   - throughput is charged in MBps-months.
+//   - SSD IOPS provisioned in disk_iops_configuration beyond the 3 IOPS per GB included with the storage are charged
+//     in IOPS-months.
+//   - Backups are charged for the fsx.backup_storage_gb usage estimate when automatic backups are enabled.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its usage estimates may include the backup storage.
+//   attributes: The attributes of the FSx file system resource.
+//   fileSystemType: The file system type in the price list (e.g., "Windows").
+//   throughput: The throughput capacity of the file system, in MB/s.
+//   defaultRetentionDays: The number of days automatic backups are kept for if automatic_backup_retention_days is not
+//                         set.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the file system.
+//   An error if the pricing data cannot be found.
+func costForFSxFileSystem(ctx *CalculationContext, attributes map[string]interface{}, fileSystemType string, throughput, defaultRetentionDays float64) (*Cost, error) {
+	deploymentOption := "Single-AZ"
+	if deploymentType, _ := attributes["deployment_type"].(string); strings.HasPrefix(deploymentType, "MULTI_AZ") {
+		deploymentOption = "Multi-AZ"
+	}
+	fs := fsxFileSystem{fileSystemType: fileSystemType, deploymentOption: deploymentOption, storageType: fsxStorageType(attributes)}
+
+	storageGB, _ := attributes["storage_capacity"].(float64)
+	storage, err := fsxComponent(ctx, fs, fsxFamilyStorage, fmt.Sprintf("Storage (%s)", fs.storageType), "GB-month", storageGB)
+	if err != nil {
+		return nil, err
+	}
+	throughputComponent, err := fsxComponent(ctx, fs, fsxFamilyThroughput, "Throughput capacity", "MBps-month", throughput)
+	if err != nil {
+		return nil, err
+	}
+	components := []CostComponent{storage, throughputComponent}
+
+	if iops := firstBlock(attributes, "disk_iops_configuration"); iops != nil && fs.storageType == "SSD" {
+		mode, _ := iops["mode"].(string)
+		provisioned, _ := iops["iops"].(float64)
+		if extra := provisioned - storageGB*fsxIncludedIOPSPerGB; mode == "USER_PROVISIONED" && extra > 0 {
+			component, err := fsxComponent(ctx, fs, fsxFamilyIOPS, "Provisioned SSD IOPS", "IOPS-month", extra)
+			if err != nil {
+				return nil, err
+			}
+			components = append(components, component)
+		}
+	}
+
+	backup, err := fsxBackupComponents(ctx, fs, attributes, defaultRetentionDays)
+	if err != nil {
+		return nil, err
+	}
+	return newCost(append(components, backup...)...), nil
+}
+
+// fsxBackupComponents prices the backups of an FSx file system.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its usage estimates may include the backup storage.
+//   fs: The file system.
+//   attributes: The attributes of the FSx file system resource.
+//   defaultRetentionDays: The number of days automatic backups are kept for if automatic_backup_retention_days is not
+//                         set.
+//
+// Returns:
+//   The cost components of the backups, or none if automatic backups are disabled or no backup storage is estimated.
+//   An error if the pricing data cannot be found.
+func fsxBackupComponents(ctx *CalculationContext, fs fsxFileSystem, attributes map[string]interface{}, defaultRetentionDays float64) ([]CostComponent, error) {
+	retention, ok := attributes["automatic_backup_retention_days"].(float64)
+	if !ok {
+		retention = defaultRetentionDays
+	}
+	if retention <= 0 || ctx.Usage == nil || ctx.Usage.FSx.BackupStorageGB <= 0 {
+		return nil, nil
+	}
+	component, err := fsxComponent(ctx, fs, fsxFamilyBackup, "Backup storage", "GB-month", float64(ctx.Usage.FSx.BackupStorageGB))
+	if err != nil {
+		return nil, err
+	}
+	return []CostComponent{component}, nil
+}
+
+// fsxComponent prices a monthly quantity of an FSx product.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   fs: The file system.
+//   family: The product family (e.g., "Storage"). Backup products match any family containing "Backup".
+//   name: The name of the line item (e.g., "Throughput capacity").
+//   unit: The unit the line item is priced in (e.g., "MBps-month").
+//   monthlyQuantity: The number of units consumed per month.
+//
+// Returns:
+//   The cost component.
+//   An error if the pricing data cannot be found.
+func fsxComponent(ctx *CalculationContext, fs fsxFileSystem, family, name, unit string, monthlyQuantity float64) (CostComponent, error) {
+	var candidates []string
+	for _, sku := range ctx.PriceList.Index().Lookup("AmazonFSx", ctx.Location, pricing.AttrFileSystemType, fs.fileSystemType) {
+		product := ctx.PriceList.Products[sku]
+		attr := product.Attributes
+		if family == fsxFamilyBackup {
+			if !strings.Contains(product.ProductFamily, fsxFamilyBackup) {
+				continue
+			}
+		} else if product.ProductFamily != family {
+			continue
+		}
+		if !matchesOptional(attr.DeploymentOption, fs.deploymentOption) {
+			continue
+		}
+		if family == fsxFamilyStorage && (!matchesOptional(attr.StorageType, fs.storageType) || !matchesOptional(attr.ThroughputCapacity, fs.throughputPerTiB)) {
+			continue
+		}
+		candidates = append(candidates, sku)
+	}
+	sku, price, err := ctx.MatchSKU(fmt.Sprintf("FSx for %s %s (%s)", fs.fileSystemType, strings.ToLower(family), fs.deploymentOption), candidates)
+	if err != nil {
+		return CostComponent{}, err
+	}
+	return newComponent(name, unit, sku, monthlyQuantity, price), nil
+}
+
+// fsxStorageType returns the storage type of an FSx file system, SSD by default.
+func fsxStorageType(attributes map[string]interface{}) string {
+	if storageType, _ := attributes["storage_type"].(string); storageType != "" {
+		return strings.ToUpper(storageType)
+	}
+	return "SSD"
+}
@@ -0,0 +1,126 @@
+package estimator
+
+import (
+	"testing"
+
+	"cloudcostguard/backend/pricing"
+	"cloudcostguard/backend/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+// createFSxPriceList creates a price list with FSx for Lustre, Windows File Server, ONTAP and OpenZFS prices.
+func createFSxPriceList() *pricing.PriceList {
+	priceList := pricing.NewPriceList()
+	add := func(sku, family string, attributes pricing.ProductAttributes, price string) {
+		attributes.ServiceCode = "AmazonFSx"
+		attributes.Location = "US East (N. Virginia)"
+		addMockProduct(priceList, sku, attributes, price)
+		product := priceList.Products[sku]
+		product.ProductFamily = family
+		priceList.Products[sku] = product
+	}
+	add("lustre-scratch", "Storage", pricing.ProductAttributes{FileSystemType: "Lustre", DeploymentOption: "Scratch_2", StorageType: "SSD"}, "0.14")
+	add("lustre-persistent-125", "Storage", pricing.ProductAttributes{FileSystemType: "Lustre", DeploymentOption: "Persistent_2", StorageType: "SSD", ThroughputCapacity: "125"}, "0.145")
+	add("lustre-persistent-250", "Storage", pricing.ProductAttributes{FileSystemType: "Lustre", DeploymentOption: "Persistent_2", StorageType: "SSD", ThroughputCapacity: "250"}, "0.21")
+	add("lustre-backup", "Storage Snapshot Backup", pricing.ProductAttributes{FileSystemType: "Lustre"}, "0.05")
+	add("windows-storage-multi", "Storage", pricing.ProductAttributes{FileSystemType: "Windows", DeploymentOption: "Multi-AZ", StorageType: "SSD"}, "0.23")
+	add("windows-storage-single", "Storage", pricing.ProductAttributes{FileSystemType: "Windows", DeploymentOption: "Single-AZ", StorageType: "SSD"}, "0.13")
+	add("windows-throughput-multi", "Provisioned Throughput", pricing.ProductAttributes{FileSystemType: "Windows", DeploymentOption: "Multi-AZ"}, "4.50")
+	add("windows-throughput-single", "Provisioned Throughput", pricing.ProductAttributes{FileSystemType: "Windows", DeploymentOption: "Single-AZ"}, "2.20")
+	add("windows-backup", "Storage Snapshot Backup", pricing.ProductAttributes{FileSystemType: "Windows"}, "0.05")
+	add("ontap-storage", "Storage", pricing.ProductAttributes{FileSystemType: "ONTAP", DeploymentOption: "Multi-AZ", StorageType: "SSD"}, "0.25")
+	add("ontap-throughput", "Provisioned Throughput", pricing.ProductAttributes{FileSystemType: "ONTAP", DeploymentOption: "Multi-AZ"}, "1.20")
+	add("ontap-iops", "Provisioned IOPS", pricing.ProductAttributes{FileSystemType: "ONTAP", DeploymentOption: "Multi-AZ"}, "0.034")
+	add("openzfs-storage", "Storage", pricing.ProductAttributes{FileSystemType: "OpenZFS", DeploymentOption: "Single-AZ", StorageType: "SSD"}, "0.09")
+	add("openzfs-throughput", "Provisioned Throughput", pricing.ProductAttributes{FileSystemType: "OpenZFS", DeploymentOption: "Single-AZ"}, "0.26")
+	return priceList
+}
+
+func TestFSxPricing(t *testing.T) {
+	priceList := createFSxPriceList()
+	estimate := func(t *testing.T, usage *UsageEstimates, resourceType string, after map[string]interface{}) *EstimationResponse {
+		plan := &terraform.Plan{ResourceChanges: []*terraform.ResourceChange{{
+			Address: resourceType + ".main",
+			Type:    resourceType,
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   after,
+		}}}
+		result, err := Estimate(plan, priceList, "us-east-1", usage)
+		assert.NoError(t, err)
+		return result
+	}
+	usage := &UsageEstimates{FSx: FSxUsage{BackupStorageGB: 500}}
+
+	t.Run("prices Lustre storage by deployment type and throughput per TiB", func(t *testing.T) {
+		result := estimate(t, usage, "aws_fsx_lustre_file_system", map[string]interface{}{
+			"storage_capacity": float64(1200),
+			"deployment_type":  "SCRATCH_2",
+		})
+		assert.InDelta(t, 1200*0.14, result.TotalMonthlyCost, 1e-9)
+
+		result = estimate(t, usage, "aws_fsx_lustre_file_system", map[string]interface{}{
+			"storage_capacity":                float64(1200),
+			"deployment_type":                 "PERSISTENT_2",
+			"per_unit_storage_throughput":     float64(250),
+			"automatic_backup_retention_days": float64(7),
+		})
+		if assert.Len(t, result.Resources, 1) {
+			assert.Equal(t, "lustre-persistent-250", result.Resources[0].Components[0].SKU)
+			assert.Equal(t, "Backup storage", result.Resources[0].Components[1].Name)
+			assert.InDelta(t, 1200*0.21+500*0.05, result.TotalMonthlyCost, 1e-9)
+		}
+	})
+
+	t.Run("prices Windows storage, throughput and backups by deployment type", func(t *testing.T) {
+		result := estimate(t, usage, "aws_fsx_windows_file_system", map[string]interface{}{
+			"storage_capacity":    float64(300),
+			"throughput_capacity": float64(32),
+			"deployment_type":     "MULTI_AZ_1",
+		})
+		if assert.Len(t, result.Resources, 1) {
+			assert.Len(t, result.Resources[0].Components, 3)
+			assert.InDelta(t, 300*0.23+32*4.50+500*0.05, result.TotalMonthlyCost, 1e-9)
+		}
+
+		result = estimate(t, usage, "aws_fsx_windows_file_system", map[string]interface{}{
+			"storage_capacity":                float64(300),
+			"throughput_capacity":             float64(32),
+			"deployment_type":                 "SINGLE_AZ_2",
+			"automatic_backup_retention_days": float64(0),
+		})
+		assert.InDelta(t, 300*0.13+32*2.20, result.TotalMonthlyCost, 1e-9)
+	})
+
+	t.Run("charges ONTAP IOPS beyond those included with the storage", func(t *testing.T) {
+		result := estimate(t, usage, "aws_fsx_ontap_file_system", map[string]interface{}{
+			"storage_capacity":                float64(1024),
+			"throughput_capacity_per_ha_pair": float64(128),
+			"ha_pairs":                        float64(1),
+			"deployment_type":                 "MULTI_AZ_2",
+			"disk_iops_configuration":         []interface{}{map[string]interface{}{"mode": "USER_PROVISIONED", "iops": float64(5072)}},
+		})
+		if assert.Len(t, result.Resources, 1) {
+			assert.Equal(t, "Provisioned SSD IOPS", result.Resources[0].Components[2].Name)
+			assert.InDelta(t, 2000, result.Resources[0].Components[2].MonthlyQuantity, 1e-9)
+			assert.InDelta(t, 1024*0.25+128*1.20+2000*0.034, result.TotalMonthlyCost, 1e-9)
+		}
+	})
+
+	t.Run("prices OpenZFS storage and throughput", func(t *testing.T) {
+		result := estimate(t, usage, "aws_fsx_openzfs_file_system", map[string]interface{}{
+			"storage_capacity":    float64(64),
+			"throughput_capacity": float64(160),
+			"deployment_type":     "SINGLE_AZ_1",
+		})
+		assert.InDelta(t, 64*0.09+160*0.26, result.TotalMonthlyCost, 1e-9)
+	})
+
+	t.Run("skips Lustre file systems with an unknown deployment type", func(t *testing.T) {
+		result := estimate(t, usage, "aws_fsx_lustre_file_system", map[string]interface{}{
+			"storage_capacity": float64(1200),
+			"deployment_type":  "PERSISTENT_3",
+		})
+		assert.Empty(t, result.Resources)
+		assert.Equal(t, SkipReasonMissingAttribute, result.Skipped[0].Reason)
+	})
+}
@@ -0,0 +1,122 @@
+package estimator
+
+import (
+	"fmt"
+)
+
+func init() {
+	MustRegister(NewCalculator("aws_kinesis_stream", []string{"AmazonKinesis"}, nil, costForKinesisStream))
+	MustRegister(NewCalculator("aws_kinesis_firehose_delivery_stream", []string{"AmazonKinesisFirehose"}, nil, costForFirehoseDeliveryStream))
+}
+
+// Usage types of Kinesis data streams and Firehose delivery streams, without a region prefix.
+const (
+	kinesisShardHours         = "Storage-ShardHour"
+	kinesisExtendedShardHours = "Extended-ShardHour"
+	kinesisPutPayloadUnits    = "PutRequestPayloadUnits"
+	kinesisOnDemandStreamHour = "OnDemand-StreamHour"
+	kinesisOnDemandIngested   = "OnDemand-BilledIncomingBytes"
+	kinesisOnDemandRetrieved  = "OnDemand-BilledOutgoingBytes"
+	firehoseIngested          = "BilledBytes"
+	firehoseFormatConversion  = "DataFormatConversion-Bytes"
+)
+
+// kinesisDefaultRetentionHours is the retention period of a Kinesis data stream that is included in its price.
+const kinesisDefaultRetentionHours = 24
+
+// costForKinesisStream calculates the cost of an AWS Kinesis data stream.
+// Streams in PROVISIONED mode (the default) are charged by the hour for each of their shard_count shards, for the
+// PUT payload units in the kinesis.monthly_put_payload_units usage estimate, and by the shard hour for a
+// retention_period longer than 24 hours. Streams in ON_DEMAND mode are charged by the stream hour and for the data in
+// the kinesis.monthly_ingested_gb and kinesis.monthly_retrieved_gb usage estimates.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its usage estimates may include the throughput of the
+//        stream.
+//   attributes: The attributes of the Kinesis stream resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the stream.
+//   An error if the stream mode is unknown or the pricing data cannot be found.
+func costForKinesisStream(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	usage := ctx.Usage
+	if usage == nil {
+		usage = &UsageEstimates{}
+	}
+	var components []CostComponent
+	add := func(name, unit, usageType string, quantity float64) error {
+		if quantity <= 0 {
+			return nil
+		}
+		added, err := usageTypeComponents(ctx, "AmazonKinesis", name, unit, usageType, quantity)
+		components = append(components, added...)
+		return err
+	}
+
+	mode, _ := firstBlock(attributes, "stream_mode_details")["stream_mode"].(string)
+	switch mode {
+	case "", "PROVISIONED":
+		shards, _ := attributes["shard_count"].(float64)
+		if shards <= 0 {
+			return nil, missingAttributeError("shard_count")
+		}
+		if err := add("Shards", "hour", kinesisShardHours, shards*hoursPerMonth); err != nil {
+			return nil, err
+		}
+		if err := add("PUT payload units", "unit", kinesisPutPayloadUnits, float64(usage.Kinesis.MonthlyPutPayloadUnits)); err != nil {
+			return nil, err
+		}
+		if retention, _ := attributes["retention_period"].(float64); retention > kinesisDefaultRetentionHours {
+			if err := add("Extended data retention", "hour", kinesisExtendedShardHours, shards*hoursPerMonth); err != nil {
+				return nil, err
+			}
+		}
+	case "ON_DEMAND":
+		if err := add("On-demand stream", "hour", kinesisOnDemandStreamHour, hoursPerMonth); err != nil {
+			return nil, err
+		}
+		if err := add("Data ingested", "GB", kinesisOnDemandIngested, float64(usage.Kinesis.MonthlyIngestedGB)); err != nil {
+			return nil, err
+		}
+		if err := add("Data retrieved", "GB", kinesisOnDemandRetrieved, float64(usage.Kinesis.MonthlyRetrievedGB)); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown Kinesis stream mode %q", ErrMissingAttribute, mode)
+	}
+
+	return newCost(components...), nil
+}
+
+// costForFirehoseDeliveryStream calculates the cost of an AWS Kinesis Data Firehose delivery stream.
+// The data in the firehose.monthly_ingested_gb usage estimate is charged with its price tiers, and again for format
+// conversion when the extended_s3_configuration enables data_format_conversion_configuration.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its usage estimates may include the data ingested by the
+//        delivery stream.
+//   attributes: The attributes of the Firehose delivery stream resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the delivery stream.
+//   An error if the pricing data cannot be found.
+func costForFirehoseDeliveryStream(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	if ctx.Usage == nil || ctx.Usage.Firehose.MonthlyIngestedGB <= 0 {
+		return newCost(), nil
+	}
+	ingestedGB := float64(ctx.Usage.Firehose.MonthlyIngestedGB)
+	components, err := usageTypeComponents(ctx, "AmazonKinesisFirehose", "Data ingested", "GB", firehoseIngested, ingestedGB)
+	if err != nil {
+		return nil, err
+	}
+
+	conversion := firstBlock(firstBlock(attributes, "extended_s3_configuration"), "data_format_conversion_configuration")
+	if enabled, ok := conversion["enabled"].(bool); conversion != nil && (enabled || !ok) {
+		converted, err := usageTypeComponents(ctx, "AmazonKinesisFirehose", "Format conversion", "GB", firehoseFormatConversion, ingestedGB)
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, converted...)
+	}
+	return newCost(components...), nil
+}
@@ -0,0 +1,85 @@
+package estimator
+
+import (
+	"testing"
+
+	"cloudcostguard/backend/pricing"
+	"cloudcostguard/backend/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+// createKinesisPriceList creates a price list with Kinesis Data Streams and Firehose prices.
+func createKinesisPriceList() *pricing.PriceList {
+	usEast := "US East (N. Virginia)"
+	priceList := pricing.NewPriceList()
+	add := func(sku, serviceCode, usageType string, tiers ...[3]string) {
+		addTieredMockProduct(priceList, sku, pricing.ProductAttributes{ServiceCode: serviceCode, Location: usEast, UsageType: usageType}, tiers...)
+	}
+	add("shard-hour", "AmazonKinesis", "USE1-Storage-ShardHour", [3]string{"0", "Inf", "0.015"})
+	add("extended", "AmazonKinesis", "USE1-Extended-ShardHour", [3]string{"0", "Inf", "0.02"})
+	add("put-units", "AmazonKinesis", "USE1-PutRequestPayloadUnits", [3]string{"0", "Inf", "0.000000014"})
+	add("on-demand-stream", "AmazonKinesis", "USE1-OnDemand-StreamHour", [3]string{"0", "Inf", "0.04"})
+	add("on-demand-in", "AmazonKinesis", "USE1-OnDemand-BilledIncomingBytes", [3]string{"0", "Inf", "0.08"})
+	add("on-demand-out", "AmazonKinesis", "USE1-OnDemand-BilledOutgoingBytes", [3]string{"0", "Inf", "0.04"})
+	add("firehose", "AmazonKinesisFirehose", "USE1-BilledBytes", [3]string{"0", "512000", "0.029"}, [3]string{"512000", "Inf", "0.025"})
+	add("firehose-conversion", "AmazonKinesisFirehose", "USE1-DataFormatConversion-Bytes", [3]string{"0", "Inf", "0.018"})
+	return priceList
+}
+
+func TestKinesisPricing(t *testing.T) {
+	priceList := createKinesisPriceList()
+	estimate := func(t *testing.T, usage *UsageEstimates, resourceType string, after map[string]interface{}) *EstimationResponse {
+		plan := &terraform.Plan{ResourceChanges: []*terraform.ResourceChange{{
+			Address: resourceType + ".main",
+			Type:    resourceType,
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   after,
+		}}}
+		result, err := Estimate(plan, priceList, "us-east-1", usage)
+		assert.NoError(t, err)
+		return result
+	}
+
+	t.Run("charges provisioned streams per shard, PUT payload unit and extended retention", func(t *testing.T) {
+		usage := &UsageEstimates{Kinesis: KinesisUsage{MonthlyPutPayloadUnits: 100000000}}
+		result := estimate(t, usage, "aws_kinesis_stream", map[string]interface{}{"shard_count": float64(4), "retention_period": float64(24)})
+		assert.InDelta(t, 4*730*0.015+1.4, result.TotalMonthlyCost, 1e-9)
+
+		result = estimate(t, usage, "aws_kinesis_stream", map[string]interface{}{
+			"shard_count":         float64(4),
+			"retention_period":    float64(168),
+			"stream_mode_details": []interface{}{map[string]interface{}{"stream_mode": "PROVISIONED"}},
+		})
+		assert.InDelta(t, 4*730*(0.015+0.02)+1.4, result.TotalMonthlyCost, 1e-9)
+	})
+
+	t.Run("charges on-demand streams per stream hour and GB", func(t *testing.T) {
+		usage := &UsageEstimates{Kinesis: KinesisUsage{MonthlyIngestedGB: 1000, MonthlyRetrievedGB: 2000}}
+		result := estimate(t, usage, "aws_kinesis_stream", map[string]interface{}{
+			"stream_mode_details": []interface{}{map[string]interface{}{"stream_mode": "ON_DEMAND"}},
+		})
+		if assert.Len(t, result.Resources, 1) {
+			assert.Len(t, result.Resources[0].Components, 3)
+			assert.InDelta(t, 730*0.04+1000*0.08+2000*0.04, result.TotalMonthlyCost, 1e-9)
+		}
+	})
+
+	t.Run("skips provisioned streams without shards", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{}, "aws_kinesis_stream", map[string]interface{}{})
+		assert.Equal(t, SkipReasonMissingAttribute, result.Skipped[0].Reason)
+	})
+
+	t.Run("charges Firehose ingestion with tiers and format conversion", func(t *testing.T) {
+		usage := &UsageEstimates{Firehose: FirehoseUsage{MonthlyIngestedGB: 600000}}
+		result := estimate(t, usage, "aws_kinesis_firehose_delivery_stream", map[string]interface{}{"destination": "extended_s3"})
+		assert.InDelta(t, 512000*0.029+88000*0.025, result.TotalMonthlyCost, 1e-6)
+
+		result = estimate(t, &UsageEstimates{Firehose: FirehoseUsage{MonthlyIngestedGB: 1000}}, "aws_kinesis_firehose_delivery_stream", map[string]interface{}{
+			"destination": "extended_s3",
+			"extended_s3_configuration": []interface{}{map[string]interface{}{
+				"data_format_conversion_configuration": []interface{}{map[string]interface{}{"enabled": true}},
+			}},
+		})
+		assert.InDelta(t, 1000*(0.029+0.018), result.TotalMonthlyCost, 1e-9)
+	})
+}
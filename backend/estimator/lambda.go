@@ -0,0 +1,71 @@
+package estimator
+
+import "fmt"
+
+func init() {
+	MustRegister(NewCalculator("aws_lambda_function", []string{"AWSLambda"}, nil, costForLambda))
+}
+
+// costForLambda calculates the cost of an AWS Lambda function.
+// It includes both the request price and the GB-second price, and accounts for the free tier.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its usage estimates may include Lambda monthly requests and average duration.
+//   attributes: The attributes of the Lambda function resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the Lambda function.
+//   An error if the pricing data cannot be found.
+func costForLambda(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	memorySize, _ := attributes["memory_size"].(float64)
+	if memorySize == 0 {
+		memorySize = 128 // Default memory size
+	}
+
+	// Functions on Graviton are billed at the ARM request and duration rates.
+	requestUsage, durationUsage := "Request", "Lambda-GB-Second"
+	if architectures, _ := attributes["architectures"].([]interface{}); len(architectures) > 0 && architectures[0] == "arm64" {
+		requestUsage, durationUsage = "Request-ARM", "Lambda-GB-Second-ARM"
+	}
+
+	requestSKU, _, err := ctx.MatchSKU("Lambda requests", usageTypeSKUs(ctx, "AWSLambda", ctx.Location, requestUsage))
+	if err != nil {
+		return nil, err
+	}
+
+	gbSecondSKU, _, err := ctx.MatchSKU("Lambda duration", usageTypeSKUs(ctx, "AWSLambda", ctx.Location, durationUsage))
+	if err != nil {
+		return nil, err
+	}
+
+	usage := ctx.Usage
+	if usage == nil {
+		usage = &UsageEstimates{}
+	}
+
+	// Free tier adjustment: the first 1M requests and 400,000 GB-seconds per month are free.
+	monthlyRequests := float64(usage.LambdaMonthlyRequests)
+	gbSeconds := (memorySize / 1024) * (float64(usage.LambdaAvgDurationMS) / 1000) * monthlyRequests
+
+	billableRequests := monthlyRequests - 1000000
+	if billableRequests < 0 {
+		billableRequests = 0
+	}
+
+	billableGBSeconds := gbSeconds - 400000
+	if billableGBSeconds < 0 {
+		billableGBSeconds = 0
+	}
+
+	components, err := tieredComponents(ctx, "Requests (after free tier)", "request", requestSKU, billableRequests)
+	if err != nil {
+		return nil, err
+	}
+	// Duration is priced in tiers of monthly GB-seconds.
+	durationComponents, err := tieredComponents(ctx, fmt.Sprintf("Duration (%d MB, %dms avg, after free tier)", int(memorySize), usage.LambdaAvgDurationMS), "GB-second", gbSecondSKU, billableGBSeconds)
+	if err != nil {
+		return nil, err
+	}
+
+	return newCost(append(components, durationComponents...)...), nil
+}
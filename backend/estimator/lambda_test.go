@@ -0,0 +1,61 @@
+package estimator
+
+import (
+	"testing"
+
+	"cloudcostguard/backend/pricing"
+	"cloudcostguard/backend/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+// createLambdaPriceList creates a price list with x86, ARM and Provisioned Concurrency Lambda prices.
+func createLambdaPriceList() *pricing.PriceList {
+	usEast := "US East (N. Virginia)"
+	priceList := pricing.NewPriceList()
+	add := func(sku, usageType, price string) {
+		addTieredMockProduct(priceList, sku, pricing.ProductAttributes{ServiceCode: "AWSLambda", Location: usEast, UsageType: usageType}, [3]string{"0", "Inf", price})
+	}
+	add("request", "Request", "0.0000002")
+	add("request-arm", "Request-ARM", "0.0000002")
+	add("duration", "Lambda-GB-Second", "0.0000166667")
+	add("duration-arm", "Lambda-GB-Second-ARM", "0.0000133334")
+	add("prov", "Lambda-Provisioned-GB-Second", "0.0000041667")
+	add("prov-arm", "Lambda-Provisioned-GB-Second-ARM", "0.0000033334")
+	return priceList
+}
+
+func TestLambdaPricing(t *testing.T) {
+	priceList := createLambdaPriceList()
+	usage := &UsageEstimates{LambdaMonthlyRequests: 10000000, LambdaAvgDurationMS: 1000}
+	estimate := func(t *testing.T, after map[string]interface{}) *EstimationResponse {
+		plan := &terraform.Plan{ResourceChanges: []*terraform.ResourceChange{{
+			Address: "aws_lambda_function.api",
+			Type:    "aws_lambda_function",
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   after,
+		}}}
+		result, err := Estimate(plan, priceList, "us-east-1", usage)
+		assert.NoError(t, err)
+		return result
+	}
+
+	t.Run("prices x86 functions at the on-demand duration rate", func(t *testing.T) {
+		result := estimate(t, map[string]interface{}{"memory_size": float64(1024)})
+		assert.Empty(t, result.Warnings)
+		if assert.Len(t, result.Resources, 1) && assert.Len(t, result.Resources[0].Components, 2) {
+			assert.Equal(t, "request", result.Resources[0].Components[0].SKU)
+			assert.Equal(t, "duration", result.Resources[0].Components[1].SKU)
+		}
+		assert.InDelta(t, 9000000*0.0000002+9600000*0.0000166667, result.TotalMonthlyCost, 1e-6)
+	})
+
+	t.Run("prices arm64 functions at the ARM rates", func(t *testing.T) {
+		result := estimate(t, map[string]interface{}{"memory_size": float64(1024), "architectures": []interface{}{"arm64"}})
+		assert.Empty(t, result.Warnings)
+		if assert.Len(t, result.Resources, 1) && assert.Len(t, result.Resources[0].Components, 2) {
+			assert.Equal(t, "request-arm", result.Resources[0].Components[0].SKU)
+			assert.Equal(t, "duration-arm", result.Resources[0].Components[1].SKU)
+		}
+		assert.InDelta(t, 9000000*0.0000002+9600000*0.0000133334, result.TotalMonthlyCost, 1e-6)
+	})
+}
@@ -0,0 +1,119 @@
+package estimator
+
+import (
+	"fmt"
+	"sort"
+
+	"cloudcostguard/backend/pricing"
+)
+
+// MatchSKU selects a single SKU from the candidate SKUs found for a price lookup.
+// Every candidate is considered, and the selection is resolved deterministically:
+//
+//  1. Candidates without a parseable on-demand price are discarded.
+//  2. The candidate with the lowest price is selected.
+//  3. Candidates with the same price are ordered by SKU, and the lowest SKU is selected.
+//
+// When the remaining candidates do not all have the same price, an ambiguous match warning
+// listing every competing SKU is recorded for the resource.
+//
+// Parameters:
+//   description: A description of the lookup used in warnings and errors (e.g., "EC2 instance type: t2.micro").
+//   candidates: The SKUs that matched the lookup.
+//
+// Returns:
+//   The selected SKU and its price.
+//   An error if no candidate has a price.
+func (ctx *CalculationContext) MatchSKU(description string, candidates []string) (string, float64, error) {
+	type candidate struct {
+		sku   string
+		price float64
+	}
+
+	var priced []candidate
+	for _, sku := range candidates {
+		price, err := getPriceFromTerms(sku, ctx.PriceList)
+		if err != nil {
+			continue
+		}
+		priced = append(priced, candidate{sku: sku, price: price})
+	}
+
+	if len(priced) == 0 {
+		return "", 0, fmt.Errorf("%w for %s", ErrPriceNotFound, description)
+	}
+
+	sort.Slice(priced, func(i, j int) bool {
+		if priced[i].price != priced[j].price {
+			return priced[i].price < priced[j].price
+		}
+		return priced[i].sku < priced[j].sku
+	})
+
+	best := priced[0]
+	if best.price != priced[len(priced)-1].price {
+		skus := make([]string, len(priced))
+		for i, c := range priced {
+			skus[i] = c.sku
+		}
+		sort.Strings(skus)
+		ctx.AddWarning(Warning{
+			Code:    WarningAmbiguousMatch,
+			Message: fmt.Sprintf("%d SKUs with different prices match %s; using the lowest-priced SKU %s", len(priced), description, best.sku),
+			SKUs:    skus,
+		})
+	}
+
+	return best.sku, best.price, nil
+}
+
+// AddWarning records a warning for the resource being priced.
+// The warning's address is set to the address of the resource change, and duplicate warnings are ignored.
+//
+// Parameters:
+//   warning: The warning to record.
+func (ctx *CalculationContext) AddWarning(warning Warning) {
+	if ctx.ResourceChange != nil {
+		warning.Address = ctx.ResourceChange.Address
+	}
+	for _, existing := range ctx.warnings {
+		if existing.Code == warning.Code && existing.Message == warning.Message {
+			return
+		}
+	}
+	ctx.warnings = append(ctx.warnings, warning)
+}
+
+// filterSKUs returns the SKUs whose product attributes satisfy a predicate, preserving their order.
+//
+// Parameters:
+//   priceList: The list of AWS prices.
+//   skus: The SKUs to filter.
+//   match: The predicate applied to the attributes of each product.
+//
+// Returns:
+//   The SKUs of the matching products.
+func filterSKUs(priceList *pricing.PriceList, skus []string, match func(attr pricing.ProductAttributes) bool) []string {
+	var matched []string
+	for _, sku := range skus {
+		if match(priceList.Products[sku].Attributes) {
+			matched = append(matched, sku)
+		}
+	}
+	return matched
+}
+
+// usageTypeSKUs returns the SKUs of the products for a service in a location whose usage type is the given usage type
+// with or without a region prefix, e.g. "USE2-ReadCapacityUnit-Hrs" for "ReadCapacityUnit-Hrs".
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   serviceCode: The AWS service code (e.g., "AmazonDynamoDB").
+//   location: The AWS pricing location (e.g., "US East (N. Virginia)").
+//   usageType: The usage type without a region prefix.
+//
+// Returns:
+//   The sorted SKUs of the matching products.
+func usageTypeSKUs(ctx *CalculationContext, serviceCode, location, usageType string) []string {
+	return ctx.PriceList.Index().Lookup(serviceCode, location, pricing.AttrUsageTypeName, usageType)
+}
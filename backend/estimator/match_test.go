@@ -0,0 +1,98 @@
+package estimator
+
+import (
+	"fmt"
+	"testing"
+
+	"cloudcostguard/backend/pricing"
+	"cloudcostguard/backend/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+// addMockProduct adds a product with a single on-demand price to a price list.
+func addMockProduct(priceList *pricing.PriceList, sku string, attributes pricing.ProductAttributes, price string) {
+	if priceList.Terms.OnDemand == nil {
+		priceList.Terms.OnDemand = make(map[string]map[string]pricing.Term)
+	}
+	priceList.Products[sku] = pricing.Product{SKU: sku, Attributes: attributes}
+	dim := pricing.PriceDimension{}
+	dim.PricePerUnit.USD = price
+	priceList.Terms.OnDemand[sku] = map[string]pricing.Term{
+		sku + ".term": {PriceDimensions: map[string]pricing.PriceDimension{sku + ".term.dim": dim}},
+	}
+}
+
+// addTieredMockProduct adds a product with tiered on-demand prices, each tier given as its begin range, end range and
+// price per unit.
+func addTieredMockProduct(priceList *pricing.PriceList, sku string, attributes pricing.ProductAttributes, tiers ...[3]string) {
+	if priceList.Terms.OnDemand == nil {
+		priceList.Terms.OnDemand = make(map[string]map[string]pricing.Term)
+	}
+	priceList.Products[sku] = pricing.Product{SKU: sku, Attributes: attributes}
+	dims := make(map[string]pricing.PriceDimension, len(tiers))
+	for i, tier := range tiers {
+		dim := pricing.PriceDimension{BeginRange: tier[0], EndRange: tier[1]}
+		dim.PricePerUnit.USD = tier[2]
+		dims[fmt.Sprintf("%s.term.dim%d", sku, i)] = dim
+	}
+	priceList.Terms.OnDemand[sku] = map[string]pricing.Term{sku + ".term": {PriceDimensions: dims}}
+}
+
+func TestMatchSKU(t *testing.T) {
+	usEast := "US East (N. Virginia)"
+	priceList := pricing.NewPriceList()
+	addMockProduct(priceList, "rds-oracle", pricing.ProductAttributes{ServiceCode: "AmazonRDS", InstanceClass: "db.m5.large", Location: usEast}, "0.95")
+	addMockProduct(priceList, "rds-mysql", pricing.ProductAttributes{ServiceCode: "AmazonRDS", InstanceClass: "db.m5.large", Location: usEast}, "0.171")
+	addMockProduct(priceList, "rds-postgres", pricing.ProductAttributes{ServiceCode: "AmazonRDS", InstanceClass: "db.m5.large", Location: usEast}, "0.178")
+	addMockProduct(priceList, "ebs-gp2-b", pricing.ProductAttributes{ServiceCode: "AmazonEC2", VolumeAPIName: "gp2", Location: usEast}, "0.10")
+	addMockProduct(priceList, "ebs-gp2-a", pricing.ProductAttributes{ServiceCode: "AmazonEC2", VolumeAPIName: "gp2", Location: usEast}, "0.10")
+	addMockProduct(priceList, "ebs-gp2-unpriced", pricing.ProductAttributes{ServiceCode: "AmazonEC2", VolumeAPIName: "gp2", Location: usEast}, "n/a")
+
+	plan := &terraform.Plan{
+		ResourceChanges: []*terraform.ResourceChange{
+			{
+				Address: "aws_db_instance.main",
+				Type:    "aws_db_instance",
+				Change:  terraform.Change{Actions: []string{"create"}},
+				After:   map[string]interface{}{"instance_class": "db.m5.large"},
+			},
+			{
+				Address: "aws_ebs_volume.data",
+				Type:    "aws_ebs_volume",
+				Change:  terraform.Change{Actions: []string{"create"}},
+				After:   map[string]interface{}{"type": "gp2", "size": float64(10)},
+			},
+		},
+	}
+
+	t.Run("selects the lowest-priced SKU and reports the ambiguity", func(t *testing.T) {
+		result, err := Estimate(plan, priceList, "us-east-1", &UsageEstimates{})
+		assert.NoError(t, err)
+		assert.InDelta(t, 0.171*730, result.Resources[0].MonthlyCost, 0.001)
+
+		assert.Len(t, result.Warnings, 1)
+		warning := result.Warnings[0]
+		assert.Equal(t, "aws_db_instance.main", warning.Address)
+		assert.Equal(t, WarningAmbiguousMatch, warning.Code)
+		assert.Equal(t, []string{"rds-mysql", "rds-oracle", "rds-postgres"}, warning.SKUs)
+	})
+
+	t.Run("does not warn when competing SKUs have the same price", func(t *testing.T) {
+		result, err := Estimate(plan, priceList, "us-east-1", &UsageEstimates{})
+		assert.NoError(t, err)
+		assert.InDelta(t, 1.0, result.Resources[1].MonthlyCost, 0.001)
+		for _, warning := range result.Warnings {
+			assert.NotEqual(t, "aws_ebs_volume.data", warning.Address)
+		}
+	})
+
+	t.Run("returns the same estimate on every run", func(t *testing.T) {
+		first, err := Estimate(plan, priceList, "us-east-1", &UsageEstimates{})
+		assert.NoError(t, err)
+		for i := 0; i < 20; i++ {
+			next, err := Estimate(plan, priceList, "us-east-1", &UsageEstimates{})
+			assert.NoError(t, err)
+			assert.Equal(t, first, next)
+		}
+	})
+}
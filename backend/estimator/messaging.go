@@ -0,0 +1,105 @@
+package estimator
+
+import (
+	"fmt"
+)
+
+func init() {
+	MustRegister(NewCalculator("aws_sqs_queue", []string{"AWSQueueService"}, nil, costForSQSQueue))
+	MustRegister(NewCalculator("aws_sns_topic", []string{"AmazonSNS"}, nil, costForSNSTopic))
+}
+
+// Usage types of SQS queues and SNS topics, without a region prefix.
+const (
+	sqsStandardRequests = "Requests-RBP"
+	sqsFIFORequests     = "Requests-FIFO-RBP"
+	snsPublishRequests  = "Requests-Tier1"
+	snsHTTPDeliveries   = "DeliveryAttempts-HTTP"
+	snsEmailDeliveries  = "DeliveryAttempts-SMTP"
+)
+
+// costForSQSQueue calculates the cost of an AWS SQS queue.
+// The sqs.monthly_requests usage estimate is charged at the FIFO rate for queues with fifo_queue set. The free tier
+// of 1 million requests per month applies to a whole account, so it is not subtracted from the requests of each queue.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its usage estimates may include the requests to the queue.
+//   attributes: The attributes of the SQS queue resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the queue.
+//   An error if the pricing data cannot be found.
+func costForSQSQueue(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	if ctx.Usage == nil || ctx.Usage.SQS.MonthlyRequests <= 0 {
+		return newCost(), nil
+	}
+	name, usageType := "Requests (Standard, account free tier not applied)", sqsStandardRequests
+	if fifo, _ := attributes["fifo_queue"].(bool); fifo {
+		name, usageType = "Requests (FIFO, account free tier not applied)", sqsFIFORequests
+	}
+	components, err := usageTypeComponents(ctx, "AWSQueueService", name, "request", usageType, float64(ctx.Usage.SQS.MonthlyRequests))
+	if err != nil {
+		return nil, err
+	}
+	return newCost(components...), nil
+}
+
+// costForSNSTopic calculates the cost of an AWS SNS topic.
+// Publish requests, HTTP/S deliveries and email deliveries in the sns.monthly_publish_requests,
+// sns.monthly_http_deliveries and sns.monthly_email_deliveries usage estimates are charged in full, because their
+// monthly free tiers apply to a whole account rather than to each topic. Deliveries to SQS queues and Lambda functions
+// are free.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its usage estimates may include the requests and
+//        deliveries of the topic.
+//   attributes: The attributes of the SNS topic resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the topic.
+//   An error if the pricing data cannot be found.
+func costForSNSTopic(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	if ctx.Usage == nil {
+		return newCost(), nil
+	}
+	var components []CostComponent
+	for _, charge := range []struct {
+		name, usageType string
+		quantity        float64
+	}{
+		{"Publish requests (account free tier not applied)", snsPublishRequests, float64(ctx.Usage.SNS.MonthlyPublishRequests)},
+		{"HTTP/S deliveries (account free tier not applied)", snsHTTPDeliveries, float64(ctx.Usage.SNS.MonthlyHTTPDeliveries)},
+		{"Email deliveries (account free tier not applied)", snsEmailDeliveries, float64(ctx.Usage.SNS.MonthlyEmailDeliveries)},
+	} {
+		if charge.quantity <= 0 {
+			continue
+		}
+		added, err := usageTypeComponents(ctx, "AmazonSNS", charge.name, "request", charge.usageType, charge.quantity)
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, added...)
+	}
+	return newCost(components...), nil
+}
+
+// usageTypeComponents prices a monthly quantity of a usage type in the region of the estimate with its price tiers.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   serviceCode: The AWS service code (e.g., "AmazonSNS").
+//   name: The name of the line item (e.g., "Publish requests").
+//   unit: The unit the line item is priced in (e.g., "request").
+//   usageType: The usage type without a region prefix (e.g., "Requests-Tier1").
+//   monthlyQuantity: The number of units consumed per month.
+//
+// Returns:
+//   The cost components.
+//   An error if the pricing data cannot be found.
+func usageTypeComponents(ctx *CalculationContext, serviceCode, name, unit, usageType string, monthlyQuantity float64) ([]CostComponent, error) {
+	sku, _, err := ctx.MatchSKU(fmt.Sprintf("%s %s", serviceCode, usageType), usageTypeSKUs(ctx, serviceCode, ctx.Location, usageType))
+	if err != nil {
+		return nil, err
+	}
+	return tieredComponents(ctx, name, unit, sku, monthlyQuantity)
+}
@@ -0,0 +1,79 @@
+package estimator
+
+import (
+	"testing"
+
+	"cloudcostguard/backend/pricing"
+	"cloudcostguard/backend/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+// createMessagingPriceList creates a price list with SQS request and SNS publish and delivery prices.
+func createMessagingPriceList() *pricing.PriceList {
+	usEast := "US East (N. Virginia)"
+	priceList := pricing.NewPriceList()
+	add := func(sku, serviceCode, usageType string, tiers ...[3]string) {
+		addTieredMockProduct(priceList, sku, pricing.ProductAttributes{ServiceCode: serviceCode, Location: usEast, UsageType: usageType}, tiers...)
+	}
+	add("sqs-standard", "AWSQueueService", "USE1-Requests-RBP", [3]string{"0", "100000000000", "0.0000004"}, [3]string{"100000000000", "Inf", "0.0000003"})
+	add("sqs-fifo", "AWSQueueService", "USE1-Requests-FIFO-RBP", [3]string{"0", "100000000000", "0.0000005"}, [3]string{"100000000000", "Inf", "0.0000004"})
+	add("sns-publish", "AmazonSNS", "USE1-Requests-Tier1", [3]string{"0", "Inf", "0.0000005"})
+	add("sns-http", "AmazonSNS", "USE1-DeliveryAttempts-HTTP", [3]string{"0", "Inf", "0.0000006"})
+	add("sns-email", "AmazonSNS", "USE1-DeliveryAttempts-SMTP", [3]string{"0", "Inf", "0.00002"})
+	return priceList
+}
+
+func TestMessagingPricing(t *testing.T) {
+	priceList := createMessagingPriceList()
+	estimate := func(t *testing.T, usage *UsageEstimates, resourceType string, after map[string]interface{}) *EstimationResponse {
+		plan := &terraform.Plan{ResourceChanges: []*terraform.ResourceChange{{
+			Address: resourceType + ".main",
+			Type:    resourceType,
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   after,
+		}}}
+		result, err := Estimate(plan, priceList, "us-east-1", usage)
+		assert.NoError(t, err)
+		return result
+	}
+
+	t.Run("charges SQS requests without the account free tier", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{SQS: SQSUsage{MonthlyRequests: 10000000}}, "aws_sqs_queue", map[string]interface{}{"name": "jobs"})
+		if assert.Len(t, result.Resources, 1) {
+			assert.Equal(t, "Requests (Standard, account free tier not applied) (first 100000000000 request)", result.Resources[0].Components[0].Name)
+			assert.InDelta(t, 4.0, result.TotalMonthlyCost, 1e-9)
+		}
+
+		result = estimate(t, &UsageEstimates{SQS: SQSUsage{MonthlyRequests: 10000000}}, "aws_sqs_queue", map[string]interface{}{"fifo_queue": true})
+		assert.InDelta(t, 5.0, result.TotalMonthlyCost, 1e-9)
+
+		result = estimate(t, &UsageEstimates{}, "aws_sqs_queue", map[string]interface{}{})
+		assert.Zero(t, result.TotalMonthlyCost)
+		assert.Equal(t, 1, result.Coverage.PricedResources)
+	})
+
+	t.Run("does not grant the SQS free tier to every queue", func(t *testing.T) {
+		plan := &terraform.Plan{}
+		for _, address := range []string{"aws_sqs_queue.a", "aws_sqs_queue.b", "aws_sqs_queue.c"} {
+			plan.ResourceChanges = append(plan.ResourceChanges, &terraform.ResourceChange{
+				Address: address,
+				Type:    "aws_sqs_queue",
+				Change:  terraform.Change{Actions: []string{"create"}},
+				After:   map[string]interface{}{},
+			})
+		}
+		result, err := Estimate(plan, priceList, "us-east-1", &UsageEstimates{SQS: SQSUsage{MonthlyRequests: 900000}})
+		assert.NoError(t, err)
+		assert.InDelta(t, 3*900000*0.0000004, result.TotalMonthlyCost, 1e-9)
+	})
+
+	t.Run("charges SNS publishes and deliveries without the account free tiers", func(t *testing.T) {
+		usage := &UsageEstimates{SNS: SNSUsage{MonthlyPublishRequests: 2000000, MonthlyHTTPDeliveries: 1000000, MonthlyEmailDeliveries: 500}}
+		result := estimate(t, usage, "aws_sns_topic", map[string]interface{}{"name": "events"})
+		if assert.Len(t, result.Resources, 1) {
+			assert.Len(t, result.Resources[0].Components, 3)
+			assert.Equal(t, "Publish requests (account free tier not applied)", result.Resources[0].Components[0].Name)
+			assert.InDelta(t, 2000000*0.0000005+1000000*0.0000006+500*0.00002, result.TotalMonthlyCost, 1e-9)
+		}
+	})
+}
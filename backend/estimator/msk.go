@@ -0,0 +1,54 @@
+package estimator
+
+import (
+	"fmt"
+
+	"cloudcostguard/backend/pricing"
+)
+
+func init() {
+	MustRegister(NewCalculator("aws_msk_cluster", []string{"AmazonMSK"}, []string{"number_of_broker_nodes"}, costForMSKCluster))
+}
+
+// mskBrokerStorage is the usage type of MSK broker storage, without a region prefix.
+const mskBrokerStorage = "Kafka.Storage.GP2"
+
+// costForMSKCluster calculates the cost of an AWS MSK (Managed Streaming for Apache Kafka) cluster.
+// Each of the number_of_broker_nodes brokers is charged by the hour for the instance_type of the
+// broker_node_group_info, and for the EBS storage of its storage_info (ebs_volume_size in older providers) in
+// GB-months.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   attributes: The attributes of the MSK cluster resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the cluster.
+//   An error if the broker instance type is missing or the pricing data cannot be found.
+func costForMSKCluster(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	brokers, _ := attributes["number_of_broker_nodes"].(float64)
+	nodeGroup := firstBlock(attributes, "broker_node_group_info")
+	instanceType, _ := nodeGroup["instance_type"].(string)
+	if instanceType == "" {
+		return nil, missingAttributeError("broker_node_group_info.instance_type")
+	}
+
+	instanceSKU, instancePrice, err := ctx.MatchSKU(fmt.Sprintf("MSK broker instance type: %s", instanceType), ctx.PriceList.Index().Lookup("AmazonMSK", ctx.Location, pricing.AttrInstanceType, instanceType))
+	if err != nil {
+		return nil, err
+	}
+	components := []CostComponent{hourlyComponent(fmt.Sprintf("Broker instances (%s)", instanceType), instanceSKU, brokers, instancePrice)}
+
+	volumeSize, _ := firstBlock(firstBlock(nodeGroup, "storage_info"), "ebs_storage_info")["volume_size"].(float64)
+	if volumeSize == 0 {
+		volumeSize, _ = nodeGroup["ebs_volume_size"].(float64)
+	}
+	if volumeSize > 0 {
+		storage, err := usageTypeComponents(ctx, "AmazonMSK", "Broker storage", "GB-month", mskBrokerStorage, volumeSize*brokers)
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, storage...)
+	}
+	return newCost(components...), nil
+}
@@ -0,0 +1,67 @@
+package estimator
+
+import (
+	"testing"
+
+	"cloudcostguard/backend/pricing"
+	"cloudcostguard/backend/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+// createMSKPriceList creates a price list with MSK broker instance and storage prices.
+func createMSKPriceList() *pricing.PriceList {
+	usEast := "US East (N. Virginia)"
+	priceList := pricing.NewPriceList()
+	addMockProduct(priceList, "m5-large", pricing.ProductAttributes{ServiceCode: "AmazonMSK", Location: usEast, InstanceType: "kafka.m5.large", UsageType: "USE1-Kafka.m5.large"}, "0.21")
+	addMockProduct(priceList, "m5-xlarge", pricing.ProductAttributes{ServiceCode: "AmazonMSK", Location: usEast, InstanceType: "kafka.m5.xlarge", UsageType: "USE1-Kafka.m5.xlarge"}, "0.42")
+	addMockProduct(priceList, "storage", pricing.ProductAttributes{ServiceCode: "AmazonMSK", Location: usEast, UsageType: "USE1-Kafka.Storage.GP2"}, "0.10")
+	return priceList
+}
+
+func TestMSKPricing(t *testing.T) {
+	priceList := createMSKPriceList()
+	estimate := func(t *testing.T, after map[string]interface{}) *EstimationResponse {
+		plan := &terraform.Plan{ResourceChanges: []*terraform.ResourceChange{{
+			Address: "aws_msk_cluster.events",
+			Type:    "aws_msk_cluster",
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   after,
+		}}}
+		result, err := Estimate(plan, priceList, "us-east-1", &UsageEstimates{})
+		assert.NoError(t, err)
+		return result
+	}
+
+	t.Run("charges every broker for its instance and storage", func(t *testing.T) {
+		result := estimate(t, map[string]interface{}{
+			"number_of_broker_nodes": float64(3),
+			"broker_node_group_info": []interface{}{map[string]interface{}{
+				"instance_type": "kafka.m5.xlarge",
+				"storage_info": []interface{}{map[string]interface{}{
+					"ebs_storage_info": []interface{}{map[string]interface{}{"volume_size": float64(1000)}},
+				}},
+			}},
+		})
+		if assert.Len(t, result.Resources, 1) {
+			components := result.Resources[0].Components
+			assert.Equal(t, "Broker instances (kafka.m5.xlarge)", components[0].Name)
+			assert.InDelta(t, 3*730, components[0].MonthlyQuantity, 1e-9)
+			assert.InDelta(t, 3000, components[1].MonthlyQuantity, 1e-9)
+			assert.InDelta(t, 3*730*0.42+3000*0.10, result.TotalMonthlyCost, 1e-9)
+		}
+	})
+
+	t.Run("reads the volume size of older providers", func(t *testing.T) {
+		result := estimate(t, map[string]interface{}{
+			"number_of_broker_nodes": float64(2),
+			"broker_node_group_info": []interface{}{map[string]interface{}{"instance_type": "kafka.m5.large", "ebs_volume_size": float64(100)}},
+		})
+		assert.InDelta(t, 2*730*0.21+200*0.10, result.TotalMonthlyCost, 1e-9)
+	})
+
+	t.Run("skips clusters without a broker instance type", func(t *testing.T) {
+		result := estimate(t, map[string]interface{}{"number_of_broker_nodes": float64(3)})
+		assert.Empty(t, result.Resources)
+		assert.Equal(t, SkipReasonMissingAttribute, result.Skipped[0].Reason)
+	})
+}
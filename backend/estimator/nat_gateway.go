@@ -0,0 +1,46 @@
+package estimator
+
+import "cloudcostguard/backend/pricing"
+
+func init() {
+	MustRegister(NewCalculator("aws_nat_gateway", []string{"AmazonVPC"}, nil, costForNATGateway))
+}
+
+// costForNATGateway calculates the cost of an AWS NAT Gateway.
+// It includes both the hourly price and the data processing price.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its usage estimates may include NAT Gateway data processing volume.
+//   attributes: The attributes of the NAT Gateway resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the NAT Gateway.
+//   An error if the pricing data cannot be found.
+func costForNATGateway(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	hourlySKU, hourlyPrice, err := ctx.MatchSKU("NAT Gateway hours", ctx.PriceList.Index().Lookup("AmazonVPC", ctx.Location, pricing.AttrGroup, "NAT Gateway"))
+	if err != nil {
+		return nil, err
+	}
+
+	// Data processing is only charged when a price is published for the region.
+	dataProcessingSKUs := usageTypeSKUs(ctx, "AmazonVPC", ctx.Location, "NatGateway-Bytes")
+	var dataProcessingSKU string
+	var dataProcessingPrice float64
+	if len(dataProcessingSKUs) > 0 {
+		dataProcessingSKU, dataProcessingPrice, err = ctx.MatchSKU("NAT Gateway data processing", dataProcessingSKUs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	components := []CostComponent{hourlyComponent("NAT gateway usage", hourlySKU, 1, hourlyPrice)}
+	if dataProcessingPrice > 0 && ctx.Usage != nil && ctx.Usage.NATGatewayGBProcessed > 0 {
+		dataComponents, err := tieredComponents(ctx, "Data processed", "GB", dataProcessingSKU, float64(ctx.Usage.NATGatewayGBProcessed))
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, dataComponents...)
+	}
+
+	return newCost(components...), nil
+}
@@ -0,0 +1,142 @@
+package estimator
+
+import "cloudcostguard/backend/terraform"
+
+// findPlanResource finds the attributes of a resource in the plan by address, ID or name.
+// References to resources created in the same plan are unknown until apply, so when no reference is known, the only
+// resource of the type in the plan is used.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   resourceType: The type of the resource (e.g., "aws_launch_template").
+//   references: The known addresses, IDs or names of the resource.
+//
+// Returns:
+//   The attributes of the resource after the change, or nil if the plan does not contain it.
+func findPlanResource(ctx *CalculationContext, resourceType string, references ...string) map[string]interface{} {
+	if ctx.Plan == nil {
+		return nil
+	}
+	var candidates []map[string]interface{}
+	known := false
+	for _, rc := range ctx.Plan.ResourceChanges {
+		if rc.Type != resourceType || rc.After == nil {
+			continue
+		}
+		candidates = append(candidates, rc.After)
+		for _, reference := range references {
+			if reference == "" {
+				continue
+			}
+			known = true
+			id, _ := rc.After["id"].(string)
+			name, _ := rc.After["name"].(string)
+			if reference == rc.Address || reference == id || reference == name {
+				return rc.After
+			}
+		}
+	}
+	if !known && len(candidates) == 1 {
+		return candidates[0]
+	}
+	return nil
+}
+
+// findPlanResourceByAttribute finds the attributes of a resource in the plan by an identifying attribute, such as
+// the cluster_identifier of an RDS cluster, falling back to findPlanResource.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   resourceType: The type of the resource (e.g., "aws_rds_cluster").
+//   attribute: The identifying attribute of the resource (e.g., "cluster_identifier").
+//   value: The value of the attribute, which may be empty if it is not known until apply.
+//
+// Returns:
+//   The attributes of the resource after the change, or nil if the plan does not contain it.
+func findPlanResourceByAttribute(ctx *CalculationContext, resourceType, attribute, value string) map[string]interface{} {
+	if ctx.Plan != nil && value != "" {
+		for _, rc := range ctx.Plan.ResourceChanges {
+			if rc.Type != resourceType || rc.After == nil {
+				continue
+			}
+			if identifier, _ := rc.After[attribute].(string); identifier == value {
+				return rc.After
+			}
+		}
+	}
+	return findPlanResource(ctx, resourceType, value)
+}
+
+// resourceAttributes returns the attributes of a resource after a change, or before it if it is deleted.
+func resourceAttributes(rc *terraform.ResourceChange) map[string]interface{} {
+	if rc.After != nil {
+		return rc.After
+	}
+	return rc.Before
+}
+
+// planResourceAttributes finds a resource in the plan by its address.
+//
+// Parameters:
+//   plan: The Terraform plan.
+//   address: The address of the resource.
+//
+// Returns:
+//   The attributes of the resource, and true if it is in the plan.
+func planResourceAttributes(plan *terraform.Plan, address string) (map[string]interface{}, bool) {
+	if plan == nil {
+		return nil, false
+	}
+	for _, rc := range plan.ResourceChanges {
+		if rc.Address == address && !rc.IsData() {
+			return resourceAttributes(rc), true
+		}
+	}
+	return nil, false
+}
+
+// resourceLocation determines the AWS pricing location of a resource from its region or availability zones.
+// Resources such as the clusters of a global database usually run in other regions than the one the plan is
+// estimated for.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   attributes: The attributes of the resource, or nil if it is not in the plan.
+//
+// Returns:
+//   The location of the resource's region, or the location of the estimate if it is not known.
+func resourceLocation(ctx *CalculationContext, attributes map[string]interface{}) string {
+	if region := resourceRegion(attributes); region != "" {
+		return regionMap[region]
+	}
+	return ctx.Location
+}
+
+// resourceRegion determines the region of a resource from its region attribute, its availability zone (e.g.,
+// "us-west-2a") or the first of its availability zones.
+//
+// Parameters:
+//   attributes: The attributes of the resource, or nil if it is not in the plan.
+//
+// Returns:
+//   The region code of the resource, or an empty string if it is not known.
+func resourceRegion(attributes map[string]interface{}) string {
+	if region, _ := attributes["region"].(string); regionMap[region] != "" {
+		return region
+	}
+	zone := resourceZone(attributes)
+	if zones, _ := attributes["availability_zones"].([]interface{}); zone == "" && len(zones) > 0 {
+		zone, _ = zones[0].(string)
+	}
+	if len(zone) < 2 || regionMap[zone[:len(zone)-1]] == "" {
+		return ""
+	}
+	return zone[:len(zone)-1]
+}
+
+// resourceZone returns the availability zone of a resource (e.g., "us-east-1a"), or an empty string if it is not
+// known.
+func resourceZone(attributes map[string]interface{}) string {
+	zone, _ := attributes["availability_zone"].(string)
+	return zone
+}
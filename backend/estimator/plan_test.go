@@ -0,0 +1,53 @@
+package estimator
+
+import (
+	"testing"
+
+	"cloudcostguard/backend/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceLocation(t *testing.T) {
+	ctx := &CalculationContext{Location: "US East (N. Virginia)"}
+	tests := []struct {
+		name       string
+		attributes map[string]interface{}
+		want       string
+	}{
+		{"region attribute", map[string]interface{}{"region": "eu-west-1", "availability_zone": "us-west-2a"}, "EU (Ireland)"},
+		{"availability zone", map[string]interface{}{"availability_zone": "us-west-2a"}, "US West (Oregon)"},
+		{"first of the availability zones", map[string]interface{}{"availability_zones": []interface{}{"eu-west-1b", "eu-west-1c"}}, "EU (Ireland)"},
+		{"unknown region", map[string]interface{}{"availability_zone": "mars-north-1a"}, "US East (N. Virginia)"},
+		{"resource not in the plan", nil, "US East (N. Virginia)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, resourceLocation(ctx, tt.attributes))
+		})
+	}
+}
+
+func TestFindPlanResource(t *testing.T) {
+	plan := &terraform.Plan{ResourceChanges: []*terraform.ResourceChange{
+		{Address: "aws_rds_cluster.main", Type: "aws_rds_cluster", After: map[string]interface{}{"cluster_identifier": "main"}},
+		{Address: "aws_rds_cluster.replica", Type: "aws_rds_cluster", After: map[string]interface{}{"cluster_identifier": "replica"}},
+		{Address: "aws_launch_template.web", Type: "aws_launch_template", After: map[string]interface{}{"name": "web"}},
+	}}
+	ctx := &CalculationContext{Plan: plan}
+
+	t.Run("finds a resource by an identifying attribute", func(t *testing.T) {
+		assert.Equal(t, plan.ResourceChanges[1].After, findPlanResourceByAttribute(ctx, "aws_rds_cluster", "cluster_identifier", "replica"))
+		assert.Nil(t, findPlanResourceByAttribute(ctx, "aws_rds_cluster", "cluster_identifier", "other"))
+	})
+
+	t.Run("uses the only resource of the type when no reference is known", func(t *testing.T) {
+		assert.Equal(t, plan.ResourceChanges[2].After, findPlanResource(ctx, "aws_launch_template", ""))
+		assert.Nil(t, findPlanResource(ctx, "aws_rds_cluster", ""))
+	})
+
+	t.Run("finds a resource by address", func(t *testing.T) {
+		attributes, ok := planResourceAttributes(plan, "aws_rds_cluster.main")
+		assert.True(t, ok)
+		assert.Equal(t, "main", attributes["cluster_identifier"])
+	})
+}
@@ -0,0 +1,254 @@
+package estimator
+
+import (
+	"fmt"
+	"strings"
+
+	"cloudcostguard/backend/pricing"
+)
+
+// Pricing terms that a PricingModel can select.
+const (
+	// PricingTermOnDemand prices resources at their on-demand rates.
+	PricingTermOnDemand = "on_demand"
+	// PricingTermReserved prices resources at the rates of a reserved commitment.
+	PricingTermReserved = "reserved"
+	// PricingTermSavingsPlan prices resources at the rates of a Savings Plan.
+	PricingTermSavingsPlan = "savings_plan"
+)
+
+// Savings Plan types that a PricingModel can select.
+const (
+	// SavingsPlanTypeCompute selects a Compute Savings Plan, which covers EC2 instances of any family and region.
+	SavingsPlanTypeCompute = "compute"
+	// SavingsPlanTypeEC2Instance selects an EC2 Instance Savings Plan, which covers the instances of one family.
+	SavingsPlanTypeEC2Instance = "ec2_instance"
+)
+
+// savingsPlanTypes maps the Savings Plan types of a PricingModel to their name and to their product family in the
+// Savings Plans offer files.
+var savingsPlanTypes = map[string]struct {
+	name          string
+	productFamily string
+}{
+	SavingsPlanTypeCompute:     {"Compute", pricing.SavingsPlanCompute},
+	SavingsPlanTypeEC2Instance: {"EC2 Instance", pricing.SavingsPlanEC2Instance},
+}
+
+// defaultCommitment is the commitment that resources priced on demand are compared against.
+var defaultCommitment = PricingModel{
+	Term:                PricingTermReserved,
+	LeaseContractLength: "1yr",
+	PurchaseOption:      "No Upfront",
+	OfferingClass:       "standard",
+}
+
+// PricingModel selects how the resources of a type are priced, such as "EC2 at 1yr No Upfront reserved".
+type PricingModel struct {
+	// Term is the pricing term: "on_demand" (the default), "reserved" or "savings_plan".
+	Term string `yaml:"term" json:"term"`
+	// LeaseContractLength is the length of a reserved commitment or Savings Plan: "1yr" (the default) or "3yr".
+	LeaseContractLength string `yaml:"lease_contract_length,omitempty" json:"lease_contract_length,omitempty"`
+	// PurchaseOption is how a reserved commitment or Savings Plan is paid: "No Upfront" (the default),
+	// "Partial Upfront" or "All Upfront".
+	PurchaseOption string `yaml:"purchase_option,omitempty" json:"purchase_option,omitempty"`
+	// OfferingClass is the offering class of a reserved commitment: "standard" (the default) or "convertible".
+	OfferingClass string `yaml:"offering_class,omitempty" json:"offering_class,omitempty"`
+	// SavingsPlanType is the type of a Savings Plan: "compute" (the default) or "ec2_instance".
+	SavingsPlanType string `yaml:"savings_plan_type,omitempty" json:"savings_plan_type,omitempty"`
+}
+
+// CommitmentComparison compares the on-demand cost of a resource with its cost under a reserved commitment or
+// Savings Plan, at list prices.
+type CommitmentComparison struct {
+	// Model describes the commitment (e.g., "1yr No Upfront standard reserved" or "1yr No Upfront Compute Savings Plan").
+	Model string `json:"model"`
+	// OnDemandMonthlyCost is the estimated monthly cost of the resource at on-demand rates.
+	OnDemandMonthlyCost float64 `json:"on_demand_monthly_cost"`
+	// CommittedMonthlyCost is the estimated monthly cost of the resource under the commitment,
+	// with upfront fees amortized over the length of the commitment.
+	CommittedMonthlyCost float64 `json:"committed_monthly_cost"`
+	// UpfrontCost is the fee paid once at the start of the commitment.
+	UpfrontCost float64 `json:"upfront_cost"`
+	// MonthlySavings is OnDemandMonthlyCost minus CommittedMonthlyCost.
+	MonthlySavings float64 `json:"monthly_savings"`
+}
+
+// Validate checks that the pricing model is well-formed.
+//
+// Returns:
+//   An error describing the first invalid field, or nil if the pricing model is valid.
+func (m PricingModel) Validate() error {
+	switch m.Term {
+	case "", PricingTermOnDemand:
+		if m.LeaseContractLength != "" || m.PurchaseOption != "" || m.OfferingClass != "" || m.SavingsPlanType != "" {
+			return fmt.Errorf("commitment options require term %q or %q", PricingTermReserved, PricingTermSavingsPlan)
+		}
+		return nil
+	case PricingTermReserved:
+		if m.SavingsPlanType != "" {
+			return fmt.Errorf("savings plan type requires term %q", PricingTermSavingsPlan)
+		}
+	case PricingTermSavingsPlan:
+		if m.OfferingClass != "" {
+			return fmt.Errorf("offering class requires term %q", PricingTermReserved)
+		}
+		if _, ok := savingsPlanTypes[strings.ToLower(m.SavingsPlanType)]; !ok && m.SavingsPlanType != "" {
+			return fmt.Errorf("unknown savings plan type %q", m.SavingsPlanType)
+		}
+	default:
+		return fmt.Errorf("unknown pricing term %q", m.Term)
+	}
+
+	switch strings.ToLower(m.LeaseContractLength) {
+	case "", "1yr", "3yr":
+	default:
+		return fmt.Errorf("unknown lease contract length %q", m.LeaseContractLength)
+	}
+	switch strings.ToLower(m.PurchaseOption) {
+	case "", "no upfront", "partial upfront", "all upfront":
+	default:
+		return fmt.Errorf("unknown purchase option %q", m.PurchaseOption)
+	}
+	switch strings.ToLower(m.OfferingClass) {
+	case "", "standard", "convertible":
+	default:
+		return fmt.Errorf("unknown offering class %q", m.OfferingClass)
+	}
+	return nil
+}
+
+// IsReserved reports whether the pricing model selects a reserved commitment.
+func (m PricingModel) IsReserved() bool {
+	return m.Term == PricingTermReserved
+}
+
+// IsSavingsPlan reports whether the pricing model selects a Savings Plan.
+func (m PricingModel) IsSavingsPlan() bool {
+	return m.Term == PricingTermSavingsPlan
+}
+
+// String describes the pricing model, e.g. "on_demand", "1yr No Upfront standard reserved" or
+// "3yr All Upfront EC2 Instance Savings Plan".
+func (m PricingModel) String() string {
+	switch {
+	case m.IsReserved():
+		m = m.withDefaults()
+		return fmt.Sprintf("%s %s %s reserved", m.LeaseContractLength, m.PurchaseOption, m.OfferingClass)
+	case m.IsSavingsPlan():
+		m = m.withDefaults()
+		return fmt.Sprintf("%s %s %s Savings Plan", m.LeaseContractLength, m.PurchaseOption, savingsPlanTypes[m.SavingsPlanType].name)
+	default:
+		return PricingTermOnDemand
+	}
+}
+
+// withDefaults fills in the unset commitment options of a reserved or Savings Plan pricing model.
+func (m PricingModel) withDefaults() PricingModel {
+	if m.LeaseContractLength == "" {
+		m.LeaseContractLength = defaultCommitment.LeaseContractLength
+	}
+	if m.PurchaseOption == "" {
+		m.PurchaseOption = defaultCommitment.PurchaseOption
+	}
+	if m.IsReserved() && m.OfferingClass == "" {
+		m.OfferingClass = defaultCommitment.OfferingClass
+	}
+	if m.IsSavingsPlan() {
+		m.SavingsPlanType = strings.ToLower(m.SavingsPlanType)
+		if m.SavingsPlanType == "" {
+			m.SavingsPlanType = SavingsPlanTypeCompute
+		}
+	}
+	return m
+}
+
+// applyPricingModel prices the hourly components of a cost under the pricing model of the calculation context and
+// compares the result with on-demand rates. Resources priced on demand are compared with the default commitment
+// (1yr No Upfront standard reserved). Components without a matching reserved term or Savings Plan rate, components
+// that are not billed per hour, and spot instance hours keep their price under the commitment.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   cost: The on-demand cost of the resource, as returned by its calculator. It is updated in place when the
+//     pricing model selects a reserved commitment or Savings Plan.
+//
+// Returns:
+//   The cost of the resource under the pricing model.
+func applyPricingModel(ctx *CalculationContext, cost *Cost) *Cost {
+	model := ctx.PricingModel
+	commitment := defaultCommitment
+	if model.IsReserved() || model.IsSavingsPlan() {
+		commitment = model.withDefaults()
+	}
+
+	comparison := &CommitmentComparison{Model: commitment.String()}
+	components := make([]CostComponent, len(cost.Components))
+	committed := false
+	for i, component := range cost.Components {
+		components[i] = component
+		comparison.OnDemandMonthlyCost += component.MonthlyCost
+		if component.Unit != "hour" || component.SKU == "" || component.spot {
+			comparison.CommittedMonthlyCost += component.MonthlyCost
+			continue
+		}
+		committedComponent, upfrontCost, ok := commitment.price(ctx.PriceList, component)
+		if !ok {
+			comparison.CommittedMonthlyCost += component.MonthlyCost
+			continue
+		}
+		committed = true
+		components[i] = committedComponent
+		comparison.CommittedMonthlyCost += committedComponent.MonthlyCost
+		comparison.UpfrontCost += upfrontCost
+	}
+	if !committed {
+		return cost
+	}
+	comparison.MonthlySavings = comparison.OnDemandMonthlyCost - comparison.CommittedMonthlyCost
+
+	if model.IsReserved() || model.IsSavingsPlan() {
+		cost = newCost(components...)
+	}
+	cost.Commitment = comparison
+	return cost
+}
+
+// price prices an hourly component under a reserved or Savings Plan commitment. Reserved prices amortize the upfront
+// fee over the lease. Savings Plan rates already are effective hourly rates, and the upfront share of the plan pays
+// for part of the hourly commitment over the whole lease.
+//
+// Parameters:
+//   priceList: The price list to look up the reserved terms and Savings Plan rates in.
+//   component: The hourly component at on-demand rates.
+//
+// Returns:
+//   The component under the commitment.
+//   The fee paid at the start of the commitment.
+//   True if the SKU of the component has a reserved term or Savings Plan rate matching the commitment.
+func (m PricingModel) price(priceList *pricing.PriceList, component CostComponent) (CostComponent, float64, bool) {
+	attributes := pricing.TermAttributes{
+		LeaseContractLength: m.LeaseContractLength,
+		PurchaseOption:      m.PurchaseOption,
+		OfferingClass:       m.OfferingClass,
+	}
+	instances := component.MonthlyQuantity / hoursPerMonth
+
+	if m.IsSavingsPlan() {
+		rate, ok := priceList.FindSavingsPlanRate(component.SKU, savingsPlanTypes[m.SavingsPlanType].productFamily, attributes)
+		if !ok {
+			return CostComponent{}, 0, false
+		}
+		committed := newComponent(component.Name, component.Unit, component.SKU, component.MonthlyQuantity, rate.Rate)
+		committed.OfferTermCode = rate.RateCode
+		return committed, rate.UpfrontShare() * rate.Rate * instances * rate.LeaseHours(), true
+	}
+
+	price, ok := priceList.FindReservedPrice(component.SKU, attributes)
+	if !ok {
+		return CostComponent{}, 0, false
+	}
+	committed := newComponent(component.Name, component.Unit, component.SKU, component.MonthlyQuantity, price.EffectiveHourlyRate())
+	committed.OfferTermCode = price.OfferTermCode
+	return committed, price.UpfrontFee * instances, true
+}
@@ -0,0 +1,39 @@
+package estimator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPricingModelValidate(t *testing.T) {
+	t.Run("accepts on-demand, reserved and Savings Plan models", func(t *testing.T) {
+		assert.NoError(t, PricingModel{}.Validate())
+		assert.NoError(t, PricingModel{Term: PricingTermOnDemand}.Validate())
+		assert.NoError(t, PricingModel{Term: PricingTermReserved}.Validate())
+		assert.NoError(t, PricingModel{Term: PricingTermReserved, LeaseContractLength: "3yr", PurchaseOption: "partial upfront", OfferingClass: "convertible"}.Validate())
+		assert.NoError(t, PricingModel{Term: PricingTermSavingsPlan}.Validate())
+		assert.NoError(t, PricingModel{Term: PricingTermSavingsPlan, LeaseContractLength: "3yr", PurchaseOption: "All Upfront", SavingsPlanType: "EC2_Instance"}.Validate())
+	})
+
+	t.Run("rejects invalid models", func(t *testing.T) {
+		assert.EqualError(t, PricingModel{Term: "spot"}.Validate(), `unknown pricing term "spot"`)
+		assert.EqualError(t, PricingModel{LeaseContractLength: "1yr"}.Validate(), `commitment options require term "reserved" or "savings_plan"`)
+		assert.EqualError(t, PricingModel{SavingsPlanType: "compute"}.Validate(), `commitment options require term "reserved" or "savings_plan"`)
+		assert.EqualError(t, PricingModel{Term: PricingTermReserved, SavingsPlanType: "compute"}.Validate(), `savings plan type requires term "savings_plan"`)
+		assert.EqualError(t, PricingModel{Term: PricingTermSavingsPlan, OfferingClass: "standard"}.Validate(), `offering class requires term "reserved"`)
+		assert.EqualError(t, PricingModel{Term: PricingTermSavingsPlan, SavingsPlanType: "sagemaker"}.Validate(), `unknown savings plan type "sagemaker"`)
+		assert.EqualError(t, PricingModel{Term: PricingTermSavingsPlan, LeaseContractLength: "2yr"}.Validate(), `unknown lease contract length "2yr"`)
+		assert.EqualError(t, PricingModel{Term: PricingTermReserved, LeaseContractLength: "2yr"}.Validate(), `unknown lease contract length "2yr"`)
+		assert.EqualError(t, PricingModel{Term: PricingTermReserved, PurchaseOption: "Monthly"}.Validate(), `unknown purchase option "Monthly"`)
+		assert.EqualError(t, PricingModel{Term: PricingTermReserved, OfferingClass: "flexible"}.Validate(), `unknown offering class "flexible"`)
+	})
+}
+
+func TestPricingModelString(t *testing.T) {
+	assert.Equal(t, "on_demand", PricingModel{}.String())
+	assert.Equal(t, "1yr No Upfront standard reserved", PricingModel{Term: PricingTermReserved}.String())
+	assert.Equal(t, "3yr All Upfront convertible reserved", PricingModel{Term: PricingTermReserved, LeaseContractLength: "3yr", PurchaseOption: "All Upfront", OfferingClass: "convertible"}.String())
+	assert.Equal(t, "1yr No Upfront Compute Savings Plan", PricingModel{Term: PricingTermSavingsPlan}.String())
+	assert.Equal(t, "3yr Partial Upfront EC2 Instance Savings Plan", PricingModel{Term: PricingTermSavingsPlan, LeaseContractLength: "3yr", PurchaseOption: "Partial Upfront", SavingsPlanType: SavingsPlanTypeEC2Instance}.String())
+}
@@ -0,0 +1,276 @@
+package estimator
+
+import (
+	"fmt"
+	"strings"
+
+	"cloudcostguard/backend/pricing"
+)
+
+func init() {
+	MustRegister(NewCalculator("aws_db_instance", []string{"AmazonRDS"}, []string{"instance_class"}, costForRDS))
+}
+
+// rdsEngine identifies the database engine, edition and license model an RDS instance is priced for.
+type rdsEngine struct {
+	// DatabaseEngine is the database engine in the price list (e.g., "PostgreSQL").
+	DatabaseEngine string
+	// DatabaseEdition is the edition in the price list (e.g., "Standard Two"), or empty for open source engines.
+	DatabaseEdition string
+	// LicenseModel is the license model in the price list, or empty for open source engines.
+	LicenseModel string
+}
+
+const (
+	rdsLicenseIncluded = "License included"
+	rdsLicenseBYOL     = "Bring your own license"
+)
+
+// rdsEngines maps the engine attribute of an RDS instance to the database engine in the price list.
+var rdsEngines = map[string]rdsEngine{
+	"mysql":             {DatabaseEngine: "MySQL"},
+	"postgres":          {DatabaseEngine: "PostgreSQL"},
+	"mariadb":           {DatabaseEngine: "MariaDB"},
+	"aurora":            {DatabaseEngine: "Aurora MySQL"},
+	"aurora-mysql":      {DatabaseEngine: "Aurora MySQL"},
+	"aurora-postgresql": {DatabaseEngine: "Aurora PostgreSQL"},
+	"oracle-ee":         {DatabaseEngine: "Oracle", DatabaseEdition: "Enterprise", LicenseModel: rdsLicenseBYOL},
+	"oracle-ee-cdb":     {DatabaseEngine: "Oracle", DatabaseEdition: "Enterprise", LicenseModel: rdsLicenseBYOL},
+	"oracle-se2":        {DatabaseEngine: "Oracle", DatabaseEdition: "Standard Two", LicenseModel: rdsLicenseIncluded},
+	"oracle-se2-cdb":    {DatabaseEngine: "Oracle", DatabaseEdition: "Standard Two", LicenseModel: rdsLicenseIncluded},
+	"sqlserver-ex":      {DatabaseEngine: "SQL Server", DatabaseEdition: "Express", LicenseModel: rdsLicenseIncluded},
+	"sqlserver-web":     {DatabaseEngine: "SQL Server", DatabaseEdition: "Web", LicenseModel: rdsLicenseIncluded},
+	"sqlserver-se":      {DatabaseEngine: "SQL Server", DatabaseEdition: "Standard", LicenseModel: rdsLicenseIncluded},
+	"sqlserver-ee":      {DatabaseEngine: "SQL Server", DatabaseEdition: "Enterprise", LicenseModel: rdsLicenseIncluded},
+}
+
+// rdsLicenseModels maps the license_model attribute of an RDS instance to the license model in the price list.
+var rdsLicenseModels = map[string]string{
+	"license-included":       rdsLicenseIncluded,
+	"bring-your-own-license": rdsLicenseBYOL,
+}
+
+const (
+	// rdsGP3LargeVolumeGB is the size from which RDS gp3 storage includes rdsGP3LargeVolumeIOPS instead of 3,000 IOPS.
+	rdsGP3LargeVolumeGB = 400
+	// rdsGP3OracleLargeVolumeGB is the size from which RDS gp3 storage of Oracle instances includes
+	// rdsGP3LargeVolumeIOPS.
+	rdsGP3OracleLargeVolumeGB = 200
+	// rdsGP3LargeVolumeIOPS is the number of IOPS included in the price of large RDS gp3 volumes.
+	rdsGP3LargeVolumeIOPS = 12000
+)
+
+// rdsGP3BaselineIOPS returns the number of IOPS included in the price of RDS gp3 storage. Large volumes include
+// 12,000 IOPS instead of 3,000, from 200 GB for Oracle and from 400 GB for the other engines except SQL Server,
+// whose volumes include 3,000 IOPS at every size.
+//
+// Parameters:
+//   engine: The engine of the instance.
+//   allocatedStorage: The allocated storage in GB.
+//
+// Returns:
+//   The number of IOPS included in the storage price.
+func rdsGP3BaselineIOPS(engine rdsEngine, allocatedStorage float64) float64 {
+	switch {
+	case engine.DatabaseEngine == "SQL Server":
+		return gp3BaselineIOPS
+	case engine.DatabaseEngine == "Oracle" && allocatedStorage >= rdsGP3OracleLargeVolumeGB:
+		return rdsGP3LargeVolumeIOPS
+	case engine.DatabaseEngine != "Oracle" && allocatedStorage >= rdsGP3LargeVolumeGB:
+		return rdsGP3LargeVolumeIOPS
+	}
+	return gp3BaselineIOPS
+}
+
+// rdsBackupUsage is the usage type of RDS backup storage beyond the free allocation, without a region prefix.
+const rdsBackupUsage = "RDS:ChargedBackupUsage"
+
+// rdsVolumeTypes maps the storage_type attribute of an RDS instance to the volume type in the price list.
+var rdsVolumeTypes = map[string]string{
+	"standard": "Magnetic",
+	"gp2":      "General Purpose",
+	"gp3":      "General Purpose-GP3",
+	"io1":      "Provisioned IOPS",
+	"io2":      "Provisioned IOPS-IO2",
+}
+
+// costForRDS calculates the cost of an AWS RDS instance.
+// The instance is priced for its engine, edition, license model and deployment option (Single-AZ or Multi-AZ).
+// Its allocated storage and provisioned IOPS are priced as separate components, and so is the backup storage in the
+// rds.backup_storage_gb usage estimate beyond the free allocation, which equals the allocated storage.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   attributes: The attributes of the RDS instance resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the RDS instance.
+//   An error if the engine, license model or storage type is unknown or the pricing data cannot be found.
+func costForRDS(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	instanceClass, _ := attributes["instance_class"].(string)
+	if instanceClass == "" {
+		return nil, missingAttributeError("instance_class")
+	}
+
+	engine, err := resolveRDSEngine(attributes)
+	if err != nil {
+		return nil, err
+	}
+	deploymentOption := "Single-AZ"
+	if multiAZ, _ := attributes["multi_az"].(bool); multiAZ {
+		deploymentOption = "Multi-AZ"
+	}
+
+	candidates := ctx.PriceList.Index().Lookup("AmazonRDS", ctx.Location, pricing.AttrInstanceClass, instanceClass)
+	if len(candidates) == 0 {
+		candidates = ctx.PriceList.Index().Lookup("AmazonRDS", ctx.Location, pricing.AttrInstanceType, instanceClass)
+	}
+	// Products missing an attribute are accepted, so that price lists without it still match.
+	candidates = filterSKUs(ctx.PriceList, candidates, func(attr pricing.ProductAttributes) bool {
+		return (engine.DatabaseEngine == "" || matchesOptional(attr.DatabaseEngine, engine.DatabaseEngine)) &&
+			(engine.DatabaseEdition == "" || matchesOptional(attr.DatabaseEdition, engine.DatabaseEdition)) &&
+			(engine.LicenseModel == "" || matchesOptional(attr.LicenseModel, engine.LicenseModel)) &&
+			matchesOptional(attr.DeploymentOption, deploymentOption)
+	})
+	description := engine.describe(instanceClass, deploymentOption)
+	sku, price, err := ctx.MatchSKU("RDS instance class: "+description, candidates)
+	if err != nil {
+		return nil, err
+	}
+	components := []CostComponent{hourlyComponent(fmt.Sprintf("Database instance usage (%s)", description), sku, 1, price)}
+
+	storage, err := rdsStorageComponents(ctx, attributes, engine, deploymentOption)
+	if err != nil {
+		return nil, err
+	}
+	components = append(components, storage...)
+
+	return newCost(components...), nil
+}
+
+// resolveRDSEngine determines the database engine, edition and license model of an RDS instance.
+//
+// Parameters:
+//   attributes: The attributes of the RDS instance resource.
+//
+// Returns:
+//   The engine of the instance. It is empty if the instance has no engine attribute, which matches every engine.
+//   An error if the engine or license model is unknown.
+func resolveRDSEngine(attributes map[string]interface{}) (rdsEngine, error) {
+	name, _ := attributes["engine"].(string)
+	if name == "" {
+		return rdsEngine{}, nil
+	}
+	engine, ok := rdsEngines[strings.ToLower(name)]
+	if !ok {
+		return rdsEngine{}, fmt.Errorf("%w: unknown RDS engine %q", ErrMissingAttribute, name)
+	}
+
+	// The license model of open source engines does not change their price.
+	if licenseModel, _ := attributes["license_model"].(string); licenseModel != "" && engine.LicenseModel != "" {
+		license, ok := rdsLicenseModels[licenseModel]
+		if !ok {
+			return rdsEngine{}, fmt.Errorf("%w: unknown RDS license model %q", ErrMissingAttribute, licenseModel)
+		}
+		engine.LicenseModel = license
+	}
+	return engine, nil
+}
+
+// rdsStorageComponents prices the allocated storage, provisioned IOPS and backup storage of an RDS instance.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   attributes: The attributes of the RDS instance resource.
+//   engine: The engine of the instance.
+//   deploymentOption: The deployment option of the instance ("Single-AZ" or "Multi-AZ").
+//
+// Returns:
+//   The cost components of the storage, or none if the instance has no allocated storage.
+//   An error if the storage type is unknown or the pricing data cannot be found.
+func rdsStorageComponents(ctx *CalculationContext, attributes map[string]interface{}, engine rdsEngine, deploymentOption string) ([]CostComponent, error) {
+	allocatedStorage, _ := attributes["allocated_storage"].(float64)
+	if allocatedStorage == 0 {
+		return nil, nil
+	}
+	iops, _ := attributes["iops"].(float64)
+	storageType, _ := attributes["storage_type"].(string)
+	if storageType == "" {
+		storageType = "gp2"
+		if iops > 0 {
+			storageType = "io1"
+		}
+	}
+	volumeType, ok := rdsVolumeTypes[storageType]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown RDS storage type %q", ErrMissingAttribute, storageType)
+	}
+
+	// Storage and IOPS products are looked up by volume type, and told apart by their usage type.
+	storageProducts := func(usageType string) []string {
+		return filterSKUs(ctx.PriceList, ctx.PriceList.Index().Lookup("AmazonRDS", ctx.Location, pricing.AttrVolumeType, volumeType), func(attr pricing.ProductAttributes) bool {
+			return strings.Contains(attr.UsageType, usageType) &&
+				(attr.DatabaseEngine == "" || attr.DatabaseEngine == "Any" || engine.DatabaseEngine == "" || strings.EqualFold(attr.DatabaseEngine, engine.DatabaseEngine)) &&
+				matchesOptional(attr.DeploymentOption, deploymentOption)
+		})
+	}
+
+	storageSKUs := storageProducts("Storage")
+	sku, price, err := ctx.MatchSKU(fmt.Sprintf("RDS storage: %s (%s)", volumeType, deploymentOption), storageSKUs)
+	if err != nil {
+		return nil, err
+	}
+	components := []CostComponent{newComponent(fmt.Sprintf("Storage (%s)", storageType), "GB-month", sku, allocatedStorage, price)}
+
+	// gp3 storage includes a baseline of IOPS that depends on the engine and the allocated storage.
+	chargedIOPS := iops
+	if storageType == "gp3" {
+		chargedIOPS -= rdsGP3BaselineIOPS(engine, allocatedStorage)
+	}
+	if chargedIOPS > 0 && (storageType == "io1" || storageType == "io2" || storageType == "gp3") {
+		iopsSKUs := filterSKUs(ctx.PriceList, storageProducts("IOPS"), func(attr pricing.ProductAttributes) bool {
+			return !strings.Contains(attr.UsageType, "Storage")
+		})
+		sku, price, err := ctx.MatchSKU(fmt.Sprintf("RDS provisioned IOPS: %s (%s)", volumeType, deploymentOption), iopsSKUs)
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, newComponent(fmt.Sprintf("Provisioned IOPS (%s)", storageType), "IOPS-month", sku, chargedIOPS, price))
+	}
+
+	// Backup storage up to the allocated storage is free, and instances without a retention period keep no backups.
+	retention, hasRetention := attributes["backup_retention_period"].(float64)
+	if ctx.Usage != nil && ctx.Usage.RDS.BackupStorageGB > 0 && (!hasRetention || retention > 0) {
+		if chargedBackup := float64(ctx.Usage.RDS.BackupStorageGB) - allocatedStorage; chargedBackup > 0 {
+			backupSKUs := filterSKUs(ctx.PriceList, usageTypeSKUs(ctx, "AmazonRDS", ctx.Location, rdsBackupUsage), func(attr pricing.ProductAttributes) bool {
+				return attr.DatabaseEngine == "" || attr.DatabaseEngine == "Any" || engine.DatabaseEngine == "" || strings.EqualFold(attr.DatabaseEngine, engine.DatabaseEngine)
+			})
+			sku, price, err := ctx.MatchSKU("RDS backup storage", backupSKUs)
+			if err != nil {
+				return nil, err
+			}
+			components = append(components, newComponent("Backup storage (beyond free allocation)", "GB-month", sku, chargedBackup, price))
+		}
+	}
+	return components, nil
+}
+
+// describe renders the instance class, engine and deployment option of an RDS instance for component names and
+// error messages, e.g. "db.m5.large, Oracle Standard Two, Multi-AZ".
+func (e rdsEngine) describe(instanceClass, deploymentOption string) string {
+	parts := []string{instanceClass}
+	if e.DatabaseEngine != "" {
+		engine := e.DatabaseEngine
+		if e.DatabaseEdition != "" {
+			engine += " " + e.DatabaseEdition
+		}
+		if e.LicenseModel == rdsLicenseBYOL {
+			engine += " BYOL"
+		}
+		parts = append(parts, engine)
+	}
+	if deploymentOption != "Single-AZ" {
+		parts = append(parts, deploymentOption)
+	}
+	return strings.Join(parts, ", ")
+}
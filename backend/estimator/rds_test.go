@@ -0,0 +1,203 @@
+package estimator
+
+import (
+	"testing"
+
+	"cloudcostguard/backend/pricing"
+	"cloudcostguard/backend/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+// createRDSPriceList creates a price list with RDS instance, storage, IOPS and backup prices.
+func createRDSPriceList() *pricing.PriceList {
+	usEast := "US East (N. Virginia)"
+	priceList := pricing.NewPriceList()
+	instance := func(sku, engine, edition, license, deployment, price string) {
+		addMockProduct(priceList, sku, pricing.ProductAttributes{
+			ServiceCode:      "AmazonRDS",
+			Location:         usEast,
+			InstanceClass:    "db.m5.large",
+			DatabaseEngine:   engine,
+			DatabaseEdition:  edition,
+			LicenseModel:     license,
+			DeploymentOption: deployment,
+		}, price)
+	}
+	instance("mysql", "MySQL", "", "No license required", "Single-AZ", "0.171")
+	instance("mysql-multi-az", "MySQL", "", "No license required", "Multi-AZ", "0.342")
+	instance("postgres", "PostgreSQL", "", "No license required", "Single-AZ", "0.178")
+	instance("oracle-se2", "Oracle", "Standard Two", "License included", "Single-AZ", "0.544")
+	instance("oracle-se2-multi-az", "Oracle", "Standard Two", "License included", "Multi-AZ", "1.088")
+	instance("oracle-se2-byol", "Oracle", "Standard Two", "Bring your own license", "Multi-AZ", "0.342")
+	instance("sqlserver-ex", "SQL Server", "Express", "License included", "Single-AZ", "0.254")
+
+	storage := func(sku, usageType, volumeType, deployment, price string) {
+		addMockProduct(priceList, sku, pricing.ProductAttributes{
+			ServiceCode:      "AmazonRDS",
+			Location:         usEast,
+			UsageType:        usageType,
+			VolumeType:       volumeType,
+			DatabaseEngine:   "Any",
+			DeploymentOption: deployment,
+		}, price)
+	}
+	storage("gp2", "RDS:GP2-Storage", "General Purpose", "Single-AZ", "0.115")
+	storage("gp3", "RDS:GP3-Storage", "General Purpose-GP3", "Single-AZ", "0.115")
+	storage("gp3-iops", "RDS:GP3-PIOPS", "General Purpose-GP3", "Single-AZ", "0.02")
+	storage("io1", "RDS:PIOPS-Storage", "Provisioned IOPS", "Single-AZ", "0.125")
+	storage("io1-iops", "RDS:PIOPS", "Provisioned IOPS", "Single-AZ", "0.10")
+	storage("io1-multi-az", "RDS:Multi-AZ-PIOPS-Storage", "Provisioned IOPS", "Multi-AZ", "0.25")
+	storage("io1-iops-multi-az", "RDS:Multi-AZ-PIOPS", "Provisioned IOPS", "Multi-AZ", "0.20")
+	addMockProduct(priceList, "backup", pricing.ProductAttributes{
+		ServiceCode: "AmazonRDS",
+		Location:    usEast,
+		UsageType:   "RDS:ChargedBackupUsage",
+	}, "0.095")
+	return priceList
+}
+
+func TestRDSPricing(t *testing.T) {
+	priceList := createRDSPriceList()
+	estimate := func(t *testing.T, after map[string]interface{}, usage *UsageEstimates) *EstimationResponse {
+		plan := &terraform.Plan{ResourceChanges: []*terraform.ResourceChange{{
+			Address: "aws_db_instance.test",
+			Type:    "aws_db_instance",
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   after,
+		}}}
+		result, err := Estimate(plan, priceList, "us-east-1", usage)
+		assert.NoError(t, err)
+		return result
+	}
+	componentCosts := func(result *EstimationResponse) map[string]float64 {
+		costs := make(map[string]float64)
+		for _, component := range result.Resources[0].Components {
+			costs[component.Name] = component.MonthlyCost
+		}
+		return costs
+	}
+
+	t.Run("prices the instance for its engine", func(t *testing.T) {
+		skus := map[string]string{"mysql": "mysql", "postgres": "postgres", "oracle-se2": "oracle-se2", "sqlserver-ex": "sqlserver-ex"}
+		for engine, sku := range skus {
+			result := estimate(t, map[string]interface{}{"instance_class": "db.m5.large", "engine": engine}, &UsageEstimates{})
+			if assert.Len(t, result.Resources, 1, engine) {
+				assert.Equal(t, sku, result.Resources[0].Components[0].SKU, engine)
+				assert.Empty(t, result.Warnings, engine)
+			}
+		}
+	})
+
+	t.Run("prices Multi-AZ deployments and the license model", func(t *testing.T) {
+		result := estimate(t, map[string]interface{}{"instance_class": "db.m5.large", "engine": "oracle-se2", "multi_az": true}, &UsageEstimates{})
+		assert.Equal(t, map[string]float64{"Database instance usage (db.m5.large, Oracle Standard Two, Multi-AZ)": 1.088 * 730}, componentCosts(result))
+
+		result = estimate(t, map[string]interface{}{
+			"instance_class": "db.m5.large",
+			"engine":         "oracle-se2",
+			"multi_az":       true,
+			"license_model":  "bring-your-own-license",
+		}, &UsageEstimates{})
+		assert.Equal(t, "oracle-se2-byol", result.Resources[0].Components[0].SKU)
+	})
+
+	t.Run("prices storage and provisioned IOPS", func(t *testing.T) {
+		result := estimate(t, map[string]interface{}{
+			"instance_class":    "db.m5.large",
+			"engine":            "mysql",
+			"multi_az":          true,
+			"allocated_storage": float64(2000),
+			"storage_type":      "io1",
+			"iops":              float64(10000),
+		}, &UsageEstimates{})
+		costs := componentCosts(result)
+		assert.InDelta(t, 0.25*2000, costs["Storage (io1)"], 0.001)
+		assert.InDelta(t, 0.20*10000, costs["Provisioned IOPS (io1)"], 0.001)
+		assert.InDelta(t, 0.342*730+0.25*2000+0.20*10000, result.TotalMonthlyCost, 0.001)
+	})
+
+	t.Run("defaults to gp2 storage, or io1 when IOPS are provisioned", func(t *testing.T) {
+		result := estimate(t, map[string]interface{}{"instance_class": "db.m5.large", "engine": "mysql", "allocated_storage": float64(100)}, &UsageEstimates{})
+		assert.InDelta(t, 0.115*100, componentCosts(result)["Storage (gp2)"], 0.001)
+
+		result = estimate(t, map[string]interface{}{
+			"instance_class":    "db.m5.large",
+			"engine":            "mysql",
+			"allocated_storage": float64(100),
+			"iops":              float64(1000),
+		}, &UsageEstimates{})
+		costs := componentCosts(result)
+		assert.InDelta(t, 0.125*100, costs["Storage (io1)"], 0.001)
+		assert.InDelta(t, 0.10*1000, costs["Provisioned IOPS (io1)"], 0.001)
+	})
+
+	t.Run("charges gp3 IOPS above the baseline for the storage size", func(t *testing.T) {
+		small := estimate(t, map[string]interface{}{
+			"instance_class":    "db.m5.large",
+			"engine":            "postgres",
+			"allocated_storage": float64(100),
+			"storage_type":      "gp3",
+			"iops":              float64(5000),
+		}, &UsageEstimates{})
+		assert.InDelta(t, 0.02*2000, componentCosts(small)["Provisioned IOPS (gp3)"], 0.001)
+
+		large := estimate(t, map[string]interface{}{
+			"instance_class":    "db.m5.large",
+			"engine":            "postgres",
+			"allocated_storage": float64(500),
+			"storage_type":      "gp3",
+			"iops":              float64(12000),
+		}, &UsageEstimates{})
+		assert.NotContains(t, componentCosts(large), "Provisioned IOPS (gp3)")
+	})
+
+	t.Run("uses the gp3 IOPS baseline of the engine", func(t *testing.T) {
+		sqlServer := estimate(t, map[string]interface{}{
+			"instance_class":    "db.m5.large",
+			"engine":            "sqlserver-ex",
+			"allocated_storage": float64(500),
+			"storage_type":      "gp3",
+			"iops":              float64(12000),
+		}, &UsageEstimates{})
+		assert.InDelta(t, 0.02*9000, componentCosts(sqlServer)["Provisioned IOPS (gp3)"], 0.001)
+
+		oracle := estimate(t, map[string]interface{}{
+			"instance_class":    "db.m5.large",
+			"engine":            "oracle-se2",
+			"allocated_storage": float64(200),
+			"storage_type":      "gp3",
+			"iops":              float64(12000),
+		}, &UsageEstimates{})
+		assert.NotContains(t, componentCosts(oracle), "Provisioned IOPS (gp3)")
+	})
+
+	t.Run("charges backup storage beyond the allocated storage", func(t *testing.T) {
+		after := map[string]interface{}{
+			"instance_class":          "db.m5.large",
+			"engine":                  "mysql",
+			"allocated_storage":       float64(100),
+			"backup_retention_period": float64(7),
+		}
+		result := estimate(t, after, &UsageEstimates{RDS: RDSUsage{BackupStorageGB: 250}})
+		assert.InDelta(t, 0.095*150, componentCosts(result)["Backup storage (beyond free allocation)"], 0.001)
+
+		after["backup_retention_period"] = float64(0)
+		result = estimate(t, after, &UsageEstimates{RDS: RDSUsage{BackupStorageGB: 250}})
+		assert.NotContains(t, componentCosts(result), "Backup storage (beyond free allocation)")
+	})
+
+	t.Run("skips instances with an unknown engine or storage type", func(t *testing.T) {
+		result := estimate(t, map[string]interface{}{"instance_class": "db.m5.large", "engine": "db2-se"}, &UsageEstimates{})
+		assert.Empty(t, result.Resources)
+		assert.Equal(t, SkipReasonMissingAttribute, result.Skipped[0].Reason)
+
+		result = estimate(t, map[string]interface{}{
+			"instance_class":    "db.m5.large",
+			"engine":            "mysql",
+			"allocated_storage": float64(100),
+			"storage_type":      "gp4",
+		}, &UsageEstimates{})
+		assert.Empty(t, result.Resources)
+		assert.Equal(t, SkipReasonMissingAttribute, result.Skipped[0].Reason)
+	})
+}
@@ -7,6 +7,7 @@ import (
 )
 
 // GenerateRecommendations analyzes a Terraform plan and suggests cost-saving optimizations.
+// Usage-based checks use the usage estimates that apply to each resource.
 func GenerateRecommendations(plan *terraform.Plan, usage *UsageEstimates) []string {
 	if plan == nil {
 		return nil
@@ -18,7 +19,7 @@ func GenerateRecommendations(plan *terraform.Plan, usage *UsageEstimates) []stri
 
 		switch rc.Type {
 		case "aws_nat_gateway":
-			recommendations = append(recommendations, checkNATGateway(rc, usage)...)
+			recommendations = append(recommendations, checkNATGateway(rc, usage.For(rc.Address))...)
 		case "aws_db_instance":
 			recommendations = append(recommendations, checkDBInstance(rc)...)
 		case "aws_instance":
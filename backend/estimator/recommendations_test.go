@@ -65,3 +65,30 @@ func TestGenerateRecommendations(t *testing.T) {
 	assert.Contains(t, recommendations, "💡 Consider switching to ARM-based Graviton EC2 instances for better price-performance.")
 	assert.Contains(t, recommendations, "💡 Enable EBS snapshot lifecycle policy to manage backup costs")
 }
+
+func TestGenerateRecommendationsPerResourceUsage(t *testing.T) {
+	plan := &terraform.Plan{
+		ResourceChanges: []*terraform.ResourceChange{
+			{
+				Address: "aws_nat_gateway.busy",
+				Type:    "aws_nat_gateway",
+				Change:  terraform.Change{Actions: []string{"create"}},
+			},
+			{
+				Address: "aws_nat_gateway.quiet",
+				Type:    "aws_nat_gateway",
+				Change:  terraform.Change{Actions: []string{"create"}},
+			},
+		},
+	}
+	usage := &UsageEstimates{
+		NATGatewayGBProcessed: 10,
+		Resources: map[string]UsageEstimates{
+			"aws_nat_gateway.busy": {NATGatewayGBProcessed: 5000},
+		},
+	}
+
+	recommendations := GenerateRecommendations(plan, usage)
+
+	assert.Equal(t, []string{"💡 NAT Gateway data transfer is expensive - consider VPC endpoints for AWS services"}, recommendations)
+}
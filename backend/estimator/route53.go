@@ -0,0 +1,198 @@
+package estimator
+
+import (
+	"fmt"
+	"strings"
+
+	"cloudcostguard/backend/pricing"
+)
+
+func init() {
+	MustRegister(NewCalculator("aws_route53_zone", []string{"AmazonRoute53"}, nil, costForRoute53Zone))
+	MustRegister(NewCalculator("aws_route53_record", []string{"AmazonRoute53"}, nil, costForRoute53Record))
+	MustRegister(NewCalculator("aws_route53_health_check", []string{"AmazonRoute53"}, nil, costForRoute53HealthCheck))
+}
+
+// route53Location is the pricing location of Route 53, which is a global service.
+const route53Location = "Global"
+
+// Usage types of Route 53.
+const (
+	route53HostedZone              = "HostedZone"
+	route53StandardQueries         = "DNS-Queries"
+	route53LatencyQueries          = "LBR-Queries"
+	route53GeoQueries              = "Geo-Queries"
+	route53GeoproximityQueries     = "Geoproximity-Queries"
+	route53IPBasedQueries          = "CIDR-Queries"
+	route53HealthCheckAWS          = "Health-Check-AWS"
+	route53HealthCheckNonAWS       = "Health-Check-Non-AWS"
+	route53HealthCheckOptionAWS    = "Health-Check-Option-AWS"
+	route53HealthCheckOptionNonAWS = "Health-Check-Option-Non-AWS"
+)
+
+// route53RoutingPolicies maps the routing policy blocks of a Route 53 record to the usage type of its queries and the
+// name of their line item.
+var route53RoutingPolicies = []struct {
+	Block     string
+	UsageType string
+	Name      string
+}{
+	{"latency_routing_policy", route53LatencyQueries, "Latency queries"},
+	{"geolocation_routing_policy", route53GeoQueries, "Geolocation queries"},
+	{"geoproximity_routing_policy", route53GeoproximityQueries, "Geoproximity queries"},
+	{"cidr_routing_policy", route53IPBasedQueries, "IP-based queries"},
+}
+
+// costForRoute53Zone calculates the cost of an AWS Route 53 hosted zone.
+// Hosted zones are charged a monthly fee. Public hosted zones are also charged for the standard queries in the
+// route53.monthly_queries usage estimate; queries to private hosted zones are not charged.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its usage estimates may include the queries of the zone.
+//   attributes: The attributes of the Route 53 hosted zone resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the hosted zone.
+//   An error if the pricing data cannot be found.
+func costForRoute53Zone(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	fee, err := route53Fee(ctx, "Hosted zone", route53HostedZone)
+	if err != nil {
+		return nil, err
+	}
+	components := []CostComponent{fee}
+	if private := len(blocks(attributes, "vpc")) > 0; !private && ctx.Usage != nil {
+		queries, err := route53Queries(ctx, "Standard queries", route53StandardQueries, float64(ctx.Usage.Route53.MonthlyQueries))
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, queries...)
+	}
+	return newCost(components...), nil
+}
+
+// costForRoute53Record calculates the cost of an AWS Route 53 record.
+// Records with a latency, geolocation, geoproximity or IP-based routing policy are charged for the queries in the
+// route53.record_monthly_queries usage estimate at the rate of their policy. Queries to other records are charged as
+// part of their hosted zone, and queries to alias records that route to AWS resources are free.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its usage estimates may include the queries of the record.
+//   attributes: The attributes of the Route 53 record resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the record.
+//   An error if the pricing data cannot be found.
+func costForRoute53Record(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	if ctx.Usage == nil || ctx.Usage.Route53.RecordMonthlyQueries == 0 || len(blocks(attributes, "alias")) > 0 {
+		return newCost(), nil
+	}
+	for _, policy := range route53RoutingPolicies {
+		if len(blocks(attributes, policy.Block)) == 0 {
+			continue
+		}
+		components, err := route53Queries(ctx, policy.Name, policy.UsageType, float64(ctx.Usage.Route53.RecordMonthlyQueries))
+		if err != nil {
+			return nil, err
+		}
+		return newCost(components...), nil
+	}
+	return newCost(), nil
+}
+
+// costForRoute53HealthCheck calculates the cost of an AWS Route 53 health check.
+// Health checks are charged a monthly fee, plus a fee for each optional feature they use: HTTPS, string matching, a
+// fast (10 second) request interval and latency measurement. Health checks of endpoints outside AWS cost more; an
+// endpoint is assumed to be outside AWS unless its fqdn is an amazonaws.com domain, and calculated and CloudWatch
+// metric health checks are charged at the AWS rate.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   attributes: The attributes of the Route 53 health check resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the health check.
+//   An error if the pricing data cannot be found.
+func costForRoute53HealthCheck(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	checkType, _ := attributes["type"].(string)
+	fqdn, _ := attributes["fqdn"].(string)
+	awsEndpoint := checkType == "CALCULATED" || checkType == "CLOUDWATCH_METRIC" || checkType == "RECOVERY_CONTROL" ||
+		strings.HasSuffix(strings.TrimSuffix(fqdn, "."), ".amazonaws.com")
+
+	var options []string
+	if strings.HasPrefix(checkType, "HTTPS") {
+		options = append(options, "HTTPS")
+	}
+	if strings.HasSuffix(checkType, "_STR_MATCH") {
+		options = append(options, "string matching")
+	}
+	if interval, _ := attributes["request_interval"].(float64); interval == 10 {
+		options = append(options, "fast interval")
+	}
+	if measureLatency, _ := attributes["measure_latency"].(bool); measureLatency {
+		options = append(options, "latency measurement")
+	}
+
+	name, usageType, optionUsageType := "Health check (non-AWS endpoint)", route53HealthCheckNonAWS, route53HealthCheckOptionNonAWS
+	if awsEndpoint {
+		name, usageType, optionUsageType = "Health check (AWS endpoint)", route53HealthCheckAWS, route53HealthCheckOptionAWS
+	}
+	fee, err := route53Fee(ctx, name, usageType)
+	if err != nil {
+		return nil, err
+	}
+	components := []CostComponent{fee}
+	for _, option := range options {
+		optional, err := route53Fee(ctx, fmt.Sprintf("Optional feature (%s)", option), optionUsageType)
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, optional)
+	}
+	return newCost(components...), nil
+}
+
+// route53Fee prices the monthly fee of a Route 53 hosted zone or health check. AWS tiers hosted zone fees by the
+// number of zones in an account, so each zone is charged the first tier.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   name: The name of the line item (e.g., "Hosted zone").
+//   usageType: The usage type (e.g., "HostedZone").
+//
+// Returns:
+//   The cost component of the fee.
+//   An error if the pricing data cannot be found.
+func route53Fee(ctx *CalculationContext, name, usageType string) (CostComponent, error) {
+	sku, price, err := ctx.MatchSKU("Route 53 "+usageType, route53Products(ctx, usageType))
+	if err != nil {
+		return CostComponent{}, err
+	}
+	return newComponent(name, "month", sku, 1, price), nil
+}
+
+// route53Queries prices a monthly number of Route 53 DNS queries with their price tiers.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   name: The name of the line item (e.g., "Standard queries").
+//   usageType: The usage type (e.g., "DNS-Queries").
+//   monthlyQueries: The number of queries per month.
+//
+// Returns:
+//   The cost components, or none if there are no queries.
+//   An error if the pricing data cannot be found.
+func route53Queries(ctx *CalculationContext, name, usageType string, monthlyQueries float64) ([]CostComponent, error) {
+	if monthlyQueries <= 0 {
+		return nil, nil
+	}
+	sku, _, err := ctx.MatchSKU("Route 53 "+usageType, route53Products(ctx, usageType))
+	if err != nil {
+		return nil, err
+	}
+	return tieredComponents(ctx, name, "query", sku, monthlyQueries)
+}
+
+// route53Products returns the SKUs of the Route 53 products of a usage type.
+func route53Products(ctx *CalculationContext, usageType string) []string {
+	return ctx.PriceList.Index().Lookup("AmazonRoute53", route53Location, pricing.AttrUsageType, usageType)
+}
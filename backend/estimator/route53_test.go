@@ -0,0 +1,102 @@
+package estimator
+
+import (
+	"testing"
+
+	"cloudcostguard/backend/pricing"
+	"cloudcostguard/backend/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+// createRoute53PriceList creates a price list with Route 53 hosted zone, query and health check prices.
+func createRoute53PriceList() *pricing.PriceList {
+	priceList := pricing.NewPriceList()
+	add := func(sku, usageType string, tiers ...[3]string) {
+		addTieredMockProduct(priceList, sku, pricing.ProductAttributes{ServiceCode: "AmazonRoute53", Location: "Global", UsageType: usageType}, tiers...)
+	}
+	add("zone", "HostedZone", [3]string{"0", "25", "0.50"}, [3]string{"25", "Inf", "0.10"})
+	add("queries", "DNS-Queries", [3]string{"0", "1000000000", "0.0000004"}, [3]string{"1000000000", "Inf", "0.0000002"})
+	add("latency-queries", "LBR-Queries", [3]string{"0", "1000000000", "0.0000006"}, [3]string{"1000000000", "Inf", "0.0000003"})
+	add("health-check-aws", "Health-Check-AWS", [3]string{"0", "Inf", "0.50"})
+	add("health-check-non-aws", "Health-Check-Non-AWS", [3]string{"0", "Inf", "0.75"})
+	add("option-aws", "Health-Check-Option-AWS", [3]string{"0", "Inf", "1.00"})
+	add("option-non-aws", "Health-Check-Option-Non-AWS", [3]string{"0", "Inf", "2.00"})
+	return priceList
+}
+
+func TestRoute53Pricing(t *testing.T) {
+	priceList := createRoute53PriceList()
+	estimate := func(t *testing.T, usage *UsageEstimates, resourceType string, after map[string]interface{}) *EstimationResponse {
+		plan := &terraform.Plan{ResourceChanges: []*terraform.ResourceChange{{
+			Address: resourceType + ".main",
+			Type:    resourceType,
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   after,
+		}}}
+		result, err := Estimate(plan, priceList, "us-east-1", usage)
+		assert.NoError(t, err)
+		return result
+	}
+
+	t.Run("charges hosted zones a monthly fee without usage estimates", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{}, "aws_route53_zone", map[string]interface{}{"name": "example.com"})
+		if assert.Len(t, result.Resources, 1) {
+			assert.Equal(t, "Hosted zone", result.Resources[0].Components[0].Name)
+			assert.InDelta(t, 0.50, result.TotalMonthlyCost, 1e-9)
+		}
+	})
+
+	t.Run("charges public hosted zones for standard queries with tiers", func(t *testing.T) {
+		usage := &UsageEstimates{Route53: Route53Usage{MonthlyQueries: 1500000000}}
+		result := estimate(t, usage, "aws_route53_zone", map[string]interface{}{"name": "example.com"})
+		assert.InDelta(t, 0.50+400+100, result.TotalMonthlyCost, 1e-9)
+
+		result = estimate(t, usage, "aws_route53_zone", map[string]interface{}{
+			"name": "internal.example.com",
+			"vpc":  []interface{}{map[string]interface{}{"vpc_id": "vpc-0123"}},
+		})
+		assert.InDelta(t, 0.50, result.TotalMonthlyCost, 1e-9)
+	})
+
+	t.Run("charges records with a routing policy for their queries", func(t *testing.T) {
+		usage := &UsageEstimates{Route53: Route53Usage{RecordMonthlyQueries: 10000000}}
+		result := estimate(t, usage, "aws_route53_record", map[string]interface{}{
+			"name":                   "api.example.com",
+			"latency_routing_policy": []interface{}{map[string]interface{}{"region": "us-east-1"}},
+		})
+		if assert.Len(t, result.Resources, 1) {
+			assert.Equal(t, "Latency queries (first 1000000000 query)", result.Resources[0].Components[0].Name)
+			assert.InDelta(t, 6.0, result.TotalMonthlyCost, 1e-9)
+		}
+
+		// Queries to alias records and to records without a routing policy are not charged to the record.
+		result = estimate(t, usage, "aws_route53_record", map[string]interface{}{
+			"name":                   "api.example.com",
+			"latency_routing_policy": []interface{}{map[string]interface{}{"region": "us-east-1"}},
+			"alias":                  []interface{}{map[string]interface{}{"name": "lb.us-east-1.elb.amazonaws.com"}},
+		})
+		assert.Zero(t, result.TotalMonthlyCost)
+		result = estimate(t, usage, "aws_route53_record", map[string]interface{}{"name": "www.example.com"})
+		assert.Zero(t, result.TotalMonthlyCost)
+	})
+
+	t.Run("charges health checks and their optional features", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{}, "aws_route53_health_check", map[string]interface{}{
+			"type":             "HTTPS_STR_MATCH",
+			"fqdn":             "example.com",
+			"request_interval": float64(10),
+		})
+		if assert.Len(t, result.Resources, 1) {
+			assert.Len(t, result.Resources[0].Components, 4)
+			assert.Equal(t, "Health check (non-AWS endpoint)", result.Resources[0].Components[0].Name)
+			assert.InDelta(t, 0.75+3*2.00, result.TotalMonthlyCost, 1e-9)
+		}
+
+		result = estimate(t, &UsageEstimates{}, "aws_route53_health_check", map[string]interface{}{
+			"type":            "HTTP",
+			"fqdn":            "web-123.us-east-1.elb.amazonaws.com",
+			"measure_latency": true,
+		})
+		assert.InDelta(t, 0.50+1.00, result.TotalMonthlyCost, 1e-9)
+	})
+}
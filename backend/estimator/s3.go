@@ -0,0 +1,45 @@
+package estimator
+
+import "cloudcostguard/backend/pricing"
+
+func init() {
+	MustRegister(NewCalculator("aws_s3_bucket", []string{"AmazonS3"}, nil, costForS3))
+}
+
+// costForS3 calculates the cost of an AWS S3 bucket.
+// It includes both storage and request costs. Storage is priced with the tiered prices published for S3 Standard.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its usage estimates may include S3 storage and request data.
+//   attributes: The attributes of the S3 bucket resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the S3 bucket.
+//   An error if the pricing data cannot be found.
+func costForS3(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	storageSKU, _, err := ctx.MatchSKU("S3 Standard storage", filterSKUs(ctx.PriceList, usageTypeSKUs(ctx, "AmazonS3", ctx.Location, "TimedStorage-ByteHrs"), func(attr pricing.ProductAttributes) bool {
+		return attr.StorageClass == "General Purpose"
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	putRequestSKU, putRequestPrice, err := ctx.MatchSKU("S3 PUT requests", ctx.PriceList.Index().Lookup("AmazonS3", ctx.Location, pricing.AttrGroup, "S3-Request-Tier1"))
+	if err != nil {
+		return nil, err
+	}
+
+	usage := ctx.Usage
+	if usage == nil {
+		usage = &UsageEstimates{}
+	}
+
+	// Storage is priced in tiers, e.g. the first 50 TB per month, the next 450 TB and everything over 500 TB.
+	components, err := tieredComponents(ctx, "Storage (Standard)", "GB-month", storageSKU, float64(usage.S3StorageGB))
+	if err != nil {
+		return nil, err
+	}
+	components = append(components, newComponent("PUT requests", "1k requests", putRequestSKU, float64(usage.S3MonthlyPutRequests)/1000, putRequestPrice))
+
+	return newCost(components...), nil
+}
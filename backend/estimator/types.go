@@ -2,49 +2,473 @@
 package estimator
 
 import (
+	"time"
+
 	"cloudcostguard/backend/terraform"
 )
 
 // EstimateRequest defines the structure of the request body for the /estimate endpoint.
 type EstimateRequest struct {
 	// Plan is the Terraform plan to estimate.
-	Plan           *terraform.Plan `json:"plan"`
+	Plan *terraform.Plan `json:"plan"`
 	// UsageEstimates contains usage estimates for various resources.
-	UsageEstimates UsageEstimates    `json:"usage_estimates"`
+	UsageEstimates UsageEstimates `json:"usage_estimates"`
+	// PricingModels maps resource types (e.g., "aws_instance") to the pricing model used for them.
+	// Resource types without an entry are priced on demand.
+	PricingModels map[string]PricingModel `json:"pricing_models,omitempty"`
+	// Discounts is the discount policy to apply to list prices. A policy configured on the server for the
+	// organization of the API key takes precedence over it.
+	Discounts *DiscountPolicy `json:"discounts,omitempty"`
 }
 
 // UsageEstimates represents the structure of the usage_estimates block in the config file.
+// The estimates of NAT gateways, Lambda functions and S3 storage keep their original top-level keys; the estimates of
+// other services are grouped under a key per service (e.g., ec2).
 type UsageEstimates struct {
 	// NATGatewayGBProcessed is the estimated GB of data processed by the NAT Gateway per month.
-	NATGatewayGBProcessed int `yaml:"nat_gateway_gb_processed" json:"nat_gateway_gb_processed"`
+	NATGatewayGBProcessed int `yaml:"nat_gateway_gb_processed,omitempty" json:"nat_gateway_gb_processed,omitempty"`
 	// LambdaMonthlyRequests is the estimated number of monthly requests for the Lambda function.
-	LambdaMonthlyRequests int `yaml:"lambda_monthly_requests" json:"lambda_monthly_requests"`
+	LambdaMonthlyRequests int `yaml:"lambda_monthly_requests,omitempty" json:"lambda_monthly_requests,omitempty"`
 	// LambdaAvgDurationMS is the estimated average duration of the Lambda function in milliseconds.
-	LambdaAvgDurationMS   int `yaml:"lambda_avg_duration_ms" json:"lambda_avg_duration_ms"`
+	LambdaAvgDurationMS int `yaml:"lambda_avg_duration_ms,omitempty" json:"lambda_avg_duration_ms,omitempty"`
 	// S3StorageGB is the estimated storage in GB for the S3 bucket.
-	S3StorageGB           int `yaml:"s3_storage_gb" json:"s3_storage_gb"`
+	S3StorageGB int `yaml:"s3_storage_gb,omitempty" json:"s3_storage_gb,omitempty"`
 	// S3MonthlyPutRequests is the estimated number of monthly PUT requests for the S3 bucket.
-	S3MonthlyPutRequests  int `yaml:"s3_monthly_put_requests" json:"s3_monthly_put_requests"`
+	S3MonthlyPutRequests int `yaml:"s3_monthly_put_requests,omitempty" json:"s3_monthly_put_requests,omitempty"`
+	// EC2 holds the usage estimates of EC2 instances and Auto Scaling groups.
+	EC2 EC2Usage `yaml:"ec2,omitempty" json:"ec2,omitempty"`
+	// RDS holds the usage estimates of RDS instances.
+	RDS RDSUsage `yaml:"rds,omitempty" json:"rds,omitempty"`
+	// Aurora holds the usage estimates of Aurora clusters.
+	Aurora AuroraUsage `yaml:"aurora,omitempty" json:"aurora,omitempty"`
+	// ElastiCache holds the usage estimates of ElastiCache serverless caches.
+	ElastiCache ElastiCacheUsage `yaml:"elasticache,omitempty" json:"elasticache,omitempty"`
+	// CloudFront holds the usage estimates of CloudFront distributions.
+	CloudFront CloudFrontUsage `yaml:"cloudfront,omitempty" json:"cloudfront,omitempty"`
+	// Route53 holds the usage estimates of Route 53 hosted zones and records.
+	Route53 Route53Usage `yaml:"route53,omitempty" json:"route53,omitempty"`
+	// VPC holds the usage estimates of VPC endpoints, transit gateway attachments and Network Firewalls.
+	VPC VPCUsage `yaml:"vpc,omitempty" json:"vpc,omitempty"`
+	// S3 holds the usage estimates of S3 replication.
+	S3 S3Usage `yaml:"s3,omitempty" json:"s3,omitempty"`
+	// EFS holds the usage estimates of EFS file systems.
+	EFS EFSUsage `yaml:"efs,omitempty" json:"efs,omitempty"`
+	// FSx holds the usage estimates of FSx file systems.
+	FSx FSxUsage `yaml:"fsx,omitempty" json:"fsx,omitempty"`
+	// SQS holds the usage estimates of SQS queues.
+	SQS SQSUsage `yaml:"sqs,omitempty" json:"sqs,omitempty"`
+	// SNS holds the usage estimates of SNS topics.
+	SNS SNSUsage `yaml:"sns,omitempty" json:"sns,omitempty"`
+	// Kinesis holds the usage estimates of Kinesis data streams.
+	Kinesis KinesisUsage `yaml:"kinesis,omitempty" json:"kinesis,omitempty"`
+	// Firehose holds the usage estimates of Kinesis Data Firehose delivery streams.
+	Firehose FirehoseUsage `yaml:"firehose,omitempty" json:"firehose,omitempty"`
+	// DynamoDB holds the usage estimates of DynamoDB tables.
+	DynamoDB DynamoDBUsage `yaml:"dynamodb,omitempty" json:"dynamodb,omitempty"`
+	// LB holds the usage estimates of Application, Network and Gateway Load Balancers.
+	LB LBUsage `yaml:"lb,omitempty" json:"lb,omitempty"`
+	// DataTransfer lists the data transfer flows between resources, or from resources to the internet, that are
+	// charged to their source resource. It is only read from the global usage estimates.
+	DataTransfer []DataTransferFlow `yaml:"data_transfer,omitempty" json:"data_transfer,omitempty"`
+	// Resources maps resource addresses or wildcard patterns (e.g., "module.workers.*") to usage estimates for the
+	// matching resources. Fields left unset in an entry fall back to the global values above. See UsageEstimates.For.
+	Resources map[string]UsageEstimates `yaml:"resources,omitempty" json:"resources,omitempty"`
+
+	// explicit records the keys set when the usage estimates were decoded, including keys set to zero.
+	explicit map[string]bool
+}
+
+// EC2Usage holds the usage estimates of EC2 instances and Auto Scaling groups, set under the ec2 key.
+type EC2Usage struct {
+	// OperatingSystem is the operating system of EC2 instances (e.g., "Linux", "Windows", "RHEL", "SUSE" or "Windows
+	// with SQL Server Standard"). It overrides the operating system derived from the AMI in the plan.
+	OperatingSystem string `yaml:"operating_system,omitempty" json:"operating_system,omitempty"`
+	// SurplusCPUCreditHours is the estimated monthly vCPU-hours of surplus CPU credits used by T-family instances with
+	// unlimited CPU credits.
+	SurplusCPUCreditHours int `yaml:"surplus_cpu_credit_hours,omitempty" json:"surplus_cpu_credit_hours,omitempty"`
+	// SpotDiscountPercent is the estimated discount of spot instances over on-demand rates, as a percentage (e.g., 70
+	// for 70% off). Spot instances are priced at on-demand rates if it is not set.
+	SpotDiscountPercent int `yaml:"spot_discount_percent,omitempty" json:"spot_discount_percent,omitempty"`
+}
+
+// RDSUsage holds the usage estimates of RDS instances, set under the rds key.
+type RDSUsage struct {
+	// BackupStorageGB is the estimated GB of automated backups and snapshots kept for RDS instances. Backup storage up
+	// to the allocated storage of an instance is free.
+	BackupStorageGB int `yaml:"backup_storage_gb,omitempty" json:"backup_storage_gb,omitempty"`
+}
+
+// AuroraUsage holds the usage estimates of Aurora clusters, set under the aurora key.
+type AuroraUsage struct {
+	// StorageGB is the estimated GB of data stored in an Aurora cluster.
+	StorageGB int `yaml:"storage_gb,omitempty" json:"storage_gb,omitempty"`
+	// MonthlyIORequests is the estimated number of read and write I/O requests per month of an Aurora cluster in the
+	// standard configuration. Clusters with the I/O-Optimized configuration are not charged for I/O.
+	MonthlyIORequests int `yaml:"monthly_io_requests,omitempty" json:"monthly_io_requests,omitempty"`
+	// MonthlyWriteIORequests is the estimated number of write I/O requests per month of the primary cluster of an
+	// Aurora global database, which are replicated to each secondary cluster.
+	MonthlyWriteIORequests int `yaml:"monthly_write_io_requests,omitempty" json:"monthly_write_io_requests,omitempty"`
+	// ServerlessV2AverageACUs is the estimated average number of Aurora capacity units (ACUs) used by an Aurora
+	// Serverless v2 instance. Instances are priced at the minimum capacity of their cluster if it is not set.
+	ServerlessV2AverageACUs float64 `yaml:"serverless_v2_average_acus,omitempty" json:"serverless_v2_average_acus,omitempty"`
+}
+
+// ElastiCacheUsage holds the usage estimates of ElastiCache serverless caches, set under the elasticache key.
+type ElastiCacheUsage struct {
+	// ServerlessStorageGB is the estimated average GB of data stored in an ElastiCache serverless cache.
+	ServerlessStorageGB int `yaml:"serverless_storage_gb,omitempty" json:"serverless_storage_gb,omitempty"`
+	// ServerlessMonthlyECPUs is the estimated number of ElastiCache Processing Units (ECPUs) consumed per month by an
+	// ElastiCache serverless cache.
+	ServerlessMonthlyECPUs int `yaml:"serverless_monthly_ecpus,omitempty" json:"serverless_monthly_ecpus,omitempty"`
+}
+
+// CloudFrontUsage holds the usage estimates of CloudFront distributions, set under the cloudfront key.
+type CloudFrontUsage struct {
+	// MonthlyDataTransferGB is the estimated GB of data transferred out to viewers per month by a CloudFront
+	// distribution.
+	MonthlyDataTransferGB int `yaml:"monthly_data_transfer_gb,omitempty" json:"monthly_data_transfer_gb,omitempty"`
+	// MonthlyHTTPRequests is the estimated number of HTTP requests per month to a CloudFront distribution.
+	MonthlyHTTPRequests int `yaml:"monthly_http_requests,omitempty" json:"monthly_http_requests,omitempty"`
+	// MonthlyHTTPSRequests is the estimated number of HTTPS requests per month to a CloudFront distribution.
+	MonthlyHTTPSRequests int `yaml:"monthly_https_requests,omitempty" json:"monthly_https_requests,omitempty"`
+}
+
+// Route53Usage holds the usage estimates of Route 53 hosted zones and records, set under the route53 key.
+type Route53Usage struct {
+	// MonthlyQueries is the estimated number of standard DNS queries per month to a public Route 53 hosted zone.
+	MonthlyQueries int `yaml:"monthly_queries,omitempty" json:"monthly_queries,omitempty"`
+	// RecordMonthlyQueries is the estimated number of DNS queries per month to a Route 53 record with a latency,
+	// geolocation, geoproximity or IP-based routing policy. It is usually set per record.
+	RecordMonthlyQueries int `yaml:"record_monthly_queries,omitempty" json:"record_monthly_queries,omitempty"`
+}
+
+// VPCUsage holds the usage estimates of VPC endpoints, transit gateway attachments and Network Firewalls, set under the
+// vpc key.
+type VPCUsage struct {
+	// EndpointGBProcessed is the estimated GB of data processed by an interface or Gateway Load Balancer VPC endpoint
+	// per month.
+	EndpointGBProcessed int `yaml:"endpoint_gb_processed,omitempty" json:"endpoint_gb_processed,omitempty"`
+	// TransitGatewayGBProcessed is the estimated GB of data sent from a VPC to its transit gateway attachment per
+	// month.
+	TransitGatewayGBProcessed int `yaml:"transit_gateway_gb_processed,omitempty" json:"transit_gateway_gb_processed,omitempty"`
+	// NetworkFirewallGBProcessed is the estimated GB of traffic inspected by a Network Firewall per month.
+	NetworkFirewallGBProcessed int `yaml:"network_firewall_gb_processed,omitempty" json:"network_firewall_gb_processed,omitempty"`
+}
+
+// S3Usage holds the usage estimates of S3 replication, set under the s3 key.
+type S3Usage struct {
+	// ReplicationMonthlyGB is the estimated GB of objects replicated per month from an S3 bucket to each destination
+	// bucket of its replication configuration.
+	ReplicationMonthlyGB int `yaml:"replication_monthly_gb,omitempty" json:"replication_monthly_gb,omitempty"`
+}
+
+// EFSUsage holds the usage estimates of EFS file systems, set under the efs key.
+type EFSUsage struct {
+	// StorageGB is the estimated GB of data stored in an EFS file system, in all storage classes.
+	StorageGB int `yaml:"storage_gb,omitempty" json:"storage_gb,omitempty"`
+	// InfrequentAccessPercent is the estimated percentage of the data of an EFS file system stored in the Infrequent
+	// Access class. It only applies to file systems with a transition_to_ia lifecycle policy.
+	InfrequentAccessPercent int `yaml:"infrequent_access_percent,omitempty" json:"infrequent_access_percent,omitempty"`
+	// ArchivePercent is the estimated percentage of the data of an EFS file system stored in the Archive class. It only
+	// applies to file systems with a transition_to_archive lifecycle policy.
+	ArchivePercent int `yaml:"archive_percent,omitempty" json:"archive_percent,omitempty"`
+	// MonthlyReadGB is the estimated GB read per month from an EFS file system in elastic throughput mode.
+	MonthlyReadGB int `yaml:"monthly_read_gb,omitempty" json:"monthly_read_gb,omitempty"`
+	// MonthlyWriteGB is the estimated GB written per month to an EFS file system in elastic throughput mode.
+	MonthlyWriteGB int `yaml:"monthly_write_gb,omitempty" json:"monthly_write_gb,omitempty"`
+	// InfrequentAccessReadGB is the estimated GB read per month from the Infrequent Access class of an EFS file system.
+	InfrequentAccessReadGB int `yaml:"infrequent_access_read_gb,omitempty" json:"infrequent_access_read_gb,omitempty"`
+	// ArchiveReadGB is the estimated GB read per month from the Archive class of an EFS file system.
+	ArchiveReadGB int `yaml:"archive_read_gb,omitempty" json:"archive_read_gb,omitempty"`
+}
+
+// FSxUsage holds the usage estimates of FSx file systems, set under the fsx key.
+type FSxUsage struct {
+	// BackupStorageGB is the estimated GB of backups kept for an FSx file system with automatic backups.
+	BackupStorageGB int `yaml:"backup_storage_gb,omitempty" json:"backup_storage_gb,omitempty"`
+}
+
+// SQSUsage holds the usage estimates of SQS queues, set under the sqs key.
+type SQSUsage struct {
+	// MonthlyRequests is the estimated number of requests per month to an SQS queue. The first 1 million requests are
+	// free.
+	MonthlyRequests int `yaml:"monthly_requests,omitempty" json:"monthly_requests,omitempty"`
+}
+
+// SNSUsage holds the usage estimates of SNS topics, set under the sns key.
+type SNSUsage struct {
+	// MonthlyPublishRequests is the estimated number of publish requests per month to an SNS topic. The first 1 million
+	// requests are free.
+	MonthlyPublishRequests int `yaml:"monthly_publish_requests,omitempty" json:"monthly_publish_requests,omitempty"`
+	// MonthlyHTTPDeliveries is the estimated number of notifications delivered per month by an SNS topic to HTTP/S
+	// endpoints. The first 100,000 deliveries are free.
+	MonthlyHTTPDeliveries int `yaml:"monthly_http_deliveries,omitempty" json:"monthly_http_deliveries,omitempty"`
+	// MonthlyEmailDeliveries is the estimated number of notifications delivered per month by an SNS topic to email
+	// addresses. The first 1,000 deliveries are free.
+	MonthlyEmailDeliveries int `yaml:"monthly_email_deliveries,omitempty" json:"monthly_email_deliveries,omitempty"`
+}
+
+// KinesisUsage holds the usage estimates of Kinesis data streams, set under the kinesis key.
+type KinesisUsage struct {
+	// MonthlyPutPayloadUnits is the estimated number of 25 KB PUT payload units written per month to a Kinesis data
+	// stream in provisioned mode.
+	MonthlyPutPayloadUnits int `yaml:"monthly_put_payload_units,omitempty" json:"monthly_put_payload_units,omitempty"`
+	// MonthlyIngestedGB is the estimated GB written per month to a Kinesis data stream in on-demand mode.
+	MonthlyIngestedGB int `yaml:"monthly_ingested_gb,omitempty" json:"monthly_ingested_gb,omitempty"`
+	// MonthlyRetrievedGB is the estimated GB read per month from a Kinesis data stream in on-demand mode.
+	MonthlyRetrievedGB int `yaml:"monthly_retrieved_gb,omitempty" json:"monthly_retrieved_gb,omitempty"`
+}
+
+// FirehoseUsage holds the usage estimates of Kinesis Data Firehose delivery streams, set under the firehose key.
+type FirehoseUsage struct {
+	// MonthlyIngestedGB is the estimated GB ingested per month by a Kinesis Data Firehose delivery stream.
+	MonthlyIngestedGB int `yaml:"monthly_ingested_gb,omitempty" json:"monthly_ingested_gb,omitempty"`
+}
+
+// DynamoDBUsage holds the usage estimates of DynamoDB tables, set under the dynamodb key.
+type DynamoDBUsage struct {
+	// StorageGB is the estimated GB of data stored in a DynamoDB table, including its indexes.
+	StorageGB int `yaml:"storage_gb,omitempty" json:"storage_gb,omitempty"`
+	// MonthlyReadRequests is the estimated number of read request units consumed per month by a DynamoDB table in
+	// PAY_PER_REQUEST mode.
+	MonthlyReadRequests int `yaml:"monthly_read_requests,omitempty" json:"monthly_read_requests,omitempty"`
+	// MonthlyWriteRequests is the estimated number of write request units consumed per month by a DynamoDB table in
+	// PAY_PER_REQUEST mode.
+	MonthlyWriteRequests int `yaml:"monthly_write_requests,omitempty" json:"monthly_write_requests,omitempty"`
+	// MonthlyStreamReadRequests is the estimated number of DynamoDB Streams read requests per month.
+	MonthlyStreamReadRequests int `yaml:"monthly_stream_read_requests,omitempty" json:"monthly_stream_read_requests,omitempty"`
+}
+
+// LBUsage holds the usage estimates of Application, Network and Gateway Load Balancers, set under the lb key.
+type LBUsage struct {
+	// NewConnectionsPerSecond is the estimated average number of new connections (or flows) per second handled by a
+	// load balancer.
+	NewConnectionsPerSecond int `yaml:"new_connections_per_second,omitempty" json:"new_connections_per_second,omitempty"`
+	// ActiveConnections is the estimated average number of active connections (or flows) of a load balancer.
+	ActiveConnections int `yaml:"active_connections,omitempty" json:"active_connections,omitempty"`
+	// ProcessedGB is the estimated GB of data processed by a load balancer per month.
+	ProcessedGB int `yaml:"processed_gb,omitempty" json:"processed_gb,omitempty"`
+	// RuleEvaluationsPerSecond is the estimated average number of listener rule evaluations per second of an
+	// Application Load Balancer, not counting the first 10 rules, which are free.
+	RuleEvaluationsPerSecond int `yaml:"rule_evaluations_per_second,omitempty" json:"rule_evaluations_per_second,omitempty"`
+}
+
+// DataTransferFlow declares the data sent by a resource to another resource or to the internet every month.
+type DataTransferFlow struct {
+	// Source is the address of the resource sending the data (e.g., "aws_instance.web"). The flow is priced in the
+	// region of the source and its cost is added to the source resource.
+	Source string `yaml:"source" json:"source"`
+	// Destination is the address of the resource receiving the data, or "internet".
+	Destination string `yaml:"destination" json:"destination"`
+	// MonthlyGB is the estimated GB of data sent per month.
+	MonthlyGB float64 `yaml:"monthly_gb" json:"monthly_gb"`
+	// CrossAZ declares that the source and destination are in different availability zones of the same region when
+	// the availability zone of either resource is not known from the plan.
+	CrossAZ bool `yaml:"cross_az,omitempty" json:"cross_az,omitempty"`
 }
 
 // EstimationResponse defines the structure of the response body for the /estimate endpoint.
 type EstimationResponse struct {
-	// TotalMonthlyCost is the total estimated monthly cost of the resources in the plan.
-	TotalMonthlyCost float64        `json:"total_monthly_cost"`
+	// TotalMonthlyCost is the net change in estimated monthly cost of the resources in the plan.
+	TotalMonthlyCost float64 `json:"total_monthly_cost"`
+	// MonthlyCostAdded is the sum of the monthly cost increases in the plan.
+	MonthlyCostAdded float64 `json:"monthly_cost_added"`
+	// MonthlyCostRemoved is the sum of the monthly cost decreases in the plan, as a positive amount.
+	MonthlyCostRemoved float64 `json:"monthly_cost_removed"`
+	// NetMonthlyCostChange is MonthlyCostAdded minus MonthlyCostRemoved. It equals TotalMonthlyCost.
+	NetMonthlyCostChange float64 `json:"net_monthly_cost_change"`
+	// ListMonthlyCost is the net change in monthly cost at list prices, before discounts.
+	// It equals TotalMonthlyCost when no discount policy applies.
+	ListMonthlyCost float64 `json:"list_monthly_cost"`
 	// Currency is the currency of the cost estimate.
-	Currency         string         `json:"currency"`
+	Currency string `json:"currency"`
 	// Resources is a slice of ResourceCost structs, each representing the cost of a single resource.
-	Resources        []ResourceCost `json:"resources"`
+	Resources []ResourceCost `json:"resources"`
 	// Recommendations is a slice of strings, where each string is a cost-saving recommendation.
-	Recommendations  []string       `json:"recommendations"`
+	Recommendations []string `json:"recommendations"`
+	// Warnings lists problems encountered while pricing resources that may affect the accuracy of the estimate.
+	Warnings []Warning `json:"warnings"`
+	// Skipped lists the resource changes that could not be priced and are not included in the totals.
+	Skipped []SkippedResource `json:"skipped"`
+	// Coverage summarizes how many of the resource changes in the plan were priced.
+	Coverage Coverage `json:"coverage"`
+	// BaselineMonthlyCost is the estimated monthly cost of the priced resources that the plan leaves unchanged.
+	BaselineMonthlyCost float64 `json:"baseline_monthly_cost"`
+	// BaselineResources is the number of unchanged resources included in BaselineMonthlyCost.
+	BaselineResources int `json:"baseline_resources"`
+	// Pricing identifies the price snapshot the estimate was calculated from.
+	Pricing PricingSource `json:"pricing"`
 }
 
-// ResourceCost represents the cost of a single resource.
+// ResourceCost represents the cost of a single resource change.
 type ResourceCost struct {
 	// Address is the address of the resource in the Terraform plan.
-	Address      string  `json:"address"`
-	// MonthlyCost is the estimated monthly cost of the resource.
-	MonthlyCost  float64 `json:"monthly_cost"`
-	// CostBreakdown is a string describing the breakdown of the cost.
-	CostBreakdown string  `json:"cost_breakdown"`
+	Address string `json:"address"`
+	// Action is the change action: "create", "update", "replace" or "delete".
+	Action string `json:"action"`
+	// BeforeMonthlyCost is the estimated monthly cost of the resource before the change.
+	BeforeMonthlyCost float64 `json:"before_monthly_cost"`
+	// AfterMonthlyCost is the estimated monthly cost of the resource after the change.
+	AfterMonthlyCost float64 `json:"after_monthly_cost"`
+	// MonthlyCost is the change in estimated monthly cost of the resource (AfterMonthlyCost - BeforeMonthlyCost).
+	MonthlyCost float64 `json:"monthly_cost"`
+	// ListMonthlyCost is the change in monthly cost of the resource at list prices, before discounts.
+	ListMonthlyCost float64 `json:"list_monthly_cost"`
+	// CostBreakdown is a string describing the breakdown of the cost after the change, or before it for deletions.
+	CostBreakdown string `json:"cost_breakdown"`
+	// BeforeCostBreakdown is a string describing the breakdown of the cost before the change.
+	BeforeCostBreakdown string `json:"before_cost_breakdown,omitempty"`
+	// Components lists the priced line items of the cost after the change, or before it for deletions.
+	// CostBreakdown is derived from them.
+	Components []CostComponent `json:"components"`
+	// BeforeComponents lists the priced line items of the cost before the change.
+	BeforeComponents []CostComponent `json:"before_components,omitempty"`
+	// PreviousAddress is the address of the resource before it was moved by a moved block, if it was moved.
+	PreviousAddress string `json:"previous_address,omitempty"`
+	// Imported is true if the resource is being imported by an import block.
+	Imported bool `json:"imported,omitempty"`
+	// PricingModel describes the pricing model the costs are based on (e.g., "on_demand").
+	PricingModel string `json:"pricing_model"`
+	// Commitment compares the on-demand cost of the resource with its cost under a reserved commitment or Savings
+	// Plan: the selected one, or a 1yr No Upfront standard reservation for resources priced on demand.
+	// It is omitted for resources without reserved terms or Savings Plan rates matching the commitment.
+	Commitment *CommitmentComparison `json:"commitment,omitempty"`
+	// MonthlyCostRange is the monthly cost of the resource at its minimum and maximum capacity after the change, or
+	// before it for deletions. It is only set for resources whose capacity scales, such as Auto Scaling groups.
+	MonthlyCostRange *CostRange `json:"monthly_cost_range,omitempty"`
+}
+
+// CostRange is the range of the monthly cost of a resource whose capacity scales.
+type CostRange struct {
+	// MinCapacity is the minimum capacity of the resource (e.g., the min_size of an Auto Scaling group).
+	MinCapacity float64 `json:"min_capacity"`
+	// MaxCapacity is the maximum capacity of the resource (e.g., the max_size of an Auto Scaling group).
+	MaxCapacity float64 `json:"max_capacity"`
+	// MinMonthlyCost is the estimated monthly cost of the resource at its minimum capacity.
+	MinMonthlyCost float64 `json:"min_monthly_cost"`
+	// MaxMonthlyCost is the estimated monthly cost of the resource at its maximum capacity.
+	MaxMonthlyCost float64 `json:"max_monthly_cost"`
+}
+
+// CostComponent is a single priced line item of a resource's monthly cost, such as instance hours or storage.
+type CostComponent struct {
+	// Name describes the line item (e.g., "Instance usage (t3.medium)" or "Storage (gp3)").
+	Name string `json:"name"`
+	// Unit is the unit the line item is priced in (e.g., "hour", "GB-month" or "request").
+	Unit string `json:"unit"`
+	// MonthlyQuantity is the number of units consumed per month.
+	MonthlyQuantity float64 `json:"monthly_quantity"`
+	// UnitPrice is the price of a single unit.
+	UnitPrice float64 `json:"unit_price"`
+	// SKU is the SKU of the price list product the unit price comes from.
+	SKU string `json:"sku,omitempty"`
+	// OfferTermCode is the code of the pricing term the unit price comes from (e.g., "JRTCKXETXF" for on-demand).
+	OfferTermCode string `json:"offer_term_code,omitempty"`
+	// ListUnitPrice is the list price of a single unit, if a discount rule applies to the line item.
+	ListUnitPrice float64 `json:"list_unit_price,omitempty"`
+	// ListMonthlyCost is the monthly cost of the line item at list prices, if a discount rule applies to it.
+	ListMonthlyCost float64 `json:"list_monthly_cost,omitempty"`
+	// Discount describes the discount rule that applies to the line item (e.g., "10% off AmazonEC2").
+	Discount string `json:"discount,omitempty"`
+	// MonthlyCost is the monthly cost of the line item (MonthlyQuantity * UnitPrice).
+	MonthlyCost float64 `json:"monthly_cost"`
+
+	// spot is true if the unit price is an estimated spot price derived from the on-demand price of the SKU.
+	// Reserved terms and unit price overrides of the SKU do not apply to it.
+	spot bool
+}
+
+// Change actions reported in ResourceCost.Action.
+const (
+	// ActionCreate means the resource is created.
+	ActionCreate = "create"
+	// ActionUpdate means the resource is updated in place.
+	ActionUpdate = "update"
+	// ActionReplace means the resource is destroyed and re-created.
+	ActionReplace = "replace"
+	// ActionDelete means the resource is destroyed.
+	ActionDelete = "delete"
+	// ActionNoOp means the resource is unchanged. Unchanged resources count toward the baseline cost.
+	ActionNoOp = "no-op"
+)
+
+// Warning codes reported in EstimationResponse.Warnings.
+const (
+	// WarningAmbiguousMatch means several SKUs with different prices matched a resource and one was chosen by tie-breaking.
+	WarningAmbiguousMatch = "ambiguous_match"
+	// WarningStalePricing means the pricing data is older than the configured maximum age.
+	WarningStalePricing = "stale_pricing"
+	// WarningSpotPricedOnDemand means spot instances were priced at on-demand rates because no spot discount was estimated.
+	WarningSpotPricedOnDemand = "spot_priced_on_demand"
+	// WarningDataTransferNotPriced means a declared data transfer flow could not be priced and was left out of the
+	// cost of its source resource.
+	WarningDataTransferNotPriced = "data_transfer_not_priced"
+)
+
+// Warning describes a problem encountered while pricing a resource.
+type Warning struct {
+	// Address is the address of the resource in the Terraform plan.
+	Address string `json:"address"`
+	// Code is a machine-readable warning code (e.g., "ambiguous_match").
+	Code string `json:"code"`
+	// Message is a human-readable description of the warning.
+	Message string `json:"message"`
+	// SKUs lists the competing SKUs for an ambiguous match.
+	SKUs []string `json:"skus,omitempty"`
+}
+
+// Skip reason codes reported in SkippedResource.Reason.
+const (
+	// SkipReasonUnsupportedType means no calculator is registered for the resource type.
+	SkipReasonUnsupportedType = "unsupported_type"
+	// SkipReasonMissingAttribute means an attribute needed to price the resource is missing or invalid.
+	SkipReasonMissingAttribute = "missing_attribute"
+	// SkipReasonPriceNotFound means no matching price was found in the price list.
+	SkipReasonPriceNotFound = "price_not_found"
+	// SkipReasonBilledSeparately means the resource is billed through another resource, such as a dedicated host.
+	SkipReasonBilledSeparately = "billed_separately"
+	// SkipReasonInvalidUsage means the usage estimates of the resource are inconsistent.
+	SkipReasonInvalidUsage = "invalid_usage"
+	// SkipReasonError means the resource could not be priced for another reason.
+	SkipReasonError = "estimation_error"
+)
+
+// SkippedResource describes a resource change that could not be priced.
+type SkippedResource struct {
+	// Address is the address of the resource in the Terraform plan.
+	Address string `json:"address"`
+	// Type is the Terraform resource type (e.g., "aws_instance").
+	Type string `json:"type"`
+	// Reason is a machine-readable reason code (e.g., "unsupported_type").
+	Reason string `json:"reason"`
+	// Message is a human-readable description of why the resource was skipped.
+	Message string `json:"message"`
+}
+
+// Coverage summarizes how many of the resource changes in a plan were priced.
+type Coverage struct {
+	// TotalResources is the number of resource changes with a cost impact, priced or not.
+	TotalResources int `json:"total_resources"`
+	// PricedResources is the number of resource changes that were priced.
+	PricedResources int `json:"priced_resources"`
+	// SkippedResources is the number of resource changes that could not be priced.
+	SkippedResources int `json:"skipped_resources"`
+}
+
+// PricingSource identifies the price snapshot an estimate was calculated from.
+type PricingSource struct {
+	// LastUpdated is when the prices were last fetched from AWS, or nil if unknown.
+	LastUpdated *time.Time `json:"last_updated,omitempty"`
+	// Offers lists the AWS offer files in the snapshot, ordered by offer code.
+	Offers []PricingOffer `json:"offers"`
+}
+
+// PricingOffer identifies a single AWS offer file in a price snapshot.
+type PricingOffer struct {
+	// OfferCode is the offer code of the offer file (e.g., "AmazonEC2").
+	OfferCode string `json:"offer_code"`
+	// Version is the version of the offer file.
+	Version string `json:"version,omitempty"`
+	// PublicationDate is when AWS published the offer file.
+	PublicationDate time.Time `json:"publication_date"`
 }
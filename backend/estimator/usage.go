@@ -0,0 +1,282 @@
+package estimator
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// For returns the usage estimates for a single resource.
+// The per-resource entries in Resources are matched against the resource address in order of precedence:
+//
+//  1. An entry whose key equals the address (e.g., `aws_lambda_function.api["eu"]`).
+//  2. An entry whose key equals the address without its instance key (e.g., `aws_lambda_function.api`).
+//  3. The most specific wildcard entry, where `*` matches any sequence of characters
+//     (e.g., `module.workers.*` or `aws_lambda_function.*`). Patterns with more literal characters are more specific.
+//
+// Fields left unset in the matching entry fall back to the global values. Fields set to zero in a decoded entry
+// override them, so that an entry can declare that a resource is idle.
+//
+// Parameters:
+//   address: The address of the resource in the Terraform plan.
+//
+// Returns:
+//   A pointer to the usage estimates for the resource, or the global usage estimates if no entry matches.
+func (u *UsageEstimates) For(address string) *UsageEstimates {
+	if u == nil || len(u.Resources) == 0 {
+		return u
+	}
+
+	key, ok := u.matchResource(address)
+	if !ok {
+		return u
+	}
+
+	merged := *u
+	merged.Resources = nil
+	override := u.Resources[key]
+	mergeUsage(&merged, &override)
+	return &merged
+}
+
+// matchResource finds the key of the per-resource entry that applies to an address.
+//
+// Parameters:
+//   address: The address of the resource in the Terraform plan.
+//
+// Returns:
+//   The key of the matching entry, and true if an entry matches.
+func (u *UsageEstimates) matchResource(address string) (string, bool) {
+	if _, ok := u.Resources[address]; ok {
+		return address, true
+	}
+	if base := trimInstanceKey(address); base != address {
+		if _, ok := u.Resources[base]; ok {
+			return base, true
+		}
+	}
+
+	var patterns []string
+	for key := range u.Resources {
+		if strings.Contains(key, "*") && matchPattern(key, address) {
+			patterns = append(patterns, key)
+		}
+	}
+	if len(patterns) == 0 {
+		return "", false
+	}
+
+	sort.Slice(patterns, func(i, j int) bool {
+		si, sj := patternSpecificity(patterns[i]), patternSpecificity(patterns[j])
+		if si != sj {
+			return si > sj
+		}
+		return patterns[i] < patterns[j]
+	})
+	return patterns[0], true
+}
+
+// mergeUsage copies the fields of an override that are non-zero or were set when it was decoded into a set of usage
+// estimates. Data transfer flows are only read from the global usage estimates, so they are not overridden.
+//
+// Parameters:
+//   dst: The usage estimates to update.
+//   override: The per-resource usage estimates to apply.
+func mergeUsage(dst, override *UsageEstimates) {
+	mergeUsageFields(reflect.ValueOf(dst).Elem(), reflect.ValueOf(override).Elem(), "", override.explicit)
+}
+
+// mergeUsageFields copies the fields of an override struct that are non-zero or explicitly set into a struct of the
+// same type, descending into nested structs.
+//
+// Parameters:
+//   dst: The struct to update.
+//   override: The struct to apply.
+//   prefix: The key path of the structs within the usage estimates (e.g., "" or "lambda.").
+//   explicit: The key paths set when the override was decoded.
+func mergeUsageFields(dst, override reflect.Value, prefix string, explicit map[string]bool) {
+	for i := 0; i < override.NumField(); i++ {
+		field := override.Type().Field(i)
+		if !field.IsExported() || field.Name == "Resources" || field.Name == "DataTransfer" {
+			continue
+		}
+		key := prefix + usageKey(field)
+		if field.Type.Kind() == reflect.Struct {
+			mergeUsageFields(dst.Field(i), override.Field(i), key+".", explicit)
+			continue
+		}
+		if value := override.Field(i); !value.IsZero() || explicit[key] {
+			dst.Field(i).Set(value)
+		}
+	}
+}
+
+// usageKey returns the configuration key of a usage estimates field (e.g., "lambda_monthly_requests").
+func usageKey(field reflect.StructField) string {
+	key, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	return key
+}
+
+// usageEstimates is UsageEstimates without its custom encoding methods.
+type usageEstimates UsageEstimates
+
+// UnmarshalYAML decodes usage estimates from YAML and records the keys that are set, including keys set to zero.
+func (u *UsageEstimates) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal((*usageEstimates)(u)); err != nil {
+		return err
+	}
+	var raw map[string]interface{}
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	u.explicit = explicitKeys(raw, "", nil)
+	return nil
+}
+
+// UnmarshalJSON decodes usage estimates from JSON and records the keys that are set, including keys set to zero.
+func (u *UsageEstimates) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, (*usageEstimates)(u)); err != nil {
+		return err
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	u.explicit = explicitKeys(raw, "", nil)
+	return nil
+}
+
+// MarshalJSON encodes usage estimates to JSON, keeping the keys that were explicitly set to zero when they were
+// decoded, so that per-resource overrides to zero survive being sent to the backend.
+func (u UsageEstimates) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(usageEstimates(u))
+	if err != nil || len(u.explicit) == 0 {
+		return data, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for key := range u.explicit {
+		if value, ok := usageField(reflect.ValueOf(u), key); ok && value.Kind() != reflect.Struct {
+			setUsageKey(fields, key, value.Interface())
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// explicitKeys collects the key paths of a decoded usage estimates object (e.g., "ec2.operating_system").
+// The per-resource entries are not included; each entry records its own keys.
+//
+// Parameters:
+//   raw: The decoded object.
+//   prefix: The key path of the object.
+//   keys: The key paths collected so far, or nil.
+//
+// Returns:
+//   The set of key paths.
+func explicitKeys(raw map[string]interface{}, prefix string, keys map[string]bool) map[string]bool {
+	if keys == nil {
+		keys = make(map[string]bool)
+	}
+	for key, value := range raw {
+		if prefix == "" && key == "resources" {
+			continue
+		}
+		keys[prefix+key] = true
+		switch nested := value.(type) {
+		case map[string]interface{}:
+			explicitKeys(nested, prefix+key+".", keys)
+		case map[interface{}]interface{}:
+			converted := make(map[string]interface{}, len(nested))
+			for k, v := range nested {
+				if name, ok := k.(string); ok {
+					converted[name] = v
+				}
+			}
+			explicitKeys(converted, prefix+key+".", keys)
+		}
+	}
+	return keys
+}
+
+// usageField finds the field of a usage estimates struct at a key path.
+func usageField(v reflect.Value, path string) (reflect.Value, bool) {
+	key, rest, nested := strings.Cut(path, ".")
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() || usageKey(field) != key {
+			continue
+		}
+		if nested {
+			if field.Type.Kind() != reflect.Struct {
+				return reflect.Value{}, false
+			}
+			return usageField(v.Field(i), rest)
+		}
+		return v.Field(i), true
+	}
+	return reflect.Value{}, false
+}
+
+// setUsageKey sets a value at a key path of a JSON object, keeping any value already there.
+func setUsageKey(fields map[string]interface{}, path string, value interface{}) {
+	key, rest, nested := strings.Cut(path, ".")
+	if !nested {
+		if _, ok := fields[key]; !ok {
+			fields[key] = value
+		}
+		return
+	}
+	child, ok := fields[key].(map[string]interface{})
+	if !ok {
+		child = make(map[string]interface{})
+		fields[key] = child
+	}
+	setUsageKey(child, rest, value)
+}
+
+// trimInstanceKey removes the trailing instance key (e.g., `[0]` or `["eu"]`) from a resource address.
+func trimInstanceKey(address string) string {
+	if strings.HasSuffix(address, "]") {
+		if i := strings.LastIndex(address, "["); i > 0 {
+			return address[:i]
+		}
+	}
+	return address
+}
+
+// patternSpecificity returns the number of literal characters in a wildcard pattern.
+func patternSpecificity(pattern string) int {
+	return len(pattern) - strings.Count(pattern, "*")
+}
+
+// matchPattern reports whether an address matches a pattern in which `*` matches any sequence of characters.
+// Unlike path.Match, every other character, including `[` and `]`, matches itself.
+//
+// Parameters:
+//   pattern: The pattern to match.
+//   address: The address of the resource in the Terraform plan.
+//
+// Returns:
+//   True if the address matches the pattern.
+func matchPattern(pattern, address string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == address
+	}
+	if !strings.HasPrefix(address, parts[0]) {
+		return false
+	}
+	address = address[len(parts[0]):]
+
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(address, part)
+		if i < 0 {
+			return false
+		}
+		address = address[i+len(part):]
+	}
+	return strings.HasSuffix(address, parts[len(parts)-1])
+}
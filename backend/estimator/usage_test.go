@@ -0,0 +1,176 @@
+package estimator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestUsageEstimatesFor(t *testing.T) {
+	usage := &UsageEstimates{
+		LambdaMonthlyRequests: 1000,
+		LambdaAvgDurationMS:   100,
+		S3StorageGB:           10,
+		Resources: map[string]UsageEstimates{
+			"aws_lambda_function.api":                   {LambdaMonthlyRequests: 50000000},
+			`aws_lambda_function.regional["eu"]`:        {LambdaMonthlyRequests: 20000},
+			"aws_lambda_function.regional":              {LambdaMonthlyRequests: 10000},
+			"module.workers.*":                          {LambdaAvgDurationMS: 900},
+			"module.workers.aws_lambda_function.cron_*": {LambdaMonthlyRequests: 30},
+		},
+	}
+
+	t.Run("applies an exact match over the global values", func(t *testing.T) {
+		got := usage.For("aws_lambda_function.api")
+		assert.Equal(t, 50000000, got.LambdaMonthlyRequests)
+		assert.Equal(t, 100, got.LambdaAvgDurationMS)
+		assert.Equal(t, 10, got.S3StorageGB)
+		assert.Nil(t, got.Resources)
+	})
+
+	t.Run("falls back to the global values when nothing matches", func(t *testing.T) {
+		got := usage.For("aws_lambda_function.other")
+		assert.Same(t, usage, got)
+	})
+
+	t.Run("prefers the instance address over the resource address", func(t *testing.T) {
+		assert.Equal(t, 20000, usage.For(`aws_lambda_function.regional["eu"]`).LambdaMonthlyRequests)
+		assert.Equal(t, 10000, usage.For(`aws_lambda_function.regional["us"]`).LambdaMonthlyRequests)
+		assert.Equal(t, 10000, usage.For("aws_lambda_function.regional[0]").LambdaMonthlyRequests)
+	})
+
+	t.Run("uses the most specific wildcard pattern", func(t *testing.T) {
+		got := usage.For("module.workers.aws_lambda_function.cron_nightly")
+		assert.Equal(t, 30, got.LambdaMonthlyRequests)
+		assert.Equal(t, 100, got.LambdaAvgDurationMS)
+
+		got = usage.For(`module.workers.aws_lambda_function.queue["jobs"]`)
+		assert.Equal(t, 1000, got.LambdaMonthlyRequests)
+		assert.Equal(t, 900, got.LambdaAvgDurationMS)
+	})
+
+	t.Run("keeps the global data transfer flows", func(t *testing.T) {
+		flows := []DataTransferFlow{{Source: "aws_lambda_function.api", Destination: "internet", MonthlyGB: 100}}
+		withFlows := &UsageEstimates{
+			DataTransfer: flows,
+			Resources: map[string]UsageEstimates{
+				"aws_lambda_function.api": {DataTransfer: []DataTransferFlow{{Source: "aws_lambda_function.api", Destination: "internet", MonthlyGB: 5}}},
+			},
+		}
+		assert.Equal(t, flows, withFlows.For("aws_lambda_function.api").DataTransfer)
+	})
+
+	t.Run("handles nil usage estimates", func(t *testing.T) {
+		var empty *UsageEstimates
+		assert.Nil(t, empty.For("aws_lambda_function.api"))
+	})
+}
+
+func TestUsageEstimatesZeroOverride(t *testing.T) {
+	t.Run("lets a YAML entry override a global value with zero", func(t *testing.T) {
+		var usage UsageEstimates
+		err := yaml.Unmarshal([]byte(`
+nat_gateway_gb_processed: 500
+lambda_monthly_requests: 1000
+resources:
+  aws_nat_gateway.standby:
+    nat_gateway_gb_processed: 0
+`), &usage)
+		assert.NoError(t, err)
+
+		got := usage.For("aws_nat_gateway.standby")
+		assert.Zero(t, got.NATGatewayGBProcessed)
+		assert.Equal(t, 1000, got.LambdaMonthlyRequests)
+		assert.Equal(t, 500, usage.For("aws_nat_gateway.main").NATGatewayGBProcessed)
+	})
+
+	t.Run("lets a JSON entry override a global value with zero", func(t *testing.T) {
+		var usage UsageEstimates
+		err := json.Unmarshal([]byte(`{"lambda_monthly_requests": 1000, "resources": {"aws_lambda_function.idle": {"lambda_monthly_requests": 0}}}`), &usage)
+		assert.NoError(t, err)
+		assert.Zero(t, usage.For("aws_lambda_function.idle").LambdaMonthlyRequests)
+	})
+
+	t.Run("keeps explicit zeros when re-encoded to JSON", func(t *testing.T) {
+		var usage UsageEstimates
+		err := yaml.Unmarshal([]byte(`
+lambda_monthly_requests: 1000
+resources:
+  aws_lambda_function.idle:
+    lambda_monthly_requests: 0
+`), &usage)
+		assert.NoError(t, err)
+
+		data, err := json.Marshal(&usage)
+		assert.NoError(t, err)
+		var decoded UsageEstimates
+		assert.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Zero(t, decoded.For("aws_lambda_function.idle").LambdaMonthlyRequests)
+		assert.Equal(t, 1000, decoded.For("aws_lambda_function.busy").LambdaMonthlyRequests)
+	})
+
+	t.Run("merges the estimates grouped by service field by field", func(t *testing.T) {
+		var usage UsageEstimates
+		err := yaml.Unmarshal([]byte(`
+ec2:
+  surplus_cpu_credit_hours: 100
+  spot_discount_percent: 70
+resources:
+  aws_autoscaling_group.batch:
+    ec2:
+      spot_discount_percent: 0
+  aws_instance.reporting:
+    ec2:
+      operating_system: Windows
+`), &usage)
+		assert.NoError(t, err)
+
+		batch := usage.For("aws_autoscaling_group.batch")
+		assert.Zero(t, batch.EC2.SpotDiscountPercent)
+		assert.Equal(t, 100, batch.EC2.SurplusCPUCreditHours)
+
+		reporting := usage.For("aws_instance.reporting")
+		assert.Equal(t, "Windows", reporting.EC2.OperatingSystem)
+		assert.Equal(t, 70, reporting.EC2.SpotDiscountPercent)
+
+		data, err := json.Marshal(&usage)
+		assert.NoError(t, err)
+		var decoded UsageEstimates
+		assert.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Zero(t, decoded.For("aws_autoscaling_group.batch").EC2.SpotDiscountPercent)
+	})
+
+	t.Run("falls back to the global values for unset fields", func(t *testing.T) {
+		var usage UsageEstimates
+		err := json.Unmarshal([]byte(`{"lambda_monthly_requests": 1000, "resources": {"aws_lambda_function.api": {"lambda_avg_duration_ms": 50}}}`), &usage)
+		assert.NoError(t, err)
+		assert.Equal(t, 1000, usage.For("aws_lambda_function.api").LambdaMonthlyRequests)
+	})
+}
+
+func TestMatchPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		address string
+		want    bool
+	}{
+		{"aws_s3_bucket.logs", "aws_s3_bucket.logs", true},
+		{"aws_s3_bucket.logs", "aws_s3_bucket.logs_archive", false},
+		{"aws_s3_bucket.*", "aws_s3_bucket.logs", true},
+		{"aws_s3_bucket.*", "aws_lambda_function.logs", false},
+		{"*.logs", "module.a.aws_s3_bucket.logs", true},
+		{"module.*.aws_s3_bucket.*", `module.app["prod"].aws_s3_bucket.logs`, true},
+		{"module.*.aws_s3_bucket.*", "aws_s3_bucket.logs", false},
+		{`aws_s3_bucket.data["*"]`, `aws_s3_bucket.data["eu"]`, true},
+		{"*", "aws_s3_bucket.logs", true},
+		{"a*a", "a", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.address, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchPattern(tt.pattern, tt.address))
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package estimator
+
+import (
+	"fmt"
+)
+
+func init() {
+	MustRegister(NewCalculator("aws_eip", []string{"AmazonVPC"}, nil, costForEIP))
+	MustRegister(NewCalculator("aws_vpc_endpoint", []string{"AmazonVPC"}, nil, costForVPCEndpoint))
+	MustRegister(NewCalculator("aws_ec2_transit_gateway_vpc_attachment", []string{"AmazonVPC"}, nil, costForTransitGatewayAttachment))
+	MustRegister(NewCalculator("aws_vpn_connection", []string{"AmazonVPC"}, nil, costForVPNConnection))
+	MustRegister(NewCalculator("aws_networkfirewall_firewall", []string{"AWSNetworkFirewall"}, nil, costForNetworkFirewall))
+}
+
+// Usage types of VPC networking resources, without a region prefix.
+const (
+	vpcPublicIPv4Address        = "PublicIPv4:InUseAddress"
+	vpcInterfaceEndpointHours   = "VpcEndpoint-Hours"
+	vpcInterfaceEndpointBytes   = "VpcEndpoint-Bytes"
+	vpcGWLBEndpointHours        = "VpcEndpoint-GWE-Hours"
+	vpcGWLBEndpointBytes        = "VpcEndpoint-GWE-Bytes"
+	vpcTransitGatewayHours      = "TransitGateway-Hours"
+	vpcTransitGatewayBytes      = "TransitGateway-Bytes"
+	vpcVPNConnectionHours       = "VPN-Usage-Hours:ipsec.1"
+	networkFirewallEndpointHour = "Endpoint-Hour"
+	networkFirewallTrafficBytes = "Traffic-GB-Processed"
+)
+
+// costForEIP calculates the cost of an AWS Elastic IP address.
+// Every public IPv4 address is charged by the hour, whether or not it is associated with a resource. Addresses from
+// a customer-owned (BYOIP) pool are not charged.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   attributes: The attributes of the Elastic IP resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the Elastic IP address.
+//   An error if the pricing data cannot be found.
+func costForEIP(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	if pool, _ := attributes["public_ipv4_pool"].(string); pool != "" && pool != "amazon" {
+		return newCost(), nil
+	}
+	sku, price, err := vpcSKU(ctx, "AmazonVPC", vpcPublicIPv4Address)
+	if err != nil {
+		return nil, err
+	}
+	return newCost(hourlyComponent("Public IPv4 address", sku, 1, price)), nil
+}
+
+// costForVPCEndpoint calculates the cost of an AWS VPC endpoint.
+// Interface endpoints are charged by the hour in each subnet (one per availability zone) in subnet_ids, and Gateway
+// Load Balancer endpoints by the hour. Both are charged for the data in the vpc.endpoint_gb_processed usage estimate.
+// Gateway endpoints, for S3 and DynamoDB, are free.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its usage estimates may include the data processed by the
+//        endpoint.
+//   attributes: The attributes of the VPC endpoint resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the VPC endpoint.
+//   An error if the endpoint type is unknown or the pricing data cannot be found.
+func costForVPCEndpoint(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	endpointType, _ := attributes["vpc_endpoint_type"].(string)
+	var hoursUsageType, bytesUsageType string
+	zones := 1.0
+	switch endpointType {
+	case "", "Gateway":
+		return newCost(), nil
+	case "Interface":
+		hoursUsageType, bytesUsageType = vpcInterfaceEndpointHours, vpcInterfaceEndpointBytes
+		if subnets, _ := attributes["subnet_ids"].([]interface{}); len(subnets) > 0 {
+			zones = float64(len(subnets))
+		}
+	case "GatewayLoadBalancer":
+		hoursUsageType, bytesUsageType = vpcGWLBEndpointHours, vpcGWLBEndpointBytes
+	default:
+		return nil, fmt.Errorf("%w: unknown VPC endpoint type %q", ErrMissingAttribute, endpointType)
+	}
+
+	sku, price, err := vpcSKU(ctx, "AmazonVPC", hoursUsageType)
+	if err != nil {
+		return nil, err
+	}
+	name := "Gateway Load Balancer endpoint"
+	if endpointType == "Interface" {
+		name = "Interface endpoint (per availability zone)"
+	}
+	components := []CostComponent{hourlyComponent(name, sku, zones, price)}
+
+	if ctx.Usage != nil {
+		processed, err := vpcDataProcessed(ctx, "AmazonVPC", bytesUsageType, float64(ctx.Usage.VPC.EndpointGBProcessed))
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, processed...)
+	}
+	return newCost(components...), nil
+}
+
+// costForTransitGatewayAttachment calculates the cost of an AWS Transit Gateway VPC attachment.
+// Attachments are charged by the hour and for the data in the vpc.transit_gateway_gb_processed usage estimate.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its usage estimates may include the data processed by the
+//        attachment.
+//   attributes: The attributes of the Transit Gateway VPC attachment resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the attachment.
+//   An error if the pricing data cannot be found.
+func costForTransitGatewayAttachment(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	sku, price, err := vpcSKU(ctx, "AmazonVPC", vpcTransitGatewayHours)
+	if err != nil {
+		return nil, err
+	}
+	components := []CostComponent{hourlyComponent("Transit gateway attachment", sku, 1, price)}
+
+	if ctx.Usage != nil {
+		processed, err := vpcDataProcessed(ctx, "AmazonVPC", vpcTransitGatewayBytes, float64(ctx.Usage.VPC.TransitGatewayGBProcessed))
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, processed...)
+	}
+	return newCost(components...), nil
+}
+
+// costForVPNConnection calculates the cost of an AWS Site-to-Site VPN connection.
+// Connections are charged by the hour. Connections that terminate on a transit gateway are also charged for their
+// transit gateway attachment.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   attributes: The attributes of the VPN connection resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the VPN connection.
+//   An error if the pricing data cannot be found.
+func costForVPNConnection(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	sku, price, err := vpcSKU(ctx, "AmazonVPC", vpcVPNConnectionHours)
+	if err != nil {
+		return nil, err
+	}
+	components := []CostComponent{hourlyComponent("VPN connection", sku, 1, price)}
+
+	if transitGatewayID, _ := attributes["transit_gateway_id"].(string); transitGatewayID != "" {
+		sku, price, err := vpcSKU(ctx, "AmazonVPC", vpcTransitGatewayHours)
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, hourlyComponent("Transit gateway attachment", sku, 1, price))
+	}
+	return newCost(components...), nil
+}
+
+// costForNetworkFirewall calculates the cost of an AWS Network Firewall.
+// Firewalls are charged by the hour for the endpoint in each subnet_mapping (one per availability zone), and for
+// the traffic in the vpc.network_firewall_gb_processed usage estimate.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change. Its usage estimates may include the traffic processed by
+//        the firewall.
+//   attributes: The attributes of the Network Firewall resource.
+//
+// Returns:
+//   A pointer to a Cost struct representing the monthly cost of the firewall.
+//   An error if the pricing data cannot be found.
+func costForNetworkFirewall(ctx *CalculationContext, attributes map[string]interface{}) (*Cost, error) {
+	endpoints := float64(len(blocks(attributes, "subnet_mapping")))
+	if endpoints == 0 {
+		endpoints = 1
+	}
+	sku, price, err := vpcSKU(ctx, "AWSNetworkFirewall", networkFirewallEndpointHour)
+	if err != nil {
+		return nil, err
+	}
+	components := []CostComponent{hourlyComponent("Firewall endpoint (per availability zone)", sku, endpoints, price)}
+
+	if ctx.Usage != nil {
+		processed, err := vpcDataProcessed(ctx, "AWSNetworkFirewall", networkFirewallTrafficBytes, float64(ctx.Usage.VPC.NetworkFirewallGBProcessed))
+		if err != nil {
+			return nil, err
+		}
+		components = append(components, processed...)
+	}
+	return newCost(components...), nil
+}
+
+// vpcSKU finds the product of a networking usage type in the region of the estimate.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   serviceCode: The AWS service code (e.g., "AmazonVPC").
+//   usageType: The usage type without a region prefix (e.g., "VpcEndpoint-Hours").
+//
+// Returns:
+//   The SKU and price of the product.
+//   An error if the pricing data cannot be found.
+func vpcSKU(ctx *CalculationContext, serviceCode, usageType string) (string, float64, error) {
+	return ctx.MatchSKU(fmt.Sprintf("%s %s", serviceCode, usageType), usageTypeSKUs(ctx, serviceCode, ctx.Location, usageType))
+}
+
+// vpcDataProcessed prices the GB of data processed by a networking resource with its price tiers.
+//
+// Parameters:
+//   ctx: The calculation context for the resource change.
+//   serviceCode: The AWS service code (e.g., "AmazonVPC").
+//   usageType: The usage type of the data processed, without a region prefix (e.g., "VpcEndpoint-Bytes").
+//   gb: The GB of data processed per month.
+//
+// Returns:
+//   The cost components, or none if no data is processed.
+//   An error if the pricing data cannot be found.
+func vpcDataProcessed(ctx *CalculationContext, serviceCode, usageType string, gb float64) ([]CostComponent, error) {
+	if gb <= 0 {
+		return nil, nil
+	}
+	sku, _, err := vpcSKU(ctx, serviceCode, usageType)
+	if err != nil {
+		return nil, err
+	}
+	return tieredComponents(ctx, "Data processed", "GB", sku, gb)
+}
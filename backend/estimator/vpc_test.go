@@ -0,0 +1,108 @@
+package estimator
+
+import (
+	"testing"
+
+	"cloudcostguard/backend/pricing"
+	"cloudcostguard/backend/terraform"
+	"github.com/stretchr/testify/assert"
+)
+
+// createVPCPriceList creates a price list with Elastic IP, VPC endpoint, Transit Gateway, VPN and Network Firewall
+// prices.
+func createVPCPriceList() *pricing.PriceList {
+	usEast := "US East (N. Virginia)"
+	priceList := pricing.NewPriceList()
+	add := func(sku, serviceCode, usageType string, tiers ...[3]string) {
+		addTieredMockProduct(priceList, sku, pricing.ProductAttributes{ServiceCode: serviceCode, Location: usEast, UsageType: usageType}, tiers...)
+	}
+	add("ipv4", "AmazonVPC", "USE1-PublicIPv4:InUseAddress", [3]string{"0", "Inf", "0.005"})
+	add("endpoint-hours", "AmazonVPC", "USE1-VpcEndpoint-Hours", [3]string{"0", "Inf", "0.01"})
+	add("endpoint-bytes", "AmazonVPC", "USE1-VpcEndpoint-Bytes", [3]string{"0", "1048576", "0.01"}, [3]string{"1048576", "Inf", "0.006"})
+	add("gwlb-endpoint-hours", "AmazonVPC", "USE1-VpcEndpoint-GWE-Hours", [3]string{"0", "Inf", "0.01"})
+	add("tgw-hours", "AmazonVPC", "USE1-TransitGateway-Hours", [3]string{"0", "Inf", "0.05"})
+	add("tgw-bytes", "AmazonVPC", "USE1-TransitGateway-Bytes", [3]string{"0", "Inf", "0.02"})
+	add("vpn", "AmazonVPC", "USE1-VPN-Usage-Hours:ipsec.1", [3]string{"0", "Inf", "0.05"})
+	add("firewall-hours", "AWSNetworkFirewall", "USE1-Endpoint-Hour", [3]string{"0", "Inf", "0.395"})
+	add("firewall-bytes", "AWSNetworkFirewall", "USE1-Traffic-GB-Processed", [3]string{"0", "Inf", "0.065"})
+	return priceList
+}
+
+func TestVPCNetworkingPricing(t *testing.T) {
+	priceList := createVPCPriceList()
+	estimate := func(t *testing.T, usage *UsageEstimates, resourceType string, after map[string]interface{}) *EstimationResponse {
+		plan := &terraform.Plan{ResourceChanges: []*terraform.ResourceChange{{
+			Address: resourceType + ".main",
+			Type:    resourceType,
+			Change:  terraform.Change{Actions: []string{"create"}},
+			After:   after,
+		}}}
+		result, err := Estimate(plan, priceList, "us-east-1", usage)
+		assert.NoError(t, err)
+		return result
+	}
+
+	t.Run("charges Elastic IPs for their public IPv4 address", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{}, "aws_eip", map[string]interface{}{"domain": "vpc"})
+		assert.InDelta(t, 0.005*730, result.TotalMonthlyCost, 1e-9)
+
+		result = estimate(t, &UsageEstimates{}, "aws_eip", map[string]interface{}{"public_ipv4_pool": "ipv4pool-ec2-0123"})
+		assert.Zero(t, result.TotalMonthlyCost)
+	})
+
+	t.Run("charges interface endpoints per availability zone and for data processed", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{VPC: VPCUsage{EndpointGBProcessed: 1000}}, "aws_vpc_endpoint", map[string]interface{}{
+			"vpc_endpoint_type": "Interface",
+			"subnet_ids":        []interface{}{"subnet-a", "subnet-b", "subnet-c"},
+		})
+		if assert.Len(t, result.Resources, 1) {
+			components := result.Resources[0].Components
+			assert.Equal(t, "Interface endpoint (per availability zone)", components[0].Name)
+			assert.InDelta(t, 3*730, components[0].MonthlyQuantity, 1e-9)
+			assert.Equal(t, "Data processed (first 1048576 GB)", components[1].Name)
+			assert.InDelta(t, 3*730*0.01+1000*0.01, result.TotalMonthlyCost, 1e-9)
+		}
+	})
+
+	t.Run("does not charge gateway endpoints", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{VPC: VPCUsage{EndpointGBProcessed: 1000}}, "aws_vpc_endpoint", map[string]interface{}{
+			"service_name": "com.amazonaws.us-east-1.s3",
+		})
+		assert.Zero(t, result.TotalMonthlyCost)
+		assert.Equal(t, 1, result.Coverage.PricedResources)
+	})
+
+	t.Run("charges Gateway Load Balancer endpoints by the hour", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{}, "aws_vpc_endpoint", map[string]interface{}{"vpc_endpoint_type": "GatewayLoadBalancer"})
+		assert.InDelta(t, 0.01*730, result.TotalMonthlyCost, 1e-9)
+	})
+
+	t.Run("charges transit gateway attachments and data processed", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{VPC: VPCUsage{TransitGatewayGBProcessed: 500}}, "aws_ec2_transit_gateway_vpc_attachment", map[string]interface{}{})
+		assert.InDelta(t, 0.05*730+500*0.02, result.TotalMonthlyCost, 1e-9)
+	})
+
+	t.Run("charges VPN connections and their transit gateway attachment", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{}, "aws_vpn_connection", map[string]interface{}{"vpn_gateway_id": "vgw-0123"})
+		assert.InDelta(t, 0.05*730, result.TotalMonthlyCost, 1e-9)
+
+		result = estimate(t, &UsageEstimates{}, "aws_vpn_connection", map[string]interface{}{"transit_gateway_id": "tgw-0123"})
+		assert.InDelta(t, 2*0.05*730, result.TotalMonthlyCost, 1e-9)
+	})
+
+	t.Run("charges Network Firewalls per endpoint and for traffic processed", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{VPC: VPCUsage{NetworkFirewallGBProcessed: 2000}}, "aws_networkfirewall_firewall", map[string]interface{}{
+			"subnet_mapping": []interface{}{
+				map[string]interface{}{"subnet_id": "subnet-a"},
+				map[string]interface{}{"subnet_id": "subnet-b"},
+			},
+		})
+		assert.InDelta(t, 2*0.395*730+2000*0.065, result.TotalMonthlyCost, 1e-9)
+	})
+
+	t.Run("skips endpoints of an unknown type", func(t *testing.T) {
+		result := estimate(t, &UsageEstimates{}, "aws_vpc_endpoint", map[string]interface{}{"vpc_endpoint_type": "Resource"})
+		assert.Empty(t, result.Resources)
+		assert.Equal(t, SkipReasonMissingAttribute, result.Skipped[0].Reason)
+	})
+}
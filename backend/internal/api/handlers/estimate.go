@@ -4,9 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 
 	"cloudcostguard/backend/estimator"
+	"cloudcostguard/backend/internal/api/middleware"
 	"cloudcostguard/backend/internal/service"
 	"cloudcostguard/backend/terraform"
 	"go.uber.org/zap"
@@ -50,7 +52,21 @@ func (h *EstimateHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	cost, err := h.estimator.Estimate(plan, region, &requestBody.UsageEstimates)
+	if err := validatePricingModels(requestBody.PricingModels); err != nil {
+		h.logger.Error("Invalid pricing models", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := requestBody.Discounts.Validate(); err != nil {
+		h.logger.Error("Invalid discount policy", zap.Error(err))
+		http.Error(w, fmt.Sprintf("invalid discount policy: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// Private pricing is chosen from the organization of the API key, never from the request.
+	organization := middleware.OrganizationFromContext(r.Context())
+	cost, err := h.estimator.Estimate(&requestBody, region, organization)
 	if err != nil {
         if _, ok := err.(*service.ServiceUnavailableError); ok {
             http.Error(w, err.Error(), http.StatusServiceUnavailable)
@@ -97,3 +113,18 @@ func validatePlan(plan *terraform.Plan) error {
 
 	return nil
 }
+
+func validatePricingModels(pricingModels map[string]estimator.PricingModel) error {
+	resourceTypes := make([]string, 0, len(pricingModels))
+	for resourceType := range pricingModels {
+		resourceTypes = append(resourceTypes, resourceType)
+	}
+	sort.Strings(resourceTypes)
+
+	for _, resourceType := range resourceTypes {
+		if err := pricingModels[resourceType].Validate(); err != nil {
+			return fmt.Errorf("invalid pricing model for %s: %w", resourceType, err)
+		}
+	}
+	return nil
+}
@@ -7,13 +7,14 @@ import (
 	"strings"
 	"testing"
 
+	"cloudcostguard/backend/estimator"
 	"cloudcostguard/backend/internal/service"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 )
 
 func TestEstimateHandler_InputValidation(t *testing.T) {
-	estimatorSvc := service.NewEstimator(nil, zap.NewNop(), nil)
+	estimatorSvc := service.NewEstimator(nil, zap.NewNop(), nil, estimator.Options{}, nil)
 	handler := NewEstimateHandler(estimatorSvc, zap.NewNop())
 
 	// Test with nil plan
@@ -38,4 +39,28 @@ func TestEstimateHandler_InputValidation(t *testing.T) {
 	handler.ServeHTTP(rr3, req3)
 	assert.Equal(t, http.StatusBadRequest, rr3.Code)
 	assert.Contains(t, rr3.Body.String(), "resource address cannot be empty")
+
+	// Test with an invalid pricing model
+	req4, _ := http.NewRequest("POST", "/estimate", bytes.NewBufferString(`{"plan": {"resource_changes": []}, "pricing_models": {"aws_instance": {"term": "reserved", "lease_contract_length": "2yr"}}}`))
+	rr4 := httptest.NewRecorder()
+	handler.ServeHTTP(rr4, req4)
+	assert.Equal(t, http.StatusBadRequest, rr4.Code)
+	assert.Contains(t, rr4.Body.String(), "invalid pricing model for aws_instance")
+
+	// Test with an invalid discount policy
+	req5, _ := http.NewRequest("POST", "/estimate", bytes.NewBufferString(`{"plan": {"resource_changes": []}, "discounts": {"percent": 150}}`))
+	rr5 := httptest.NewRecorder()
+	handler.ServeHTTP(rr5, req5)
+	assert.Equal(t, http.StatusBadRequest, rr5.Code)
+	assert.Contains(t, rr5.Body.String(), "invalid discount policy")
+}
+
+func TestValidatePricingModels(t *testing.T) {
+	assert.NoError(t, validatePricingModels(map[string]estimator.PricingModel{
+		"aws_instance":          {Term: estimator.PricingTermReserved},
+		"aws_autoscaling_group": {Term: estimator.PricingTermSavingsPlan, SavingsPlanType: estimator.SavingsPlanTypeEC2Instance},
+	}))
+	assert.EqualError(t, validatePricingModels(map[string]estimator.PricingModel{
+		"aws_instance": {Term: estimator.PricingTermSavingsPlan, OfferingClass: "standard"},
+	}), `invalid pricing model for aws_instance: offering class requires term "reserved"`)
 }
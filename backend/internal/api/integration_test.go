@@ -11,6 +11,7 @@ import (
 	"testing"
 	"time"
 
+	"cloudcostguard/backend/estimator"
 	"cloudcostguard/backend/internal/api"
 	"cloudcostguard/backend/internal/cache"
 	"cloudcostguard/backend/internal/config"
@@ -68,7 +69,7 @@ func TestEstimateHandler_Integration(t *testing.T) {
 
 	pricingRepo := postgres.NewPricingRepository(db, logger)
 	pricingCache := cache.NewPricingCache(pricingRepo, logger, time.Hour)
-	estimatorSvc := service.NewEstimator(pricingCache, logger, db)
+	estimatorSvc := service.NewEstimator(pricingCache, logger, db, estimator.Options{}, nil)
 	router := api.NewRouter(estimatorSvc, logger, db, pricingCache, apiConfig)
 
 	// Test with no data in DB
@@ -83,6 +84,8 @@ func TestEstimateHandler_Integration(t *testing.T) {
 		sku TEXT PRIMARY KEY,
 		product_json JSONB,
 		terms_json JSONB,
+		reserved_terms_json JSONB,
+		savings_plan_rates_json JSONB,
 		last_updated TIMESTAMPTZ NOT NULL
 	);`)
 	assert.NoError(t, err)
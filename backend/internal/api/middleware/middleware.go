@@ -44,7 +44,8 @@ func LoggingMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
 }
 
 // APIKeyAuthMiddleware checks for a valid API key in the Authorization header.
-func APIKeyAuthMiddleware(validKeys []string) func(http.Handler) http.Handler {
+// The organization the key belongs to, if any, is added to the request context (see OrganizationFromContext).
+func APIKeyAuthMiddleware(validKeys []string, organizations map[string]string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
@@ -73,6 +74,9 @@ func APIKeyAuthMiddleware(validKeys []string) func(http.Handler) http.Handler {
 				return
 			}
 
+			if organization := organizations[apiKey]; organization != "" {
+				r = r.WithContext(context.WithValue(r.Context(), organizationKey, organization))
+			}
 			next.ServeHTTP(w, r)
 		})
 	}
@@ -94,7 +98,17 @@ func RateLimitMiddleware(requestsPerSecond int, burst int) func(http.Handler) ht
 
 type contextKey string
 
-const requestIDKey contextKey = "request_id"
+const (
+	requestIDKey    contextKey = "request_id"
+	organizationKey contextKey = "organization"
+)
+
+// OrganizationFromContext returns the organization of the API key that authenticated a request, or an empty string
+// if the key does not belong to an organization.
+func OrganizationFromContext(ctx context.Context) string {
+	organization, _ := ctx.Value(organizationKey).(string)
+	return organization
+}
 
 // RequestIDMiddleware generates a unique request ID and adds it to the request context.
 func RequestIDMiddleware() func(http.Handler) http.Handler {
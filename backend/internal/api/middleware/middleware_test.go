@@ -77,7 +77,7 @@ func TestAPIKeyAuthMiddleware(t *testing.T) {
 		w.WriteHeader(http.StatusOK)
 	})
 
-	testServer := httptest.NewServer(APIKeyAuthMiddleware([]string{"test-key"})(handler))
+	testServer := httptest.NewServer(APIKeyAuthMiddleware([]string{"test-key"}, nil)(handler))
 	defer testServer.Close()
 
 	// Test with no key
@@ -100,3 +100,30 @@ func TestAPIKeyAuthMiddleware(t *testing.T) {
 	assert.NoError(t, err3)
 	assert.Equal(t, http.StatusOK, resp3.StatusCode)
 }
+
+func TestAPIKeyAuthMiddlewareOrganization(t *testing.T) {
+	var organization string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		organization = OrganizationFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	})
+
+	testServer := httptest.NewServer(APIKeyAuthMiddleware([]string{"org-key", "shared-key"}, map[string]string{"org-key": "my-org"})(handler))
+	defer testServer.Close()
+
+	// Test with a key of an organization
+	req1, _ := http.NewRequest("GET", testServer.URL, nil)
+	req1.Header.Set("Authorization", "Bearer org-key")
+	resp1, err1 := http.DefaultClient.Do(req1)
+	assert.NoError(t, err1)
+	assert.Equal(t, http.StatusOK, resp1.StatusCode)
+	assert.Equal(t, "my-org", organization)
+
+	// Test with a key without an organization
+	req2, _ := http.NewRequest("GET", testServer.URL, nil)
+	req2.Header.Set("Authorization", "Bearer shared-key")
+	resp2, err2 := http.DefaultClient.Do(req2)
+	assert.NoError(t, err2)
+	assert.Equal(t, http.StatusOK, resp2.StatusCode)
+	assert.Empty(t, organization)
+}
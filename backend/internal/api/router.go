@@ -27,7 +27,7 @@ func NewRouter(estimatorSvc *service.Estimator, logger *zap.Logger, db *sql.DB,
 	historyHandler := handlers.NewHistoryHandler(db, logger)
 
 	// Protected estimate route
-	protectedEstimateHandler := middleware.APIKeyAuthMiddleware(apiConfig.APIKeys)(estimateHandler)
+	protectedEstimateHandler := middleware.APIKeyAuthMiddleware(apiConfig.APIKeys, apiConfig.KeyOrganizations)(estimateHandler)
 
 	// Routing
 	router.HandleFunc("/swagger/", httpSwagger.WrapHandler)
@@ -53,6 +53,11 @@ func (pc *PricingCache) Refresh(ctx context.Context) error {
         return err
     }
 
+    // Build the lookup index before the price list is shared, so estimates never scan every product.
+    indexStart := time.Now()
+    priceList.BuildIndex()
+    pc.logger.Info("Pricing index built", zap.Duration("duration", time.Since(indexStart)))
+
     pc.mu.Lock()
     pc.data = priceList
     pc.mu.Unlock()
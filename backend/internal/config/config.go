@@ -11,6 +11,7 @@ type Config struct {
 	Server   ServerConfig
 	Database DatabaseConfig
 	Cache    CacheConfig
+	Pricing  PricingConfig
 	Logging  LoggingConfig
 	API      APIConfig
 }
@@ -26,6 +27,9 @@ type APIConfig struct {
 	RateLimitPerSecond int      `envconfig:"API_RATE_LIMIT_PER_SECOND" default:"10"`
 	RateLimitBurst     int      `envconfig:"API_RATE_LIMIT_BURST" default:"5"`
 	APIKeys            []string `envconfig:"API_KEYS" required:"true"`
+	// KeyOrganizations maps API keys to the GitHub organization they belong to ("key1:my-org,key2:other-org").
+	// Private pricing is only applied to estimates requested with a key of the organization it is configured for.
+	KeyOrganizations map[string]string `envconfig:"API_KEY_ORGANIZATIONS"`
 }
 
 type DatabaseConfig struct {
@@ -44,6 +48,11 @@ type CacheConfig struct {
     RefreshInterval time.Duration `envconfig:"CACHE_REFRESH_INTERVAL" default:"6h"`
 }
 
+type PricingConfig struct {
+    MaxAge             time.Duration `envconfig:"PRICING_MAX_AGE" default:"72h"`
+    DiscountPolicyFile string        `envconfig:"DISCOUNT_POLICY_FILE"`
+}
+
 type LoggingConfig struct {
     Level  string `envconfig:"LOG_LEVEL" default:"info"`
     Format string `envconfig:"LOG_FORMAT" default:"json"`
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"cloudcostguard/backend/pricing"
 	"go.uber.org/zap"
+	"time"
 )
 
 type PricingRepository struct {
@@ -19,7 +20,7 @@ func NewPricingRepository(db *sql.DB, logger *zap.Logger) *PricingRepository {
 }
 
 func (r *PricingRepository) LoadPricing(ctx context.Context) (*pricing.PriceList, error) {
-	rows, err := r.db.QueryContext(ctx, "SELECT sku, product_json, terms_json FROM aws_prices")
+	rows, err := r.db.QueryContext(ctx, "SELECT sku, product_json, terms_json, reserved_terms_json, savings_plan_rates_json, last_updated FROM aws_prices")
 	if err != nil {
 		return nil, fmt.Errorf("cache refresh failed: %w", err)
 	}
@@ -27,14 +28,20 @@ func (r *PricingRepository) LoadPricing(ctx context.Context) (*pricing.PriceList
 
 	newPriceList := pricing.NewPriceList()
 	newPriceList.Terms.OnDemand = make(map[string]map[string]pricing.Term)
+	newPriceList.Terms.Reserved = make(map[string]map[string]pricing.Term)
+	newPriceList.SavingsPlanRates = make(map[string][]pricing.SavingsPlanRate)
 
 	for rows.Next() {
 		var sku string
-		var productJSON, termsJSON []byte
-		if err := rows.Scan(&sku, &productJSON, &termsJSON); err != nil {
+		var productJSON, termsJSON, reservedTermsJSON, savingsPlanRatesJSON []byte
+		var lastUpdated time.Time
+		if err := rows.Scan(&sku, &productJSON, &termsJSON, &reservedTermsJSON, &savingsPlanRatesJSON, &lastUpdated); err != nil {
 			r.logger.Warn("Failed to scan row", zap.Error(err))
 			continue
 		}
+		if lastUpdated.After(newPriceList.LastUpdated) {
+			newPriceList.LastUpdated = lastUpdated
+		}
 
 		var product pricing.Product
 		if err := json.Unmarshal(productJSON, &product); err != nil {
@@ -49,8 +56,54 @@ func (r *PricingRepository) LoadPricing(ctx context.Context) (*pricing.PriceList
 			continue
 		}
 		newPriceList.Terms.OnDemand[sku] = terms
+
+		// Reserved terms and Savings Plan rates are optional and loaded independently, so a product whose reserved
+		// terms cannot be read can still be priced on demand and under a Savings Plan.
+		if len(reservedTermsJSON) > 0 {
+			var reservedTerms map[string]pricing.Term
+			if err := json.Unmarshal(reservedTermsJSON, &reservedTerms); err != nil {
+				r.logger.Warn("Failed to unmarshal reserved terms", zap.String("sku", sku), zap.Error(err))
+			} else {
+				newPriceList.Terms.Reserved[sku] = reservedTerms
+			}
+		}
+
+		if len(savingsPlanRatesJSON) > 0 {
+			var rates []pricing.SavingsPlanRate
+			if err := json.Unmarshal(savingsPlanRatesJSON, &rates); err != nil {
+				r.logger.Warn("Failed to unmarshal Savings Plan rates", zap.String("sku", sku), zap.Error(err))
+			} else {
+				newPriceList.SavingsPlanRates[sku] = rates
+			}
+		}
+	}
+
+	// Offer metadata only describes the snapshot, so estimates can still be made without it.
+	if err := r.loadOffers(ctx, newPriceList); err != nil {
+		r.logger.Warn("Failed to load pricing offers", zap.Error(err))
 	}
 
-	r.logger.Info("Cache refreshed", zap.Int("products_loaded", len(newPriceList.Products)))
+	r.logger.Info("Cache refreshed",
+		zap.Int("products_loaded", len(newPriceList.Products)),
+		zap.Time("last_updated", newPriceList.LastUpdated),
+	)
 	return newPriceList, nil
 }
+
+// loadOffers loads the AWS offer files that the stored prices were fetched from into a price list.
+func (r *PricingRepository) loadOffers(ctx context.Context, priceList *pricing.PriceList) error {
+	rows, err := r.db.QueryContext(ctx, "SELECT offer_code, version, publication_date FROM aws_offers")
+	if err != nil {
+		return fmt.Errorf("failed to query offers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var offer pricing.Offer
+		if err := rows.Scan(&offer.OfferCode, &offer.Version, &offer.PublicationDate); err != nil {
+			return fmt.Errorf("failed to scan offer: %w", err)
+		}
+		priceList.AddOffer(offer)
+	}
+	return rows.Err()
+}
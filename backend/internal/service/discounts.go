@@ -0,0 +1,55 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"cloudcostguard/backend/estimator"
+	"gopkg.in/yaml.v2"
+)
+
+// DiscountPolicies maps GitHub organizations to their discount policies.
+type DiscountPolicies map[string]*estimator.DiscountPolicy
+
+// discountPolicyFile is the structure of the discount policy file.
+type discountPolicyFile struct {
+	Organizations map[string]*estimator.DiscountPolicy `yaml:"organizations"`
+}
+
+// LoadDiscountPolicies loads the per-organization discount policies from a YAML file.
+// An empty path means no organization has a discount policy.
+func LoadDiscountPolicies(path string) (DiscountPolicies, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read discount policy file: %w", err)
+	}
+
+	var file discountPolicyFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse discount policy file: %w", err)
+	}
+
+	policies := make(DiscountPolicies, len(file.Organizations))
+	for organization, policy := range file.Organizations {
+		if err := policy.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid discount policy for %s: %w", organization, err)
+		}
+		policies[strings.ToLower(organization)] = policy
+	}
+	return policies, nil
+}
+
+// For returns the discount policy of an organization, or nil if it has none.
+// The organization must come from the authenticated API key, never from the request, so that private pricing is
+// only disclosed to the organization it belongs to.
+func (p DiscountPolicies) For(organization string) *estimator.DiscountPolicy {
+	if organization == "" {
+		return nil
+	}
+	return p[strings.ToLower(organization)]
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadDiscountPolicies(t *testing.T) {
+	writePolicyFile := func(t *testing.T, content string) string {
+		path := filepath.Join(t.TempDir(), "discounts.yml")
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	t.Run("loads policies keyed by organization", func(t *testing.T) {
+		path := writePolicyFile(t, `
+organizations:
+  My-Org:
+    percent: 5
+    services:
+      AmazonEC2: 10
+    overrides:
+      - instance_type: m5.large
+        unit_price: 0.08
+`)
+		policies, err := LoadDiscountPolicies(path)
+		assert.NoError(t, err)
+
+		policy := policies.For("my-org")
+		if assert.NotNil(t, policy) {
+			assert.Equal(t, 5.0, policy.Percent)
+			assert.Equal(t, 10.0, policy.Services["AmazonEC2"])
+			assert.Equal(t, 0.08, policy.Overrides[0].UnitPrice)
+		}
+		assert.Same(t, policy, policies.For("MY-ORG"))
+		assert.Nil(t, policies.For("other-org"))
+		assert.Nil(t, policies.For(""))
+	})
+
+	t.Run("returns no policies without a file", func(t *testing.T) {
+		policies, err := LoadDiscountPolicies("")
+		assert.NoError(t, err)
+		assert.Nil(t, policies.For("my-org"))
+	})
+
+	t.Run("rejects invalid policies", func(t *testing.T) {
+		path := writePolicyFile(t, `
+organizations:
+  my-org:
+    percent: 150
+`)
+		_, err := LoadDiscountPolicies(path)
+		assert.EqualError(t, err, "invalid discount policy for my-org: discount percentage 150 must be between 0 and 100")
+	})
+
+	t.Run("returns an error for a missing file", func(t *testing.T) {
+		_, err := LoadDiscountPolicies(filepath.Join(t.TempDir(), "missing.yml"))
+		assert.Error(t, err)
+	})
+}
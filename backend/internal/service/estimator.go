@@ -4,7 +4,6 @@ import (
 	"cloudcostguard/backend/estimator"
 	"cloudcostguard/backend/internal/api/middleware"
 	"cloudcostguard/backend/internal/cache"
-	"cloudcostguard/backend/terraform"
 	"database/sql"
 	"go.uber.org/zap"
 	"time"
@@ -14,17 +13,23 @@ type Estimator struct {
 	pricingCache *cache.PricingCache
 	logger       *zap.Logger
 	db           *sql.DB
+	options      estimator.Options
+	discounts    DiscountPolicies
 }
 
-func NewEstimator(pricingCache *cache.PricingCache, logger *zap.Logger, db *sql.DB) *Estimator {
+func NewEstimator(pricingCache *cache.PricingCache, logger *zap.Logger, db *sql.DB, options estimator.Options, discounts DiscountPolicies) *Estimator {
 	return &Estimator{
 		pricingCache: pricingCache,
 		logger:       logger,
 		db:           db,
+		options:      options,
+		discounts:    discounts,
 	}
 }
 
-func (s *Estimator) Estimate(plan *terraform.Plan, region string, usageEstimates *estimator.UsageEstimates) (*estimator.EstimationResponse, error) {
+// Estimate estimates the cost of the plan in an estimate request for the organization of the authenticated API key.
+// The discount policy configured for the organization takes precedence over the one in the request.
+func (s *Estimator) Estimate(request *estimator.EstimateRequest, region, organization string) (*estimator.EstimationResponse, error) {
 	startTime := time.Now()
 	defer func() {
 		middleware.EstimationDuration.Observe(time.Since(startTime).Seconds())
@@ -35,7 +40,13 @@ func (s *Estimator) Estimate(plan *terraform.Plan, region string, usageEstimates
 		s.logger.Error("Pricing data is not available")
 		return nil, &ServiceUnavailableError{"Pricing data is not available"}
 	}
-	return estimator.Estimate(plan, priceList, region, usageEstimates)
+	options := s.options
+	options.PricingModels = request.PricingModels
+	options.Discounts = request.Discounts
+	if discounts := s.discounts.For(organization); discounts != nil {
+		options.Discounts = discounts
+	}
+	return estimator.EstimateWithOptions(request.Plan, priceList, region, &request.UsageEstimates, options)
 }
 
 type ServiceUnavailableError struct {
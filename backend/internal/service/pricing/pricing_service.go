@@ -5,21 +5,26 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"cloudcostguard/backend/estimator"
 	"cloudcostguard/backend/pricing"
 	"time"
 
 	"go.uber.org/zap"
 )
 
-var awsPricingURLs = []string{
-	"https://pricing.us-east-1.amazonaws.com/offers/v1.0/aws/AmazonEC2/current/index.json",
-	"https://pricing.us-east-1.amazonaws.com/offers/v1.0/aws/AmazonRDS/current/index.json",
-	"https://pricing.us-east-1.amazonaws.com/offers/v1.0/aws/AmazonS3/current/index.json",
-	"https://pricing.us-east-1.amazonaws.com/offers/v1.0/aws/AWSELB/current/index.json",
-	"https://pricing.us-east-1.amazonaws.com/offers/v1.0/aws/AmazonEBS/current/index.json",
-	"https://pricing.us-east-1.amazonaws.com/offers/v1.0/aws/AmazonVPC/current/index.json",
-	"https://pricing.us-east-1.amazonaws.com/offers/v1.0/aws/AWSLambda/current/index.json",
-	"https://pricing.us-east-1.amazonaws.com/offers/v1.0/aws/AmazonECS/current/index.json",
+// awsOfferURLTemplate is the URL of the current AWS offer file for a service code.
+const awsOfferURLTemplate = "https://pricing.us-east-1.amazonaws.com/offers/v1.0/aws/%s/current/index.json"
+
+// awsSavingsPlansRegionIndexURL is the URL of the region index of the current AWS Savings Plans offer files.
+const awsSavingsPlansRegionIndexURL = "https://pricing.us-east-1.amazonaws.com/savingsPlan/v1.0/aws/AWSComputeSavingsPlan/current/region_index.json"
+
+// awsPricingURLs returns the offer file URLs for every service code used by a registered calculator.
+func awsPricingURLs() []string {
+	var urls []string
+	for _, code := range estimator.ServiceCodes() {
+		urls = append(urls, fmt.Sprintf(awsOfferURLTemplate, code))
+	}
+	return urls
 }
 
 type PricingDataStorer interface {
@@ -65,16 +70,36 @@ func (s *Service) run(ctx context.Context) {
 func (s *Service) fetchAndStorePricingData(ctx context.Context) error {
 	priceList := pricing.NewPriceList()
 
-	for _, url := range awsPricingURLs {
+	for _, url := range awsPricingURLs() {
 		s.logger.Info("Fetching pricing data", zap.String("url", url))
 		if err := priceList.LoadFromURL(url); err != nil {
 			return fmt.Errorf("failed to load pricing data from %s: %w", url, err)
 		}
 	}
 
+	// Savings Plan rates are optional; resources can still be priced on demand and under reserved terms without them.
+	if err := s.loadSavingsPlans(priceList); err != nil {
+		s.logger.Warn("Failed to load Savings Plans", zap.Error(err))
+	}
+
 	return s.storer.StorePricingData(ctx, priceList)
 }
 
+// loadSavingsPlans fetches the Savings Plans offer file of every region and merges their rates into a price list.
+func (s *Service) loadSavingsPlans(priceList *pricing.PriceList) error {
+	urls, err := pricing.SavingsPlanOfferURLs(awsSavingsPlansRegionIndexURL)
+	if err != nil {
+		return err
+	}
+	for _, url := range urls {
+		s.logger.Info("Fetching Savings Plans", zap.String("url", url))
+		if err := priceList.LoadSavingsPlansFromURL(url); err != nil {
+			return fmt.Errorf("failed to load Savings Plans from %s: %w", url, err)
+		}
+	}
+	return nil
+}
+
 type PostgresPricingDataStorer struct {
 	db *sql.DB
 }
@@ -91,11 +116,13 @@ func (s *PostgresPricingDataStorer) StorePricingData(ctx context.Context, priceL
 	defer tx.Rollback()
 
 	stmt, err := tx.PrepareContext(ctx, `
-		INSERT INTO aws_prices (sku, product_json, terms_json, last_updated)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO aws_prices (sku, product_json, terms_json, reserved_terms_json, savings_plan_rates_json, last_updated)
+		VALUES ($1, $2, $3, $4, $5, $6)
 		ON CONFLICT (sku) DO UPDATE SET
 			product_json = EXCLUDED.product_json,
 			terms_json = EXCLUDED.terms_json,
+			reserved_terms_json = EXCLUDED.reserved_terms_json,
+			savings_plan_rates_json = EXCLUDED.savings_plan_rates_json,
 			last_updated = EXCLUDED.last_updated
 	`)
 	if err != nil {
@@ -117,10 +144,39 @@ func (s *PostgresPricingDataStorer) StorePricingData(ctx context.Context, priceL
 			return fmt.Errorf("failed to marshal terms for SKU %s: %w", sku, err)
 		}
 
-		if _, err := stmt.ExecContext(ctx, sku, productJSON, termsJSON, now); err != nil {
+		var reservedTermsJSON []byte
+		if reservedTerms, ok := priceList.Terms.Reserved[sku]; ok {
+			reservedTermsJSON, err = json.Marshal(reservedTerms)
+			if err != nil {
+				return fmt.Errorf("failed to marshal reserved terms for SKU %s: %w", sku, err)
+			}
+		}
+
+		var savingsPlanRatesJSON []byte
+		if rates, ok := priceList.SavingsPlanRates[sku]; ok {
+			savingsPlanRatesJSON, err = json.Marshal(rates)
+			if err != nil {
+				return fmt.Errorf("failed to marshal Savings Plan rates for SKU %s: %w", sku, err)
+			}
+		}
+
+		if _, err := stmt.ExecContext(ctx, sku, productJSON, termsJSON, reservedTermsJSON, savingsPlanRatesJSON, now); err != nil {
 			return fmt.Errorf("failed to execute statement for SKU %s: %w", sku, err)
 		}
 	}
 
+	for _, offer := range priceList.SortedOffers() {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO aws_offers (offer_code, version, publication_date, last_updated)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (offer_code) DO UPDATE SET
+				version = EXCLUDED.version,
+				publication_date = EXCLUDED.publication_date,
+				last_updated = EXCLUDED.last_updated
+		`, offer.OfferCode, offer.Version, offer.PublicationDate, now); err != nil {
+			return fmt.Errorf("failed to store offer %s: %w", offer.OfferCode, err)
+		}
+	}
+
 	return tx.Commit()
 }
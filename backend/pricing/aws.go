@@ -6,22 +6,46 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // PriceList holds the pricing data for all supported AWS services.
 type PriceList struct {
 	// Products is a map of SKU to Product.
 	Products map[string]Product `json:"products"`
-	// Terms is a map of terms, with OnDemand being the only one we care about.
-	Terms    struct {
+	// Terms holds the on-demand and reserved terms of the products, keyed by SKU and then by term key.
+	Terms struct {
 		OnDemand map[string]map[string]Term `json:"OnDemand"`
+		Reserved map[string]map[string]Term `json:"Reserved,omitempty"`
 	} `json:"terms"`
+	// SavingsPlanRates holds the Savings Plan rates of the products, keyed by SKU. They are loaded from the separate
+	// Savings Plans offer files, which have a different format.
+	SavingsPlanRates map[string][]SavingsPlanRate `json:"-"`
+	// OfferCode is the offer code of an AWS offer file (e.g., "AmazonEC2").
+	OfferCode string `json:"offerCode,omitempty"`
+	// Version is the version of an AWS offer file.
+	Version string `json:"version,omitempty"`
+	// PublicationDate is the time an AWS offer file was published, in RFC 3339 format.
+	PublicationDate string `json:"publicationDate,omitempty"`
+	// Offers lists the AWS offer files merged into the price list, keyed by offer code.
+	Offers map[string]Offer `json:"-"`
+	// LastUpdated is when the prices were last fetched from AWS. It is zero if unknown.
+	LastUpdated time.Time `json:"-"`
+
+	// index is published atomically so that lookups never take a lock. indexMu only serializes building it.
+	indexMu sync.Mutex
+	index   atomic.Pointer[Index]
 }
 
 // Product represents a single product in the AWS catalog.
 type Product struct {
 	// SKU is the unique identifier for the product.
-	SKU        string            `json:"sku"`
+	SKU string `json:"sku"`
+	// ProductFamily is the family of a product (e.g., "Storage" or "Provisioned Throughput").
+	ProductFamily string `json:"productFamily"`
 	// Attributes contains the detailed attributes of a product.
 	Attributes ProductAttributes `json:"attributes"`
 }
@@ -29,40 +53,108 @@ type Product struct {
 // ProductAttributes contains the detailed attributes of a product.
 type ProductAttributes struct {
 	// ServiceCode is the AWS service code (e.g., "AmazonEC2").
-	ServiceCode     string `json:"servicecode"`
+	ServiceCode string `json:"servicecode"`
 	// InstanceType is the EC2 instance type (e.g., "t2.micro").
-	InstanceType    string `json:"instanceType"`
+	InstanceType string `json:"instanceType"`
 	// InstanceClass is the RDS instance class (e.g., "db.t2.micro").
-	InstanceClass   string `json:"instanceClass"`
+	InstanceClass string `json:"instanceClass"`
 	// Location is the AWS region (e.g., "US East (N. Virginia)").
-	Location        string `json:"location"`
+	Location string `json:"location"`
 	// OperatingSystem is the operating system (e.g., "Linux").
 	OperatingSystem string `json:"operatingSystem"`
+	// Tenancy is the EC2 tenancy: "Shared", "Dedicated" or "Host".
+	Tenancy string `json:"tenancy"`
+	// PreInstalledSw is the software pre-installed on an EC2 instance (e.g., "NA" or "SQL Std").
+	PreInstalledSw string `json:"preInstalledSw"`
+	// LicenseModel is the license model of an EC2 instance or RDS database (e.g., "No License required" or "License included").
+	LicenseModel string `json:"licenseModel"`
+	// CapacityStatus distinguishes running instances ("Used") from unused capacity reservations.
+	CapacityStatus string `json:"capacitystatus"`
 	// UsageType is the usage type (e.g., "BoxUsage:t2.micro").
-	UsageType       string `json:"usagetype"`
+	UsageType string `json:"usagetype"`
 	// VolumeAPIName is the EBS volume type (e.g., "gp2").
-	VolumeAPIName   string `json:"volumeApiName"`
+	VolumeAPIName string `json:"volumeApiName"`
 	// Group is the ELB group (e.g., "ELB-Application").
-	Group           string `json:"group"`
+	Group string `json:"group"`
+	// Operation is the operation a product is charged for (e.g., "LoadBalancing:Network" for Network Load Balancers).
+	Operation string `json:"operation"`
 	// StorageClass is the S3 storage class (e.g., "General Purpose").
-	StorageClass    string `json:"storageClass"`
+	StorageClass string `json:"storageClass"`
+	// DatabaseEngine is the RDS database engine (e.g., "PostgreSQL" or "Oracle"), or "Any" for storage shared by all engines.
+	DatabaseEngine string `json:"databaseEngine"`
+	// DatabaseEdition is the edition of a commercial RDS database engine (e.g., "Standard Two" or "Express").
+	DatabaseEdition string `json:"databaseEdition"`
+	// DeploymentOption is the RDS deployment option (e.g., "Single-AZ" or "Multi-AZ").
+	DeploymentOption string `json:"deploymentOption"`
+	// VolumeType is the RDS storage volume type (e.g., "General Purpose" or "Provisioned IOPS").
+	VolumeType string `json:"volumeType"`
+	// CacheEngine is the ElastiCache engine (e.g., "Redis", "Valkey" or "Memcached").
+	CacheEngine string `json:"cacheEngine"`
+	// FileSystemType is the FSx file system type (e.g., "Lustre", "ONTAP", "Windows" or "OpenZFS").
+	FileSystemType string `json:"fileSystemType"`
+	// StorageType is the FSx storage type: "SSD" or "HDD".
+	StorageType string `json:"storageType"`
+	// ThroughputCapacity is the throughput per TiB of storage of an FSx for Lustre file system, in MB/s (e.g., "125").
+	ThroughputCapacity string `json:"throughputCapacity"`
+	// TransferType is the kind of data transfer (e.g., "AWS Outbound", "InterRegion Outbound" or "IntraRegion").
+	TransferType string `json:"transferType"`
+	// FromLocation is the AWS region data is transferred from (e.g., "US East (N. Virginia)").
+	FromLocation string `json:"fromLocation"`
+	// ToLocation is the AWS region data is transferred to, or "External" for the internet.
+	ToLocation string `json:"toLocation"`
+}
+
+// Offer identifies a single AWS offer file that a price list was built from.
+type Offer struct {
+	// OfferCode is the offer code of the offer file (e.g., "AmazonEC2").
+	OfferCode string
+	// Version is the version of the offer file.
+	Version string
+	// PublicationDate is the time the offer file was published by AWS.
+	PublicationDate time.Time
 }
 
 // Term represents the pricing terms for a product.
 type Term struct {
+	// OfferTermCode is the code of the term (e.g., "JRTCKXETXF" for on-demand).
+	OfferTermCode string `json:"offerTermCode,omitempty"`
+	// EffectiveDate is the date the term became effective, in RFC 3339 format.
+	EffectiveDate string `json:"effectiveDate,omitempty"`
 	// PriceDimensions is a map of price dimensions.
 	PriceDimensions map[string]PriceDimension `json:"priceDimensions"`
+	// TermAttributes describes the commitment of a reserved term. It is empty for on-demand terms.
+	TermAttributes TermAttributes `json:"termAttributes,omitempty"`
+}
+
+// TermAttributes describes the commitment of a reserved term.
+type TermAttributes struct {
+	// LeaseContractLength is the length of the commitment: "1yr" or "3yr".
+	LeaseContractLength string `json:"LeaseContractLength,omitempty"`
+	// PurchaseOption is how the commitment is paid: "No Upfront", "Partial Upfront" or "All Upfront".
+	PurchaseOption string `json:"PurchaseOption,omitempty"`
+	// OfferingClass is the offering class of the reservation: "standard" or "convertible".
+	OfferingClass string `json:"OfferingClass,omitempty"`
 }
 
 // PriceDimension represents a single dimension of pricing for a product.
+// Tiered prices are published as one dimension per tier, each covering a range of the monthly quantity.
 type PriceDimension struct {
+	// RateCode is the unique identifier of the price dimension.
+	RateCode string `json:"rateCode,omitempty"`
+	// Description describes the price dimension (e.g., "$0.023 per GB - first 50 TB / month of storage used").
+	Description string `json:"description,omitempty"`
+	// BeginRange is the start of the quantity range the price applies to (e.g., "0").
+	BeginRange string `json:"beginRange,omitempty"`
+	// EndRange is the end of the quantity range the price applies to, or "Inf" if the range is unbounded.
+	EndRange string `json:"endRange,omitempty"`
+	// Unit is the unit the price is quoted in (e.g., "Hrs" or "GB-Mo").
+	Unit string `json:"unit,omitempty"`
 	// PricePerUnit is a map of currency to price.
 	PricePerUnit struct {
 		USD string `json:"USD"`
 	} `json:"pricePerUnit"`
 }
 
-
 // NewPriceList creates a new, empty price list.
 //
 // Returns:
@@ -100,7 +192,6 @@ func (p *PriceList) LoadFromURL(url string) error {
 	return nil
 }
 
-
 // LoadFromFile loads a pricing file from a local path and merges it into the price list.
 //
 // Parameters:
@@ -138,4 +229,109 @@ func (p *PriceList) Merge(other *PriceList) {
 	for sku, terms := range other.Terms.OnDemand {
 		p.Terms.OnDemand[sku] = terms
 	}
+	if len(other.Terms.Reserved) > 0 && p.Terms.Reserved == nil {
+		p.Terms.Reserved = make(map[string]map[string]Term)
+	}
+	for sku, terms := range other.Terms.Reserved {
+		p.Terms.Reserved[sku] = terms
+	}
+	if len(other.SavingsPlanRates) > 0 && p.SavingsPlanRates == nil {
+		p.SavingsPlanRates = make(map[string][]SavingsPlanRate)
+	}
+	for sku, rates := range other.SavingsPlanRates {
+		p.SavingsPlanRates[sku] = rates
+	}
+	if offer, ok := other.offer(); ok {
+		p.AddOffer(offer)
+	}
+	for _, offer := range other.Offers {
+		p.AddOffer(offer)
+	}
+	if other.LastUpdated.After(p.LastUpdated) {
+		p.LastUpdated = other.LastUpdated
+	}
+
+	p.index.Store(nil)
+}
+
+// BuildIndex builds the lookup index for the products currently in the price list.
+// It should be called once the price list is fully loaded, before it is shared between goroutines.
+func (p *PriceList) BuildIndex() {
+	p.indexMu.Lock()
+	defer p.indexMu.Unlock()
+	p.index.Store(NewIndex(p))
+}
+
+// Index returns the lookup index for the price list, building it on first use.
+// Once built, the index is read without locking, so concurrent estimates do not contend on it.
+//
+// Returns:
+//   A pointer to the Index for the products in the price list.
+func (p *PriceList) Index() *Index {
+	if idx := p.index.Load(); idx != nil {
+		return idx
+	}
+	p.indexMu.Lock()
+	defer p.indexMu.Unlock()
+	if idx := p.index.Load(); idx != nil {
+		return idx
+	}
+	idx := NewIndex(p)
+	p.index.Store(idx)
+	return idx
+}
+
+// AddOffer records an offer file that the price list was built from, replacing any offer with the same offer code.
+//
+// Parameters:
+//   offer: The offer to record.
+func (p *PriceList) AddOffer(offer Offer) {
+	if p.Offers == nil {
+		p.Offers = make(map[string]Offer)
+	}
+	p.Offers[offer.OfferCode] = offer
+}
+
+// SortedOffers returns the offer files that the price list was built from, ordered by offer code.
+//
+// Returns:
+//   A slice of the offers in the price list.
+func (p *PriceList) SortedOffers() []Offer {
+	offers := make([]Offer, 0, len(p.Offers))
+	for _, offer := range p.Offers {
+		offers = append(offers, offer)
+	}
+	sort.Slice(offers, func(i, j int) bool {
+		return offers[i].OfferCode < offers[j].OfferCode
+	})
+	return offers
+}
+
+// offer returns the offer described by the top-level fields of a decoded offer file.
+// When the file has no offer code, the service code of its products is used instead.
+//
+// Returns:
+//   The offer, and true if the price list has a valid publication date.
+func (p *PriceList) offer() (Offer, bool) {
+	if p.PublicationDate == "" {
+		return Offer{}, false
+	}
+	publicationDate, err := time.Parse(time.RFC3339, p.PublicationDate)
+	if err != nil {
+		return Offer{}, false
+	}
+
+	offerCode := p.OfferCode
+	if offerCode == "" {
+		skus := make([]string, 0, len(p.Products))
+		for sku := range p.Products {
+			skus = append(skus, sku)
+		}
+		sort.Strings(skus)
+		if len(skus) > 0 {
+			offerCode = p.Products[skus[0]].Attributes.ServiceCode
+		}
+	}
+
+	return Offer{OfferCode: offerCode, Version: p.Version, PublicationDate: publicationDate}, true
 }
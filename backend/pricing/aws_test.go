@@ -3,6 +3,7 @@ package pricing
 import (
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestLoadFromFile(t *testing.T) {
@@ -30,9 +31,59 @@ func TestLoadFromFile(t *testing.T) {
 		assert.Equal(t, "0.0116000000", priceDimension.PricePerUnit.USD)
 	})
 
+	t.Run("records the offer publication date and term codes", func(t *testing.T) {
+		priceList := NewPriceList()
+		err := priceList.LoadFromFile("../../testdata/sample-pricing.json")
+		assert.NoError(t, err)
+
+		assert.Equal(t, []Offer{
+			{OfferCode: "AmazonEC2", PublicationDate: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		}, priceList.SortedOffers())
+
+		term := priceList.Terms.OnDemand["JRTCKXETXF"]["JRTCKXETXF.JRTCKXETXF"]
+		assert.Equal(t, "JRTCKXETXF", term.OfferTermCode)
+		assert.Equal(t, "2018-08-01T00:00:00Z", term.EffectiveDate)
+	})
+
 	t.Run("returns error for non-existent file", func(t *testing.T) {
 		priceList := NewPriceList()
 		err := priceList.LoadFromFile("non-existent-file.json")
 		assert.Error(t, err)
 	})
 }
+
+func TestMerge(t *testing.T) {
+	t.Run("merges offers and keeps the latest update time", func(t *testing.T) {
+		older := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+		newer := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+		priceList := NewPriceList()
+		priceList.LastUpdated = newer
+
+		other := NewPriceList()
+		other.OfferCode = "AmazonS3"
+		other.Version = "20240601000000"
+		other.PublicationDate = "2024-06-01T00:00:00Z"
+		other.LastUpdated = older
+		other.AddOffer(Offer{OfferCode: "AmazonEC2", PublicationDate: older})
+
+		priceList.Merge(other)
+
+		assert.Equal(t, []Offer{
+			{OfferCode: "AmazonEC2", PublicationDate: older},
+			{OfferCode: "AmazonS3", Version: "20240601000000", PublicationDate: newer},
+		}, priceList.SortedOffers())
+		assert.Equal(t, newer, priceList.LastUpdated)
+	})
+
+	t.Run("ignores offer files without a valid publication date", func(t *testing.T) {
+		priceList := NewPriceList()
+		other := NewPriceList()
+		other.OfferCode = "AmazonS3"
+		other.PublicationDate = "yesterday"
+
+		priceList.Merge(other)
+
+		assert.Empty(t, priceList.SortedOffers())
+	})
+}
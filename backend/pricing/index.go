@@ -0,0 +1,190 @@
+package pricing
+
+import (
+	"sort"
+	"strings"
+)
+
+// Attribute identifies a product attribute that price lookups can be keyed on.
+type Attribute string
+
+const (
+	// AttrInstanceType indexes products by EC2 or ElastiCache instance type.
+	AttrInstanceType Attribute = "instanceType"
+	// AttrInstanceClass indexes products by RDS instance class.
+	AttrInstanceClass Attribute = "instanceClass"
+	// AttrVolumeAPIName indexes products by EBS volume API name.
+	AttrVolumeAPIName Attribute = "volumeApiName"
+	// AttrUsageType indexes products by usage type.
+	AttrUsageType Attribute = "usagetype"
+	// AttrGroup indexes products by group.
+	AttrGroup Attribute = "group"
+	// AttrVolumeType indexes products by RDS storage volume type.
+	AttrVolumeType Attribute = "volumeType"
+	// AttrFileSystemType indexes products by FSx file system type.
+	AttrFileSystemType Attribute = "fileSystemType"
+	// AttrFromLocation indexes data transfer products by the location data is transferred from.
+	AttrFromLocation Attribute = "fromLocation"
+	// AttrUsageTypeName indexes products by usage type with and without its region prefix, so that a lookup of
+	// "TimedStorage-ByteHrs" matches both "TimedStorage-ByteHrs" and "USE2-TimedStorage-ByteHrs".
+	AttrUsageTypeName Attribute = "usagetypeName"
+)
+
+// indexedAttributes is the list of attributes included in every index.
+var indexedAttributes = []Attribute{
+	AttrInstanceType,
+	AttrInstanceClass,
+	AttrVolumeAPIName,
+	AttrUsageType,
+	AttrGroup,
+	AttrVolumeType,
+	AttrFileSystemType,
+	AttrFromLocation,
+	AttrUsageTypeName,
+}
+
+// value returns the value of an indexed attribute.
+func (a ProductAttributes) value(attr Attribute) string {
+	switch attr {
+	case AttrInstanceType:
+		return a.InstanceType
+	case AttrInstanceClass:
+		return a.InstanceClass
+	case AttrVolumeAPIName:
+		return a.VolumeAPIName
+	case AttrUsageType:
+		return a.UsageType
+	case AttrGroup:
+		return a.Group
+	case AttrVolumeType:
+		return a.VolumeType
+	case AttrFileSystemType:
+		return a.FileSystemType
+	case AttrFromLocation:
+		return a.FromLocation
+	case AttrUsageTypeName:
+		return a.UsageType
+	default:
+		return ""
+	}
+}
+
+// regionPrefixes is the set of prefixes that AWS adds to the usage types of products outside us-east-1, e.g.
+// "USE2" for us-east-2 and "EU" for eu-west-1, and to the usage types of CloudFront edge regions, e.g. "JP" for Japan.
+var regionPrefixes = map[string]bool{
+	"USE1": true, "USE2": true, "USW1": true, "USW2": true, "UGE1": true, "UGW1": true,
+	"CAN1": true, "CAW1": true, "SAE1": true, "AFS1": true, "ILC1": true, "MES1": true, "MEC1": true,
+	"EU": true, "EUW2": true, "EUW3": true, "EUC1": true, "EUC2": true, "EUN1": true, "EUS1": true, "EUS2": true,
+	"APE1": true, "APN1": true, "APN2": true, "APN3": true,
+	"APS1": true, "APS2": true, "APS3": true, "APS4": true, "APS5": true, "APS6": true,
+	"US": true, "CA": true, "SA": true, "ZA": true, "ME": true, "IN": true, "JP": true, "AU": true, "AP": true,
+}
+
+// TrimRegionPrefix removes the region prefix from a usage type, e.g. "USE2-" from "USE2-TimedStorage-ByteHrs".
+// Only the prefixes of known regions are removed, so usage types that start with an acronym, such as
+// "IA-ReadCapacityUnit-Hrs" or "SNS-Requests-Tier1", are kept as they are.
+//
+// Parameters:
+//   usageType: The usage type of a product.
+//
+// Returns:
+//   The usage type without its region prefix, and true if it had one.
+func TrimRegionPrefix(usageType string) (string, bool) {
+	prefix, name, ok := strings.Cut(usageType, "-")
+	if !ok || name == "" || !regionPrefixes[prefix] {
+		return usageType, false
+	}
+	return name, true
+}
+
+type locationKey struct {
+	serviceCode string
+	location    string
+}
+
+type attributeKey struct {
+	locationKey
+	attribute Attribute
+	value     string
+}
+
+// Index is a read-only lookup structure over the products of a price list.
+// Products are grouped by service code and location, and further by the value of each indexed attribute,
+// so that a lookup costs a single map access instead of a scan of every product.
+// SKUs are returned in sorted order.
+type Index struct {
+	byLocation  map[locationKey][]string
+	byAttribute map[attributeKey][]string
+}
+
+// NewIndex builds an index over the products of a price list.
+//
+// Parameters:
+//   priceList: The price list to index.
+//
+// Returns:
+//   A pointer to the new Index.
+func NewIndex(priceList *PriceList) *Index {
+	idx := &Index{
+		byLocation:  make(map[locationKey][]string),
+		byAttribute: make(map[attributeKey][]string),
+	}
+
+	for sku, product := range priceList.Products {
+		attr := product.Attributes
+		lk := locationKey{serviceCode: attr.ServiceCode, location: attr.Location}
+		idx.byLocation[lk] = append(idx.byLocation[lk], sku)
+
+		for _, a := range indexedAttributes {
+			v := attr.value(a)
+			if v == "" {
+				continue
+			}
+			ak := attributeKey{locationKey: lk, attribute: a, value: v}
+			idx.byAttribute[ak] = append(idx.byAttribute[ak], sku)
+		}
+		if name, ok := TrimRegionPrefix(attr.UsageType); ok {
+			ak := attributeKey{locationKey: lk, attribute: AttrUsageTypeName, value: name}
+			idx.byAttribute[ak] = append(idx.byAttribute[ak], sku)
+		}
+	}
+
+	for _, skus := range idx.byLocation {
+		sort.Strings(skus)
+	}
+	for _, skus := range idx.byAttribute {
+		sort.Strings(skus)
+	}
+
+	return idx
+}
+
+// Products returns the SKUs of every product for a service in a location.
+//
+// Parameters:
+//   serviceCode: The AWS service code (e.g., "AmazonEC2").
+//   location: The AWS pricing location (e.g., "US East (N. Virginia)").
+//
+// Returns:
+//   The sorted SKUs of the matching products. The slice must not be modified.
+func (i *Index) Products(serviceCode, location string) []string {
+	return i.byLocation[locationKey{serviceCode: serviceCode, location: location}]
+}
+
+// Lookup returns the SKUs of the products for a service in a location whose attribute equals a value.
+//
+// Parameters:
+//   serviceCode: The AWS service code (e.g., "AmazonEC2").
+//   location: The AWS pricing location (e.g., "US East (N. Virginia)").
+//   attribute: The indexed attribute to match on.
+//   value: The attribute value to match.
+//
+// Returns:
+//   The sorted SKUs of the matching products. The slice must not be modified.
+func (i *Index) Lookup(serviceCode, location string, attribute Attribute, value string) []string {
+	return i.byAttribute[attributeKey{
+		locationKey: locationKey{serviceCode: serviceCode, location: location},
+		attribute:   attribute,
+		value:       value,
+	}]
+}
@@ -0,0 +1,150 @@
+package pricing
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestPriceList() *PriceList {
+	priceList := NewPriceList()
+	priceList.Products["b-sku"] = Product{SKU: "b-sku", Attributes: ProductAttributes{ServiceCode: "AmazonEC2", Location: "US East (N. Virginia)", InstanceType: "t2.micro"}}
+	priceList.Products["a-sku"] = Product{SKU: "a-sku", Attributes: ProductAttributes{ServiceCode: "AmazonEC2", Location: "US East (N. Virginia)", InstanceType: "t2.micro"}}
+	priceList.Products["c-sku"] = Product{SKU: "c-sku", Attributes: ProductAttributes{ServiceCode: "AmazonEC2", Location: "EU (Ireland)", InstanceType: "t2.micro"}}
+	priceList.Products["d-sku"] = Product{SKU: "d-sku", Attributes: ProductAttributes{ServiceCode: "AmazonEC2", Location: "US East (N. Virginia)", VolumeAPIName: "gp3"}}
+	return priceList
+}
+
+func TestIndex(t *testing.T) {
+	t.Run("looks up products by service, location and attribute", func(t *testing.T) {
+		idx := NewIndex(newTestPriceList())
+
+		assert.Equal(t, []string{"a-sku", "b-sku"}, idx.Lookup("AmazonEC2", "US East (N. Virginia)", AttrInstanceType, "t2.micro"))
+		assert.Equal(t, []string{"c-sku"}, idx.Lookup("AmazonEC2", "EU (Ireland)", AttrInstanceType, "t2.micro"))
+		assert.Equal(t, []string{"d-sku"}, idx.Lookup("AmazonEC2", "US East (N. Virginia)", AttrVolumeAPIName, "gp3"))
+		assert.Empty(t, idx.Lookup("AmazonRDS", "US East (N. Virginia)", AttrInstanceType, "t2.micro"))
+	})
+
+	t.Run("looks up products by usage type with or without a region prefix", func(t *testing.T) {
+		priceList := NewPriceList()
+		for sku, usageType := range map[string]string{
+			"us-east-1":    "ReadCapacityUnit-Hrs",
+			"us-east-2":    "USE2-ReadCapacityUnit-Hrs",
+			"ia":           "IA-ReadCapacityUnit-Hrs",
+			"us-east-2-ia": "USE2-IA-ReadCapacityUnit-Hrs",
+			"streams":      "Streams-ReadRequestUnits",
+			"us-east-2-wr": "USE2-WriteRequestUnits",
+			"repl":         "ReplWriteRequestUnits",
+			"sns":          "SNS-Requests-Tier1",
+		} {
+			priceList.Products[sku] = Product{SKU: sku, Attributes: ProductAttributes{ServiceCode: "AmazonDynamoDB", Location: "US East (N. Virginia)", UsageType: usageType}}
+		}
+		idx := NewIndex(priceList)
+		lookup := func(usageType string) []string {
+			return idx.Lookup("AmazonDynamoDB", "US East (N. Virginia)", AttrUsageTypeName, usageType)
+		}
+
+		assert.Equal(t, []string{"us-east-1", "us-east-2"}, lookup("ReadCapacityUnit-Hrs"))
+		assert.Equal(t, []string{"ia", "us-east-2-ia"}, lookup("IA-ReadCapacityUnit-Hrs"))
+		assert.Equal(t, []string{"streams"}, lookup("Streams-ReadRequestUnits"))
+		assert.Empty(t, lookup("ReadRequestUnits"))
+		assert.Equal(t, []string{"us-east-2-wr"}, lookup("WriteRequestUnits"))
+		assert.Equal(t, []string{"sns"}, lookup("SNS-Requests-Tier1"))
+		assert.Empty(t, lookup("Requests-Tier1"))
+	})
+
+	t.Run("lists products by service and location", func(t *testing.T) {
+		idx := NewIndex(newTestPriceList())
+		assert.Equal(t, []string{"a-sku", "b-sku", "d-sku"}, idx.Products("AmazonEC2", "US East (N. Virginia)"))
+	})
+
+	t.Run("rebuilds the index after a merge", func(t *testing.T) {
+		priceList := newTestPriceList()
+		priceList.BuildIndex()
+		assert.Len(t, priceList.Index().Products("AmazonEC2", "EU (Ireland)"), 1)
+
+		other := NewPriceList()
+		other.Products["e-sku"] = Product{SKU: "e-sku", Attributes: ProductAttributes{ServiceCode: "AmazonEC2", Location: "EU (Ireland)", InstanceType: "t2.small"}}
+		priceList.Merge(other)
+
+		assert.Equal(t, []string{"c-sku", "e-sku"}, priceList.Index().Products("AmazonEC2", "EU (Ireland)"))
+	})
+
+	t.Run("builds a single index for concurrent readers", func(t *testing.T) {
+		priceList := newTestPriceList()
+		indexes := make([]*Index, 8)
+		var wg sync.WaitGroup
+		for i := range indexes {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				indexes[i] = priceList.Index()
+			}()
+		}
+		wg.Wait()
+
+		for _, idx := range indexes {
+			assert.Same(t, indexes[0], idx)
+		}
+	})
+}
+
+func TestTrimRegionPrefix(t *testing.T) {
+	tests := []struct {
+		usageType string
+		want      string
+		trimmed   bool
+	}{
+		{"USE2-ReadCapacityUnit-Hrs", "ReadCapacityUnit-Hrs", true},
+		{"EU-DataTransfer-Out-Bytes", "DataTransfer-Out-Bytes", true},
+		{"USE2-IA-ReadCapacityUnit-Hrs", "IA-ReadCapacityUnit-Hrs", true},
+		{"APS3-TimedStorage-ByteHrs", "TimedStorage-ByteHrs", true},
+		{"JP-Requests-Tier2-HTTPS", "Requests-Tier2-HTTPS", true},
+		{"IA-ReadCapacityUnit-Hrs", "IA-ReadCapacityUnit-Hrs", false},
+		{"SNS-Requests-Tier1", "SNS-Requests-Tier1", false},
+		{"CW-Requests", "CW-Requests", false},
+		{"EBS-SnapshotUsage", "EBS-SnapshotUsage", false},
+		{"USE2-SNS-Requests-Tier1", "SNS-Requests-Tier1", true},
+		{"ReadCapacityUnit-Hrs", "ReadCapacityUnit-Hrs", false},
+		{"Streams-ReadRequestUnits", "Streams-ReadRequestUnits", false},
+		{"RDS:GP2-Storage", "RDS:GP2-Storage", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, trimmed := TrimRegionPrefix(tt.usageType)
+		assert.Equal(t, tt.want, got, tt.usageType)
+		assert.Equal(t, tt.trimmed, trimmed, tt.usageType)
+	}
+}
+
+func BenchmarkPriceListIndexParallel(b *testing.B) {
+	priceList := newTestPriceList()
+	priceList.BuildIndex()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			priceList.Index().Lookup("AmazonEC2", "US East (N. Virginia)", AttrInstanceType, "t2.micro")
+		}
+	})
+}
+
+func BenchmarkNewIndex(b *testing.B) {
+	priceList := NewPriceList()
+	for i := 0; i < 200000; i++ {
+		sku := fmt.Sprintf("sku-%d", i)
+		priceList.Products[sku] = Product{SKU: sku, Attributes: ProductAttributes{
+			ServiceCode:  "AmazonEC2",
+			Location:     fmt.Sprintf("location-%d", i%20),
+			InstanceType: fmt.Sprintf("type-%d", i%5000),
+			UsageType:    fmt.Sprintf("BoxUsage:type-%d", i%5000),
+		}}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewIndex(priceList)
+	}
+}
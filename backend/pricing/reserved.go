@@ -0,0 +1,105 @@
+package pricing
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ReservedPrice is the price of a product under a reserved term.
+type ReservedPrice struct {
+	// OfferTermCode is the code of the reserved term.
+	OfferTermCode string
+	// TermAttributes describes the commitment of the reserved term.
+	TermAttributes TermAttributes
+	// UpfrontFee is the fee paid once at the start of the commitment, in USD.
+	UpfrontFee float64
+	// HourlyRate is the recurring price per hour, in USD.
+	HourlyRate float64
+}
+
+// LeaseHours returns the number of hours in the commitment of the reserved price.
+//
+// Returns:
+//   The number of hours in the lease contract, or 0 if the lease contract length is unknown.
+func (r ReservedPrice) LeaseHours() float64 {
+	years, err := strconv.Atoi(strings.TrimSuffix(r.TermAttributes.LeaseContractLength, "yr"))
+	if err != nil {
+		return 0
+	}
+	return float64(years) * 8760
+}
+
+// EffectiveHourlyRate returns the hourly rate with the upfront fee amortized over the commitment.
+//
+// Returns:
+//   The effective hourly rate in USD.
+func (r ReservedPrice) EffectiveHourlyRate() float64 {
+	hours := r.LeaseHours()
+	if hours == 0 {
+		return r.HourlyRate
+	}
+	return r.HourlyRate + r.UpfrontFee/hours
+}
+
+// FindReservedPrice returns the price of a product under the reserved term matching a commitment.
+// Term attributes left empty in the commitment match any value, and reserved terms without an offering class
+// (e.g., RDS reservations) match any offering class. When several terms match, the one with the lowest term key wins.
+//
+// Parameters:
+//   sku: The SKU of the product.
+//   commitment: The term attributes of the commitment (e.g., "1yr", "No Upfront", "standard").
+//
+// Returns:
+//   The reserved price, and true if a matching reserved term with a parseable price was found.
+func (p *PriceList) FindReservedPrice(sku string, commitment TermAttributes) (ReservedPrice, bool) {
+	terms := p.Terms.Reserved[sku]
+	termKeys := make([]string, 0, len(terms))
+	for key := range terms {
+		termKeys = append(termKeys, key)
+	}
+	sort.Strings(termKeys)
+
+	for _, termKey := range termKeys {
+		term := terms[termKey]
+		if !commitment.matches(term.TermAttributes) {
+			continue
+		}
+
+		price := ReservedPrice{OfferTermCode: term.OfferTermCode, TermAttributes: term.TermAttributes}
+		if price.OfferTermCode == "" {
+			price.OfferTermCode = termKey[strings.LastIndex(termKey, ".")+1:]
+		}
+		priced := false
+		for _, dim := range term.PriceDimensions {
+			value, err := strconv.ParseFloat(dim.PricePerUnit.USD, 64)
+			if err != nil {
+				continue
+			}
+			priced = true
+			if dim.Unit == "Quantity" {
+				price.UpfrontFee += value
+			} else {
+				price.HourlyRate += value
+			}
+		}
+		if priced {
+			return price, true
+		}
+	}
+	return ReservedPrice{}, false
+}
+
+// matches reports whether the attributes of a reserved term satisfy a commitment.
+func (c TermAttributes) matches(term TermAttributes) bool {
+	if c.LeaseContractLength != "" && !strings.EqualFold(c.LeaseContractLength, term.LeaseContractLength) {
+		return false
+	}
+	if c.PurchaseOption != "" && !strings.EqualFold(c.PurchaseOption, term.PurchaseOption) {
+		return false
+	}
+	if c.OfferingClass != "" && term.OfferingClass != "" && !strings.EqualFold(c.OfferingClass, term.OfferingClass) {
+		return false
+	}
+	return true
+}
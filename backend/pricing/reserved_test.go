@@ -0,0 +1,74 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newReservedPriceList() *PriceList {
+	priceList := NewPriceList()
+	priceList.Terms.Reserved = map[string]map[string]Term{
+		"ec2-sku": {
+			"ec2-sku.4NA7Y494T4": newReservedTerm("4NA7Y494T4", "1yr", "No Upfront", "standard", "0", "0.026"),
+			"ec2-sku.HU7G6KETJZ": newReservedTerm("HU7G6KETJZ", "1yr", "Partial Upfront", "standard", "110", "0.0125"),
+			"ec2-sku.6QCMYABX3D": newReservedTerm("6QCMYABX3D", "1yr", "All Upfront", "standard", "215", "0"),
+			"ec2-sku.7NE97W5U4E": newReservedTerm("7NE97W5U4E", "1yr", "No Upfront", "convertible", "0", "0.03"),
+			"ec2-sku.BPH4J8HBKS": newReservedTerm("BPH4J8HBKS", "3yr", "No Upfront", "standard", "0", "0.018"),
+		},
+		"rds-sku": {
+			"rds-sku.4NA7Y494T4": newReservedTerm("4NA7Y494T4", "1yr", "No Upfront", "", "0", "0.051"),
+		},
+	}
+	return priceList
+}
+
+func newReservedTerm(termCode, lease, purchaseOption, offeringClass, upfront, hourly string) Term {
+	upfrontDim := PriceDimension{Unit: "Quantity"}
+	upfrontDim.PricePerUnit.USD = upfront
+	hourlyDim := PriceDimension{Unit: "Hrs"}
+	hourlyDim.PricePerUnit.USD = hourly
+	return Term{
+		OfferTermCode: termCode,
+		PriceDimensions: map[string]PriceDimension{
+			termCode + ".2TG2D8R56U": upfrontDim,
+			termCode + ".6YS6EN2CT7": hourlyDim,
+		},
+		TermAttributes: TermAttributes{LeaseContractLength: lease, PurchaseOption: purchaseOption, OfferingClass: offeringClass},
+	}
+}
+
+func TestFindReservedPrice(t *testing.T) {
+	priceList := newReservedPriceList()
+
+	t.Run("finds the term matching the commitment", func(t *testing.T) {
+		price, ok := priceList.FindReservedPrice("ec2-sku", TermAttributes{LeaseContractLength: "1yr", PurchaseOption: "Partial Upfront", OfferingClass: "standard"})
+		assert.True(t, ok)
+		assert.Equal(t, "HU7G6KETJZ", price.OfferTermCode)
+		assert.Equal(t, 110.0, price.UpfrontFee)
+		assert.Equal(t, 0.0125, price.HourlyRate)
+		assert.Equal(t, 8760.0, price.LeaseHours())
+		assert.InDelta(t, 0.0125+110.0/8760, price.EffectiveHourlyRate(), 1e-9)
+	})
+
+	t.Run("matches commitment options case-insensitively", func(t *testing.T) {
+		price, ok := priceList.FindReservedPrice("ec2-sku", TermAttributes{LeaseContractLength: "3YR", PurchaseOption: "no upfront", OfferingClass: "Standard"})
+		assert.True(t, ok)
+		assert.Equal(t, "BPH4J8HBKS", price.OfferTermCode)
+		assert.Equal(t, 26280.0, price.LeaseHours())
+		assert.Equal(t, 0.018, price.EffectiveHourlyRate())
+	})
+
+	t.Run("matches terms without an offering class", func(t *testing.T) {
+		price, ok := priceList.FindReservedPrice("rds-sku", TermAttributes{LeaseContractLength: "1yr", PurchaseOption: "No Upfront", OfferingClass: "standard"})
+		assert.True(t, ok)
+		assert.Equal(t, 0.051, price.HourlyRate)
+	})
+
+	t.Run("returns false without a matching term", func(t *testing.T) {
+		_, ok := priceList.FindReservedPrice("rds-sku", TermAttributes{LeaseContractLength: "3yr"})
+		assert.False(t, ok)
+		_, ok = priceList.FindReservedPrice("unknown-sku", TermAttributes{})
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,254 @@
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Savings Plan types, as published in the product family of the Savings Plans offer files.
+const (
+	// SavingsPlanCompute is a Compute Savings Plan, which covers EC2 instances of any family and region.
+	SavingsPlanCompute = "ComputeSavingsPlans"
+	// SavingsPlanEC2Instance is an EC2 Instance Savings Plan, which covers the instances of one family in one region.
+	SavingsPlanEC2Instance = "EC2InstanceSavingsPlans"
+)
+
+// SavingsPlanRate is the rate of a product under a Savings Plan.
+type SavingsPlanRate struct {
+	// PlanType is the type of the Savings Plan: "ComputeSavingsPlans" or "EC2InstanceSavingsPlans".
+	PlanType string `json:"planType"`
+	// LeaseContractLength is the length of the commitment: "1yr" or "3yr".
+	LeaseContractLength string `json:"leaseContractLength"`
+	// PurchaseOption is how the commitment is paid: "No Upfront", "Partial Upfront" or "All Upfront".
+	PurchaseOption string `json:"purchaseOption"`
+	// RateCode is the code of the rate.
+	RateCode string `json:"rateCode"`
+	// Unit is the unit the rate is quoted in (e.g., "Hrs").
+	Unit string `json:"unit"`
+	// Rate is the price per unit under the Savings Plan, in USD.
+	Rate float64 `json:"rate"`
+}
+
+// UpfrontShare returns the share of the commitment that is paid at the start of the Savings Plan.
+// All Upfront plans are paid in full, and Partial Upfront plans pay half of the commitment upfront.
+//
+// Returns:
+//   The upfront share of the commitment, between 0 and 1.
+func (r SavingsPlanRate) UpfrontShare() float64 {
+	switch strings.ToLower(r.PurchaseOption) {
+	case "all upfront":
+		return 1
+	case "partial upfront":
+		return 0.5
+	default:
+		return 0
+	}
+}
+
+// LeaseHours returns the number of hours in the commitment of the Savings Plan.
+//
+// Returns:
+//   The number of hours in the lease contract, or 0 if the lease contract length is unknown.
+func (r SavingsPlanRate) LeaseHours() float64 {
+	return ReservedPrice{TermAttributes: TermAttributes{LeaseContractLength: r.LeaseContractLength}}.LeaseHours()
+}
+
+// savingsPlanOfferFile is the format of a regional AWS Savings Plans offer file.
+type savingsPlanOfferFile struct {
+	OfferCode       string `json:"offerCode"`
+	Version         string `json:"version"`
+	PublicationDate string `json:"publicationDate"`
+	Products        []struct {
+		SKU           string `json:"sku"`
+		ProductFamily string `json:"productFamily"`
+		Attributes    struct {
+			PurchaseOption string `json:"purchaseOption"`
+			PurchaseTerm   string `json:"purchaseTerm"`
+		} `json:"attributes"`
+	} `json:"products"`
+	Terms struct {
+		SavingsPlan []struct {
+			SKU   string `json:"sku"`
+			Rates []struct {
+				DiscountedSku  string `json:"discountedSku"`
+				RateCode       string `json:"rateCode"`
+				Unit           string `json:"unit"`
+				DiscountedRate struct {
+					Price string `json:"price"`
+				} `json:"discountedRate"`
+			} `json:"rates"`
+		} `json:"savingsPlan"`
+	} `json:"terms"`
+}
+
+// savingsPlanRegionIndex is the format of the region index of the AWS Savings Plans offer files.
+type savingsPlanRegionIndex struct {
+	Regions []struct {
+		RegionCode string `json:"regionCode"`
+		VersionURL string `json:"versionUrl"`
+	} `json:"regions"`
+}
+
+// SavingsPlanOfferURLs fetches the region index of the AWS Savings Plans offer files and returns the URL of the
+// offer file of every region.
+//
+// Parameters:
+//   regionIndexURL: The URL of the region index (e.g., ".../savingsPlan/v1.0/aws/AWSComputeSavingsPlan/current/region_index.json").
+//
+// Returns:
+//   The URLs of the regional offer files, ordered by region code.
+//   An error if the region index could not be loaded or parsed.
+func SavingsPlanOfferURLs(regionIndexURL string) ([]string, error) {
+	base, err := url.Parse(regionIndexURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Savings Plans region index URL %s: %w", regionIndexURL, err)
+	}
+	resp, err := http.Get(regionIndexURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download Savings Plans region index from %s: %w", regionIndexURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download Savings Plans region index: received status %s", resp.Status)
+	}
+
+	var index savingsPlanRegionIndex
+	if err := json.NewDecoder(resp.Body).Decode(&index); err != nil {
+		return nil, fmt.Errorf("failed to parse Savings Plans region index: %w", err)
+	}
+	sort.Slice(index.Regions, func(i, j int) bool {
+		return index.Regions[i].RegionCode < index.Regions[j].RegionCode
+	})
+
+	urls := make([]string, 0, len(index.Regions))
+	for _, region := range index.Regions {
+		offerURL, err := base.Parse(region.VersionURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Savings Plans offer URL for %s: %w", region.RegionCode, err)
+		}
+		urls = append(urls, offerURL.String())
+	}
+	return urls, nil
+}
+
+// LoadSavingsPlansFromURL fetches a regional Savings Plans offer file from a URL and merges its rates into the
+// price list.
+//
+// Parameters:
+//   url: The URL of the Savings Plans offer file to load.
+//
+// Returns:
+//   An error if the offer file could not be loaded or parsed, nil otherwise.
+func (p *PriceList) LoadSavingsPlansFromURL(url string) error {
+	resp, err := http.Get(url)
+	if err != nil {
+		return fmt.Errorf("failed to download Savings Plans from %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download Savings Plans: received status %s", resp.Status)
+	}
+	return p.loadSavingsPlans(resp.Body)
+}
+
+// LoadSavingsPlansFromFile loads a regional Savings Plans offer file from a local path and merges its rates into the
+// price list.
+//
+// Parameters:
+//   path: The local path of the Savings Plans offer file to load.
+//
+// Returns:
+//   An error if the offer file could not be loaded or parsed, nil otherwise.
+func (p *PriceList) LoadSavingsPlansFromFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open Savings Plans file: %w", err)
+	}
+	defer file.Close()
+
+	return p.loadSavingsPlans(file)
+}
+
+// loadSavingsPlans decodes a regional Savings Plans offer file and merges its rates into the price list.
+// Rates are keyed by the SKU of the product they discount, so they apply to the products of the on-demand offer files.
+func (p *PriceList) loadSavingsPlans(r io.Reader) error {
+	var offerFile savingsPlanOfferFile
+	if err := json.NewDecoder(r).Decode(&offerFile); err != nil {
+		return fmt.Errorf("failed to parse Savings Plans: %w", err)
+	}
+
+	plans := make(map[string]SavingsPlanRate, len(offerFile.Products))
+	for _, product := range offerFile.Products {
+		plans[product.SKU] = SavingsPlanRate{
+			PlanType:            product.ProductFamily,
+			LeaseContractLength: product.Attributes.PurchaseTerm,
+			PurchaseOption:      product.Attributes.PurchaseOption,
+		}
+	}
+
+	other := NewPriceList()
+	other.SavingsPlanRates = make(map[string][]SavingsPlanRate)
+	for _, term := range offerFile.Terms.SavingsPlan {
+		plan, ok := plans[term.SKU]
+		if !ok {
+			continue
+		}
+		for _, rate := range term.Rates {
+			value, err := strconv.ParseFloat(rate.DiscountedRate.Price, 64)
+			if err != nil {
+				continue
+			}
+			planRate := plan
+			planRate.RateCode = rate.RateCode
+			planRate.Unit = rate.Unit
+			planRate.Rate = value
+			other.SavingsPlanRates[rate.DiscountedSku] = append(other.SavingsPlanRates[rate.DiscountedSku], planRate)
+		}
+	}
+	if offerFile.OfferCode != "" {
+		if publicationDate, err := time.Parse(time.RFC3339, offerFile.PublicationDate); err == nil {
+			other.AddOffer(Offer{OfferCode: offerFile.OfferCode, Version: offerFile.Version, PublicationDate: publicationDate})
+		}
+	}
+
+	p.Merge(other)
+	return nil
+}
+
+// FindSavingsPlanRate returns the rate of a product under the Savings Plan matching a commitment.
+// Term attributes left empty in the commitment match any value. When several rates match, the one with the lowest
+// rate code wins.
+//
+// Parameters:
+//   sku: The SKU of the product.
+//   planType: The type of the Savings Plan (e.g., "ComputeSavingsPlans").
+//   commitment: The lease contract length and purchase option of the commitment (e.g., "1yr", "No Upfront").
+//
+// Returns:
+//   The Savings Plan rate, and true if a matching rate was found.
+func (p *PriceList) FindSavingsPlanRate(sku, planType string, commitment TermAttributes) (SavingsPlanRate, bool) {
+	var found SavingsPlanRate
+	ok := false
+	for _, rate := range p.SavingsPlanRates[sku] {
+		if !strings.EqualFold(rate.PlanType, planType) {
+			continue
+		}
+		if !commitment.matches(TermAttributes{LeaseContractLength: rate.LeaseContractLength, PurchaseOption: rate.PurchaseOption}) {
+			continue
+		}
+		if !ok || rate.RateCode < found.RateCode {
+			found, ok = rate, true
+		}
+	}
+	return found, ok
+}
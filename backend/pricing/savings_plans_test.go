@@ -0,0 +1,98 @@
+package pricing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadSavingsPlansFromFile(t *testing.T) {
+	t.Run("loads the rates of a Savings Plans offer file by discounted SKU", func(t *testing.T) {
+		priceList := NewPriceList()
+		assert.NoError(t, priceList.LoadSavingsPlansFromFile("../../testdata/sample-savings-plans.json"))
+
+		assert.Equal(t, []SavingsPlanRate{
+			{PlanType: SavingsPlanCompute, LeaseContractLength: "1yr", PurchaseOption: "No Upfront", RateCode: "2YAJA5CWA9QP9BBS.JRTCKXETXF", Unit: "Hrs", Rate: 0.0083},
+			{PlanType: SavingsPlanEC2Instance, LeaseContractLength: "1yr", PurchaseOption: "All Upfront", RateCode: "7ZHJ3YU3U2JN9WY9.JRTCKXETXF", Unit: "Hrs", Rate: 0.0069},
+		}, priceList.SavingsPlanRates["JRTCKXETXF"])
+		assert.Equal(t, []Offer{
+			{OfferCode: "AWSComputeSavingsPlan", Version: "20240501000000", PublicationDate: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		}, priceList.SortedOffers())
+	})
+
+	t.Run("keeps the rates when merged with an on-demand offer file", func(t *testing.T) {
+		priceList := NewPriceList()
+		assert.NoError(t, priceList.LoadSavingsPlansFromFile("../../testdata/sample-savings-plans.json"))
+		assert.NoError(t, priceList.LoadFromFile("../../testdata/sample-pricing.json"))
+
+		assert.Len(t, priceList.SavingsPlanRates["JRTCKXETXF"], 2)
+		assert.Contains(t, priceList.Products, "JRTCKXETXF")
+	})
+
+	t.Run("returns error for non-existent file", func(t *testing.T) {
+		priceList := NewPriceList()
+		assert.Error(t, priceList.LoadSavingsPlansFromFile("non-existent-file.json"))
+	})
+}
+
+func TestSavingsPlanOfferURLs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"regions": [
+			{"regionCode": "us-west-2", "versionUrl": "/savingsPlan/v1.0/aws/AWSComputeSavingsPlan/20240501000000/us-west-2/index.json"},
+			{"regionCode": "us-east-1", "versionUrl": "/savingsPlan/v1.0/aws/AWSComputeSavingsPlan/20240501000000/us-east-1/index.json"}
+		]}`))
+	}))
+	defer server.Close()
+
+	urls, err := SavingsPlanOfferURLs(server.URL + "/savingsPlan/v1.0/aws/AWSComputeSavingsPlan/current/region_index.json")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		server.URL + "/savingsPlan/v1.0/aws/AWSComputeSavingsPlan/20240501000000/us-east-1/index.json",
+		server.URL + "/savingsPlan/v1.0/aws/AWSComputeSavingsPlan/20240501000000/us-west-2/index.json",
+	}, urls)
+}
+
+func TestFindSavingsPlanRate(t *testing.T) {
+	priceList := NewPriceList()
+	priceList.SavingsPlanRates = map[string][]SavingsPlanRate{
+		"ec2-sku": {
+			{PlanType: SavingsPlanCompute, LeaseContractLength: "1yr", PurchaseOption: "No Upfront", RateCode: "B.ec2-sku", Rate: 0.0083},
+			{PlanType: SavingsPlanCompute, LeaseContractLength: "3yr", PurchaseOption: "No Upfront", RateCode: "C.ec2-sku", Rate: 0.0058},
+			{PlanType: SavingsPlanEC2Instance, LeaseContractLength: "1yr", PurchaseOption: "No Upfront", RateCode: "A.ec2-sku", Rate: 0.0072},
+		},
+	}
+
+	t.Run("finds the rate of the plan type matching the commitment", func(t *testing.T) {
+		rate, ok := priceList.FindSavingsPlanRate("ec2-sku", SavingsPlanCompute, TermAttributes{LeaseContractLength: "1yr", PurchaseOption: "No Upfront"})
+		assert.True(t, ok)
+		assert.Equal(t, "B.ec2-sku", rate.RateCode)
+		assert.Equal(t, 0.0083, rate.Rate)
+
+		rate, ok = priceList.FindSavingsPlanRate("ec2-sku", SavingsPlanEC2Instance, TermAttributes{LeaseContractLength: "1yr", PurchaseOption: "No Upfront"})
+		assert.True(t, ok)
+		assert.Equal(t, 0.0072, rate.Rate)
+	})
+
+	t.Run("matches commitment options case-insensitively", func(t *testing.T) {
+		rate, ok := priceList.FindSavingsPlanRate("ec2-sku", SavingsPlanCompute, TermAttributes{LeaseContractLength: "3YR", PurchaseOption: "no upfront"})
+		assert.True(t, ok)
+		assert.Equal(t, 0.0058, rate.Rate)
+		assert.Equal(t, 26280.0, rate.LeaseHours())
+	})
+
+	t.Run("returns false without a matching rate", func(t *testing.T) {
+		_, ok := priceList.FindSavingsPlanRate("ec2-sku", SavingsPlanEC2Instance, TermAttributes{LeaseContractLength: "3yr"})
+		assert.False(t, ok)
+		_, ok = priceList.FindSavingsPlanRate("unknown-sku", SavingsPlanCompute, TermAttributes{})
+		assert.False(t, ok)
+	})
+}
+
+func TestSavingsPlanRateUpfrontShare(t *testing.T) {
+	assert.Equal(t, 1.0, SavingsPlanRate{PurchaseOption: "All Upfront"}.UpfrontShare())
+	assert.Equal(t, 0.5, SavingsPlanRate{PurchaseOption: "Partial Upfront"}.UpfrontShare())
+	assert.Equal(t, 0.0, SavingsPlanRate{PurchaseOption: "No Upfront"}.UpfrontShare())
+}
@@ -0,0 +1,157 @@
+package pricing
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Tier is a single price tier of a product, priced per unit for the part of the monthly quantity within its range.
+type Tier struct {
+	// BeginRange is the start of the quantity range of the tier.
+	BeginRange float64
+	// EndRange is the end of the quantity range of the tier, or +Inf if the range is unbounded.
+	EndRange float64
+	// Price is the price per unit in USD.
+	Price float64
+	// RateCode is the rate code of the price dimension the tier comes from.
+	RateCode string
+	// Description describes the tier.
+	Description string
+	// Unit is the unit the price is quoted in.
+	Unit string
+}
+
+// TierUsage is the part of a quantity priced at a single tier.
+type TierUsage struct {
+	// Tier is the tier the quantity is priced at.
+	Tier Tier
+	// Quantity is the part of the quantity within the range of the tier.
+	Quantity float64
+}
+
+// Cost returns the cost of the part of the quantity priced at the tier.
+//
+// Returns:
+//   The quantity multiplied by the price of the tier.
+func (u TierUsage) Cost() float64 {
+	return u.Quantity * u.Tier.Price
+}
+
+// OnDemandTiers returns the price tiers of the on-demand term of a product, ordered by the start of their range.
+// Price dimensions without a range are treated as a single tier covering every quantity. Terms are visited in key
+// order and the first term with a parseable price is used, so the result is deterministic.
+//
+// Parameters:
+//   sku: The SKU of the product.
+//
+// Returns:
+//   The price tiers of the product and the offer term code of the term they come from.
+//   An error if the product has no parseable on-demand price.
+func (p *PriceList) OnDemandTiers(sku string) ([]Tier, string, error) {
+	terms := p.Terms.OnDemand[sku]
+	termKeys := make([]string, 0, len(terms))
+	for key := range terms {
+		termKeys = append(termKeys, key)
+	}
+	sort.Strings(termKeys)
+
+	for _, termKey := range termKeys {
+		term := terms[termKey]
+		var tiers []Tier
+		for _, dim := range term.PriceDimensions {
+			tier, err := dim.tier()
+			if err != nil {
+				continue
+			}
+			tiers = append(tiers, tier)
+		}
+		if len(tiers) == 0 {
+			continue
+		}
+
+		sort.Slice(tiers, func(i, j int) bool {
+			if tiers[i].BeginRange != tiers[j].BeginRange {
+				return tiers[i].BeginRange < tiers[j].BeginRange
+			}
+			return tiers[i].RateCode < tiers[j].RateCode
+		})
+
+		termCode := term.OfferTermCode
+		if termCode == "" {
+			// Term keys have the form "<sku>.<offerTermCode>".
+			termCode = termKey[strings.LastIndex(termKey, ".")+1:]
+		}
+		return tiers, termCode, nil
+	}
+
+	return nil, "", fmt.Errorf("could not extract price for SKU %s", sku)
+}
+
+// SplitTiers splits a quantity across price tiers. Each tier prices the part of the quantity within its range.
+//
+// Parameters:
+//   tiers: The price tiers, ordered by the start of their range.
+//   quantity: The monthly quantity to price.
+//
+// Returns:
+//   The parts of the quantity priced at each tier the quantity reaches, in tier order.
+func SplitTiers(tiers []Tier, quantity float64) []TierUsage {
+	var usages []TierUsage
+	for _, tier := range tiers {
+		if quantity <= tier.BeginRange {
+			break
+		}
+		used := math.Min(quantity, tier.EndRange) - tier.BeginRange
+		if used <= 0 {
+			continue
+		}
+		usages = append(usages, TierUsage{Tier: tier, Quantity: used})
+	}
+	return usages
+}
+
+// TieredCost prices a quantity across price tiers.
+//
+// Parameters:
+//   tiers: The price tiers, ordered by the start of their range.
+//   quantity: The monthly quantity to price.
+//
+// Returns:
+//   The total cost of the quantity.
+func TieredCost(tiers []Tier, quantity float64) float64 {
+	total := 0.0
+	for _, usage := range SplitTiers(tiers, quantity) {
+		total += usage.Cost()
+	}
+	return total
+}
+
+// tier converts a price dimension into a price tier.
+func (d PriceDimension) tier() (Tier, error) {
+	price, err := strconv.ParseFloat(d.PricePerUnit.USD, 64)
+	if err != nil {
+		return Tier{}, err
+	}
+
+	tier := Tier{
+		EndRange:    math.Inf(1),
+		Price:       price,
+		RateCode:    d.RateCode,
+		Description: d.Description,
+		Unit:        d.Unit,
+	}
+	if d.BeginRange != "" {
+		if tier.BeginRange, err = strconv.ParseFloat(d.BeginRange, 64); err != nil {
+			return Tier{}, fmt.Errorf("invalid begin range %q: %w", d.BeginRange, err)
+		}
+	}
+	if d.EndRange != "" && d.EndRange != "Inf" {
+		if tier.EndRange, err = strconv.ParseFloat(d.EndRange, 64); err != nil {
+			return Tier{}, fmt.Errorf("invalid end range %q: %w", d.EndRange, err)
+		}
+	}
+	return tier, nil
+}
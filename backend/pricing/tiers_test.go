@@ -0,0 +1,90 @@
+package pricing
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTieredPriceList() *PriceList {
+	priceList := NewPriceList()
+	priceList.Terms.OnDemand = map[string]map[string]Term{
+		"s3-sku": {
+			"s3-sku.JRTCKXETXF": {
+				OfferTermCode: "JRTCKXETXF",
+				PriceDimensions: map[string]PriceDimension{
+					"s3-sku.JRTCKXETXF.PGHJ3S3EYE": newDimension("PGHJ3S3EYE", "51200", "512000", "0.022"),
+					"s3-sku.JRTCKXETXF.WVV8R9FH29": newDimension("WVV8R9FH29", "512000", "Inf", "0.021"),
+					"s3-sku.JRTCKXETXF.D42MF2PVJS": newDimension("D42MF2PVJS", "0", "51200", "0.023"),
+				},
+			},
+		},
+	}
+	return priceList
+}
+
+func newDimension(rateCode, beginRange, endRange, price string) PriceDimension {
+	dim := PriceDimension{RateCode: rateCode, BeginRange: beginRange, EndRange: endRange, Unit: "GB-Mo"}
+	dim.PricePerUnit.USD = price
+	return dim
+}
+
+func TestOnDemandTiers(t *testing.T) {
+	t.Run("returns the tiers ordered by range", func(t *testing.T) {
+		tiers, termCode, err := newTieredPriceList().OnDemandTiers("s3-sku")
+		assert.NoError(t, err)
+		assert.Equal(t, "JRTCKXETXF", termCode)
+		assert.Len(t, tiers, 3)
+		assert.Equal(t, Tier{BeginRange: 0, EndRange: 51200, Price: 0.023, RateCode: "D42MF2PVJS", Unit: "GB-Mo"}, tiers[0])
+		assert.Equal(t, 51200.0, tiers[1].BeginRange)
+		assert.Equal(t, 512000.0, tiers[1].EndRange)
+		assert.True(t, math.IsInf(tiers[2].EndRange, 1))
+	})
+
+	t.Run("treats dimensions without a range as a single tier", func(t *testing.T) {
+		priceList := NewPriceList()
+		dim := PriceDimension{}
+		dim.PricePerUnit.USD = "0.045"
+		priceList.Terms.OnDemand = map[string]map[string]Term{
+			"nat-sku": {"nat-sku.JRTCKXETXF": {PriceDimensions: map[string]PriceDimension{"dim": dim}}},
+		}
+
+		tiers, termCode, err := priceList.OnDemandTiers("nat-sku")
+		assert.NoError(t, err)
+		assert.Equal(t, "JRTCKXETXF", termCode)
+		assert.Len(t, tiers, 1)
+		assert.Equal(t, 0.0, tiers[0].BeginRange)
+		assert.True(t, math.IsInf(tiers[0].EndRange, 1))
+	})
+
+	t.Run("returns an error for products without a price", func(t *testing.T) {
+		_, _, err := NewPriceList().OnDemandTiers("missing-sku")
+		assert.Error(t, err)
+	})
+}
+
+func TestTieredCost(t *testing.T) {
+	tiers, _, err := newTieredPriceList().OnDemandTiers("s3-sku")
+	assert.NoError(t, err)
+
+	t.Run("prices a quantity within the first tier", func(t *testing.T) {
+		assert.InDelta(t, 100*0.023, TieredCost(tiers, 100), 0.0001)
+	})
+
+	t.Run("prices a quantity across every tier", func(t *testing.T) {
+		usages := SplitTiers(tiers, 600000)
+		assert.Len(t, usages, 3)
+		assert.Equal(t, 51200.0, usages[0].Quantity)
+		assert.Equal(t, 460800.0, usages[1].Quantity)
+		assert.Equal(t, 88000.0, usages[2].Quantity)
+
+		expected := 51200*0.023 + 460800*0.022 + 88000*0.021
+		assert.InDelta(t, expected, TieredCost(tiers, 600000), 0.0001)
+	})
+
+	t.Run("returns no usage for a zero quantity", func(t *testing.T) {
+		assert.Empty(t, SplitTiers(tiers, 0))
+		assert.Equal(t, 0.0, TieredCost(tiers, 0))
+	})
+}
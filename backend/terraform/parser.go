@@ -11,24 +11,100 @@ type Plan struct {
 	ResourceChanges []*ResourceChange `json:"resource_changes"`
 }
 
+// Resource modes reported in ResourceChange.Mode.
+const (
+	// ModeManaged is the mode of resources declared with a resource block.
+	ModeManaged = "managed"
+	// ModeData is the mode of data sources.
+	ModeData = "data"
+)
+
+// Change actions reported in Change.Actions.
+const (
+	// ActionNoOp means the resource is unchanged.
+	ActionNoOp = "no-op"
+	// ActionCreate means the resource is created.
+	ActionCreate = "create"
+	// ActionRead means the data source is read.
+	ActionRead = "read"
+	// ActionUpdate means the resource is updated in place.
+	ActionUpdate = "update"
+	// ActionDelete means the resource is destroyed.
+	ActionDelete = "delete"
+	// ActionForget means the resource is removed from the state without being destroyed.
+	ActionForget = "forget"
+)
+
 // ResourceChange represents a change to a single resource in the plan.
 type ResourceChange struct {
 	// Address is the address of the resource.
-	Address      string                 `json:"address"`
+	Address string `json:"address"`
+	// PreviousAddress is the address of the resource before it was moved, if it was moved.
+	PreviousAddress string `json:"previous_address,omitempty"`
+	// Mode is the mode of the resource: "managed" or "data".
+	Mode string `json:"mode"`
 	// Type is the type of the resource.
-	Type         string                 `json:"type"`
+	Type string `json:"type"`
 	// Change represents the actions to be taken on a resource.
-	Change       Change                 `json:"change"`
+	Change Change `json:"change"`
 	// Before is the state of the resource before the change.
-	Before       map[string]interface{} `json:"before"`
+	Before map[string]interface{} `json:"before"`
 	// After is the state of the resource after the change.
-	After        map[string]interface{} `json:"after"`
+	After map[string]interface{} `json:"after"`
+}
+
+// IsData reports whether the resource change is for a data source.
+func (rc *ResourceChange) IsData() bool {
+	return rc.Mode == ModeData
+}
+
+// IsImport reports whether the resource is being imported into the state.
+func (rc *ResourceChange) IsImport() bool {
+	return rc.Change.Importing != nil
+}
+
+// IsMove reports whether the resource was moved from another address.
+func (rc *ResourceChange) IsMove() bool {
+	return rc.PreviousAddress != "" && rc.PreviousAddress != rc.Address
 }
 
 // Change represents the actions to be taken on a resource.
 type Change struct {
 	// Actions is a list of actions to be taken on the resource.
 	Actions []string `json:"actions"`
+	// Importing is set when the resource is being imported by an import block.
+	Importing *Importing `json:"importing,omitempty"`
+}
+
+// Is reports whether the change has exactly the given actions, in order.
+//
+// Parameters:
+//   actions: The expected actions.
+//
+// Returns:
+//   True if the change's actions equal the expected actions.
+func (c Change) Is(actions ...string) bool {
+	if len(c.Actions) != len(actions) {
+		return false
+	}
+	for i, action := range actions {
+		if c.Actions[i] != action {
+			return false
+		}
+	}
+	return true
+}
+
+// IsReplace reports whether the change destroys and re-creates the resource, in either order.
+// ["delete", "create"] destroys the resource first; ["create", "delete"] is used by create_before_destroy.
+func (c Change) IsReplace() bool {
+	return c.Is(ActionDelete, ActionCreate) || c.Is(ActionCreate, ActionDelete)
+}
+
+// Importing describes a resource being imported by an import block.
+type Importing struct {
+	// ID is the import ID of the resource.
+	ID string `json:"id"`
 }
 
 // ParsePlan parses a Terraform plan from a JSON reader.
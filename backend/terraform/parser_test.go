@@ -38,6 +38,51 @@ func TestParsePlan(t *testing.T) {
 		assert.Equal(t, "t2.micro", rc.After["instance_type"])
 	})
 
+	t.Run("parses moved, imported and data resources", func(t *testing.T) {
+		planJSON := `
+		{
+			"resource_changes": [
+				{
+					"address": "aws_instance.app",
+					"previous_address": "aws_instance.web",
+					"mode": "managed",
+					"type": "aws_instance",
+					"change": {"actions": ["no-op"]}
+				},
+				{
+					"address": "aws_s3_bucket.logs",
+					"mode": "managed",
+					"type": "aws_s3_bucket",
+					"change": {"actions": ["no-op"], "importing": {"id": "my-logs"}}
+				},
+				{
+					"address": "data.aws_ami.ubuntu",
+					"mode": "data",
+					"type": "aws_ami",
+					"change": {"actions": ["read"]}
+				}
+			]
+		}
+		`
+		plan, err := ParsePlan(strings.NewReader(planJSON))
+		assert.NoError(t, err)
+		assert.Len(t, plan.ResourceChanges, 3)
+
+		moved := plan.ResourceChanges[0]
+		assert.Equal(t, "aws_instance.web", moved.PreviousAddress)
+		assert.True(t, moved.IsMove())
+		assert.False(t, moved.IsImport())
+
+		imported := plan.ResourceChanges[1]
+		assert.True(t, imported.IsImport())
+		assert.Equal(t, "my-logs", imported.Change.Importing.ID)
+		assert.False(t, imported.IsMove())
+
+		data := plan.ResourceChanges[2]
+		assert.True(t, data.IsData())
+		assert.True(t, data.Change.Is(ActionRead))
+	})
+
 	t.Run("returns error for invalid json", func(t *testing.T) {
 		planJSON := `{"invalid_json":}`
 		reader := strings.NewReader(planJSON)
@@ -45,3 +90,18 @@ func TestParsePlan(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestChange(t *testing.T) {
+	t.Run("matches actions exactly and in order", func(t *testing.T) {
+		change := Change{Actions: []string{"delete", "create"}}
+		assert.True(t, change.Is(ActionDelete, ActionCreate))
+		assert.False(t, change.Is(ActionCreate, ActionDelete))
+		assert.False(t, change.Is(ActionDelete))
+	})
+
+	t.Run("detects replacements in either order", func(t *testing.T) {
+		assert.True(t, Change{Actions: []string{"delete", "create"}}.IsReplace())
+		assert.True(t, Change{Actions: []string{"create", "delete"}}.IsReplace())
+		assert.False(t, Change{Actions: []string{"update"}}.IsReplace())
+	})
+}
@@ -43,6 +43,8 @@ var analyzeCmd = &cobra.Command{
 		prNumberStr := ""
 		resolvedRegion := "us-east-1" // Default region
 		usageEstimates := estimator.UsageEstimates{}
+		var pricingModels map[string]estimator.PricingModel
+		var discounts *estimator.DiscountPolicy
 
 		cfg, err := config.LoadConfig(".cloudcostguard.yml")
 		if err == nil {
@@ -52,6 +54,8 @@ var analyzeCmd = &cobra.Command{
 				resolvedRegion = cfg.Region
 			}
 			usageEstimates = cfg.UsageEstimates
+			pricingModels = cfg.PricingModels
+			discounts = cfg.Discounts
 		} else if !os.IsNotExist(err) {
 			return fmt.Errorf("could not load config file: %w", err)
 		}
@@ -90,6 +94,8 @@ var analyzeCmd = &cobra.Command{
 		requestBody, err := json.Marshal(map[string]interface{}{
 			"plan":           json.RawMessage(planBytes),
 			"usage_estimates": usageEstimates,
+			"pricing_models":  pricingModels,
+			"discounts":       discounts,
 		})
 		if err != nil {
 			return fmt.Errorf("could not marshal request body: %w", err)
@@ -132,19 +138,130 @@ var analyzeCmd = &cobra.Command{
 func formatComment(result estimator.EstimationResponse) string {
 	var builder strings.Builder
 	builder.WriteString("## CloudCostGuard Analysis 🤖\n\n")
-	builder.WriteString(fmt.Sprintf("Estimated Monthly Cost Impact: **$%.2f**\n\n", result.TotalMonthlyCost))
+	builder.WriteString(fmt.Sprintf("Estimated Monthly Cost Impact: **%s**\n\n", formatDelta(result.TotalMonthlyCost)))
+	if result.MonthlyCostAdded != 0 || result.MonthlyCostRemoved != 0 {
+		builder.WriteString(fmt.Sprintf("Cost added: $%.2f · Cost removed: $%.2f\n\n", result.MonthlyCostAdded, result.MonthlyCostRemoved))
+	}
+	if result.ListMonthlyCost != result.TotalMonthlyCost {
+		builder.WriteString(fmt.Sprintf("At list prices, before discounts: %s\n\n", formatDelta(result.ListMonthlyCost)))
+	}
+	if result.BaselineResources > 0 {
+		builder.WriteString(fmt.Sprintf("Unchanged resources: $%.2f/month across %d resources\n\n", result.BaselineMonthlyCost, result.BaselineResources))
+	}
 
 	if len(result.Resources) > 0 {
 		builder.WriteString("| Resource | Monthly Cost | Details |\n")
 		builder.WriteString("| :--- | :--- | :--- |\n")
 		for _, resource := range result.Resources {
-			builder.WriteString(fmt.Sprintf("| `%s` | `$%.2f` | %s |\n", resource.Address, resource.MonthlyCost, resource.CostBreakdown))
+			builder.WriteString(fmt.Sprintf("| %s | `%s` | %s |\n", formatResourceAddress(resource), formatResourceCost(resource), formatResourceDetails(resource)))
 		}
 	}
 
+	builder.WriteString(formatCommitments(result))
+	builder.WriteString(formatCoverage(result))
+
+	if result.Pricing.LastUpdated != nil {
+		builder.WriteString(fmt.Sprintf("\n_Prices last updated from AWS on %s._\n", result.Pricing.LastUpdated.UTC().Format("2006-01-02 15:04 MST")))
+	}
+
 	return builder.String()
 }
 
+// formatCoverage renders how many resources were priced, followed by the skipped resources and warnings.
+// It returns an empty string when the plan has no resource changes with a cost impact.
+func formatCoverage(result estimator.EstimationResponse) string {
+	if result.Coverage.TotalResources == 0 && len(result.Warnings) == 0 {
+		return ""
+	}
+
+	var builder strings.Builder
+	builder.WriteString("\n### Coverage\n\n")
+	builder.WriteString(fmt.Sprintf("%d of %d resources priced.\n", result.Coverage.PricedResources, result.Coverage.TotalResources))
+	if len(result.Skipped) > 0 {
+		builder.WriteString("\nNot priced (excluded from the totals above):\n\n")
+		for _, skipped := range result.Skipped {
+			builder.WriteString(fmt.Sprintf("- `%s` (%s): %s\n", skipped.Address, skipped.Reason, skipped.Message))
+		}
+	}
+	if len(result.Warnings) > 0 {
+		builder.WriteString("\nWarnings:\n\n")
+		for _, warning := range result.Warnings {
+			builder.WriteString(fmt.Sprintf("- `%s` (%s): %s\n", warning.Address, warning.Code, warning.Message))
+		}
+	}
+	return builder.String()
+}
+
+// formatCommitments renders the savings that reserved commitments and Savings Plans offer over on-demand prices.
+// It returns an empty string when no resource in the estimate would be cheaper under a commitment.
+func formatCommitments(result estimator.EstimationResponse) string {
+	var builder strings.Builder
+	for _, resource := range result.Resources {
+		commitment := resource.Commitment
+		if commitment == nil || commitment.MonthlySavings <= 0 {
+			continue
+		}
+		builder.WriteString(fmt.Sprintf("- `%s`: $%.2f/month on demand, $%.2f/month at %s", resource.Address, commitment.OnDemandMonthlyCost, commitment.CommittedMonthlyCost, commitment.Model))
+		if commitment.UpfrontCost > 0 {
+			builder.WriteString(fmt.Sprintf(" ($%.2f upfront)", commitment.UpfrontCost))
+		}
+		builder.WriteString(fmt.Sprintf(", saving $%.2f/month\n", commitment.MonthlySavings))
+	}
+	if builder.Len() == 0 {
+		return ""
+	}
+	return "\n### Commitment Savings\n\n" + builder.String()
+}
+
+// formatResourceAddress renders the address of a resource change, noting moved and imported resources.
+func formatResourceAddress(resource estimator.ResourceCost) string {
+	address := fmt.Sprintf("`%s`", resource.Address)
+	if resource.PreviousAddress != "" {
+		address += fmt.Sprintf(" (moved from `%s`)", resource.PreviousAddress)
+	}
+	if resource.Imported {
+		address += " (imported)"
+	}
+	return address
+}
+
+// formatResourceCost renders the monthly cost of a resource change.
+// Updates and replacements show the cost before and after the change, e.g. "$15.00 → $60.00 (+$45.00)".
+func formatResourceCost(resource estimator.ResourceCost) string {
+	switch resource.Action {
+	case estimator.ActionUpdate, estimator.ActionReplace:
+		sign := "+"
+		if resource.MonthlyCost < 0 {
+			sign = ""
+		}
+		return fmt.Sprintf("$%.2f → $%.2f (%s%s)", resource.BeforeMonthlyCost, resource.AfterMonthlyCost, sign, formatDelta(resource.MonthlyCost))
+	default:
+		return formatDelta(resource.MonthlyCost)
+	}
+}
+
+// formatResourceDetails renders the cost breakdown of a resource change.
+// Updates and replacements show the breakdown before and after the change, and resources whose capacity scales
+// show the range of their cost, e.g. "Scales from $140.16 (2) to $700.80 (10) per month".
+func formatResourceDetails(resource estimator.ResourceCost) string {
+	details := resource.CostBreakdown
+	if resource.BeforeCostBreakdown != "" && resource.Action != estimator.ActionDelete && resource.BeforeCostBreakdown != resource.CostBreakdown {
+		details = fmt.Sprintf("%s → %s", resource.BeforeCostBreakdown, resource.CostBreakdown)
+	}
+	if r := resource.MonthlyCostRange; r != nil {
+		details += fmt.Sprintf(" · Scales from $%.2f (%g) to $%.2f (%g) per month", r.MinMonthlyCost, r.MinCapacity, r.MaxMonthlyCost, r.MaxCapacity)
+	}
+	return details
+}
+
+// formatDelta renders a monthly cost change, with a sign for decreases.
+func formatDelta(value float64) string {
+	if value < 0 {
+		return fmt.Sprintf("-$%.2f", -value)
+	}
+	return fmt.Sprintf("$%.2f", value)
+}
+
 var historyCmd = &cobra.Command{
 	Use:   "history [REPO]",
 	Short: "Shows the cost estimation history for a repository.",
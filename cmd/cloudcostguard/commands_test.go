@@ -4,6 +4,7 @@ import (
 	"cloudcostguard/backend/estimator"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestFormatComment(t *testing.T) {
@@ -33,6 +34,71 @@ func TestFormatComment(t *testing.T) {
 		assert.Contains(t, comment, "| `aws_ebs_volume.data` | `$23.45` | 100 GB @ $0.2345/GB-mo |")
 	})
 
+	t.Run("formats before and after costs for updated resources", func(t *testing.T) {
+		result := estimator.EstimationResponse{
+			TotalMonthlyCost:   35.0,
+			MonthlyCostAdded:   45.0,
+			MonthlyCostRemoved: 10.0,
+			Currency:           "USD",
+			Resources: []estimator.ResourceCost{
+				{
+					Address:             "aws_instance.web",
+					Action:              estimator.ActionUpdate,
+					BeforeMonthlyCost:   15.0,
+					AfterMonthlyCost:    60.0,
+					MonthlyCost:         45.0,
+					BeforeCostBreakdown: "t3.small @ $0.0205/hr",
+					CostBreakdown:       "t3.large @ $0.0822/hr",
+				},
+				{
+					Address:           "aws_ebs_volume.old",
+					Action:            estimator.ActionDelete,
+					BeforeMonthlyCost: 10.0,
+					MonthlyCost:       -10.0,
+					CostBreakdown:     "100 GB gp2 @ $0.1000/GB-mo",
+				},
+			},
+		}
+
+		comment := formatComment(result)
+
+		assert.Contains(t, comment, "Estimated Monthly Cost Impact: **$35.00**")
+		assert.Contains(t, comment, "Cost added: $45.00 · Cost removed: $10.00")
+		assert.Contains(t, comment, "| `aws_instance.web` | `$15.00 → $60.00 (+$45.00)` | t3.small @ $0.0205/hr → t3.large @ $0.0822/hr |")
+		assert.Contains(t, comment, "| `aws_ebs_volume.old` | `-$10.00` | 100 GB gp2 @ $0.1000/GB-mo |")
+	})
+
+	t.Run("formats the baseline and moved or imported resources", func(t *testing.T) {
+		result := estimator.EstimationResponse{
+			TotalMonthlyCost:    45.0,
+			MonthlyCostAdded:    45.0,
+			Currency:            "USD",
+			BaselineMonthlyCost: 250.0,
+			BaselineResources:   4,
+			Resources: []estimator.ResourceCost{
+				{
+					Address:           "aws_instance.app",
+					PreviousAddress:   "aws_instance.web",
+					Action:            estimator.ActionUpdate,
+					BeforeMonthlyCost: 15.0,
+					AfterMonthlyCost:  60.0,
+					MonthlyCost:       45.0,
+				},
+				{
+					Address:  "aws_instance.legacy",
+					Action:   estimator.ActionUpdate,
+					Imported: true,
+				},
+			},
+		}
+
+		comment := formatComment(result)
+
+		assert.Contains(t, comment, "Unchanged resources: $250.00/month across 4 resources")
+		assert.Contains(t, comment, "| `aws_instance.app` (moved from `aws_instance.web`) | `$15.00 → $60.00 (+$45.00)` |")
+		assert.Contains(t, comment, "| `aws_instance.legacy` (imported) |")
+	})
+
 	t.Run("formats a comment with no resources", func(t *testing.T) {
 		result := estimator.EstimationResponse{
 			TotalMonthlyCost: 0.0,
@@ -44,5 +110,131 @@ func TestFormatComment(t *testing.T) {
 
 		assert.Contains(t, comment, "Estimated Monthly Cost Impact: **$0.00**")
 		assert.NotContains(t, comment, "| Resource | Monthly Cost | Details |")
+		assert.NotContains(t, comment, "### Coverage")
+		assert.NotContains(t, comment, "### Commitment Savings")
+		assert.NotContains(t, comment, "At list prices")
+		assert.NotContains(t, comment, "Prices last updated")
+	})
+
+	t.Run("formats the pricing update time", func(t *testing.T) {
+		lastUpdated := time.Date(2024, 6, 1, 12, 30, 0, 0, time.UTC)
+		result := estimator.EstimationResponse{
+			Currency: "USD",
+			Pricing:  estimator.PricingSource{LastUpdated: &lastUpdated},
+		}
+
+		comment := formatComment(result)
+
+		assert.Contains(t, comment, "_Prices last updated from AWS on 2024-06-01 12:30 UTC._")
+	})
+
+	t.Run("formats the cost at list prices when discounts apply", func(t *testing.T) {
+		result := estimator.EstimationResponse{
+			TotalMonthlyCost: 90.00,
+			ListMonthlyCost:  100.00,
+			Currency:         "USD",
+		}
+
+		comment := formatComment(result)
+
+		assert.Contains(t, comment, "Estimated Monthly Cost Impact: **$90.00**")
+		assert.Contains(t, comment, "At list prices, before discounts: $100.00\n")
+	})
+
+	t.Run("formats the cost range of scaling resources", func(t *testing.T) {
+		result := estimator.EstimationResponse{
+			TotalMonthlyCost: 280.32,
+			Currency:         "USD",
+			Resources: []estimator.ResourceCost{
+				{
+					Address:       "aws_autoscaling_group.web",
+					Action:        estimator.ActionCreate,
+					MonthlyCost:   280.32,
+					CostBreakdown: "Instance usage (m5.large): 2920 × $0.096/hour",
+					MonthlyCostRange: &estimator.CostRange{
+						MinCapacity:    2,
+						MaxCapacity:    10,
+						MinMonthlyCost: 140.16,
+						MaxMonthlyCost: 700.80,
+					},
+				},
+			},
+		}
+
+		comment := formatComment(result)
+
+		assert.Contains(t, comment, "| `aws_autoscaling_group.web` | `$280.32` | Instance usage (m5.large): 2920 × $0.096/hour · Scales from $140.16 (2) to $700.80 (10) per month |")
+	})
+
+	t.Run("formats commitment savings", func(t *testing.T) {
+		result := estimator.EstimationResponse{
+			Currency: "USD",
+			Resources: []estimator.ResourceCost{
+				{
+					Address:      "aws_instance.web",
+					Action:       estimator.ActionCreate,
+					MonthlyCost:  60.74,
+					PricingModel: "on_demand",
+					Commitment: &estimator.CommitmentComparison{
+						Model:                "1yr No Upfront standard reserved",
+						OnDemandMonthlyCost:  60.74,
+						CommittedMonthlyCost: 38.33,
+						MonthlySavings:       22.41,
+					},
+				},
+				{
+					Address:      "aws_db_instance.db",
+					Action:       estimator.ActionCreate,
+					MonthlyCost:  30.00,
+					PricingModel: "1yr All Upfront standard reserved",
+					Commitment: &estimator.CommitmentComparison{
+						Model:                "1yr All Upfront standard reserved",
+						OnDemandMonthlyCost:  50.00,
+						CommittedMonthlyCost: 30.00,
+						UpfrontCost:          360.00,
+						MonthlySavings:       20.00,
+					},
+				},
+			},
+		}
+
+		comment := formatComment(result)
+
+		assert.Contains(t, comment, "### Commitment Savings")
+		assert.Contains(t, comment, "- `aws_instance.web`: $60.74/month on demand, $38.33/month at 1yr No Upfront standard reserved, saving $22.41/month\n")
+		assert.Contains(t, comment, "- `aws_db_instance.db`: $50.00/month on demand, $30.00/month at 1yr All Upfront standard reserved ($360.00 upfront), saving $20.00/month\n")
+	})
+
+	t.Run("formats coverage with skipped resources and warnings", func(t *testing.T) {
+		result := estimator.EstimationResponse{
+			TotalMonthlyCost: 100.0,
+			Currency:         "USD",
+			Resources: []estimator.ResourceCost{
+				{Address: "aws_instance.web", Action: estimator.ActionCreate, MonthlyCost: 100.0},
+			},
+			Skipped: []estimator.SkippedResource{
+				{
+					Address: "aws_sfn_state_machine.flow",
+					Type:    "aws_sfn_state_machine",
+					Reason:  estimator.SkipReasonUnsupportedType,
+					Message: "unsupported resource type: aws_sfn_state_machine",
+				},
+			},
+			Warnings: []estimator.Warning{
+				{
+					Address: "aws_instance.web",
+					Code:    estimator.WarningAmbiguousMatch,
+					Message: "2 SKUs with different prices match EC2 instance type: t2.micro; using the lowest-priced SKU a",
+				},
+			},
+			Coverage: estimator.Coverage{TotalResources: 2, PricedResources: 1, SkippedResources: 1},
+		}
+
+		comment := formatComment(result)
+
+		assert.Contains(t, comment, "### Coverage")
+		assert.Contains(t, comment, "1 of 2 resources priced.")
+		assert.Contains(t, comment, "- `aws_sfn_state_machine.flow` (unsupported_type): unsupported resource type: aws_sfn_state_machine")
+		assert.Contains(t, comment, "- `aws_instance.web` (ambiguous_match): 2 SKUs with different prices")
 	})
 }
@@ -50,6 +50,12 @@ const docTemplate = `{
                         "description": "AWS Region",
                         "name": "region",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "GitHub repository (owner/repo) the estimate is recorded for",
+                        "name": "repo",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -112,22 +118,110 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "estimator.DiscountPolicy": {
+            "type": "object",
+            "properties": {
+                "overrides": {
+                    "description": "Overrides lists explicit unit prices for specific SKUs or instance types.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/estimator.PriceOverride"
+                    }
+                },
+                "percent": {
+                    "description": "Percent is the percentage discount on all services (e.g., 5 for 5% off).",
+                    "type": "number"
+                },
+                "services": {
+                    "description": "Services maps AWS service codes (e.g., \"AmazonEC2\") to a percentage discount on that service.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number",
+                        "format": "float64"
+                    }
+                }
+            }
+        },
         "estimator.EstimateRequest": {
             "type": "object",
             "properties": {
+                "discounts": {
+                    "description": "Discounts is the discount policy to apply to list prices. A policy configured on the server for the\norganization of the API key takes precedence over it.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/estimator.DiscountPolicy"
+                        }
+                    ]
+                },
                 "plan": {
                     "$ref": "#/definitions/terraform.Plan"
                 },
+                "pricing_models": {
+                    "description": "PricingModels maps resource types (e.g., \"aws_instance\") to the pricing model used for them.\nResource types without an entry are priced on demand.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/estimator.PricingModel"
+                    }
+                },
                 "usage_estimates": {
                     "$ref": "#/definitions/estimator.UsageEstimates"
                 }
             }
         },
+        "estimator.PriceOverride": {
+            "type": "object",
+            "properties": {
+                "instance_type": {
+                    "description": "InstanceType is the instance type or instance class of the products to override (e.g., \"m5.large\" or \"db.r5.large\").",
+                    "type": "string"
+                },
+                "sku": {
+                    "description": "SKU is the SKU of the product to override.",
+                    "type": "string"
+                },
+                "unit_price": {
+                    "description": "UnitPrice is the price per unit of the component (e.g., per hour for instance usage), in USD.",
+                    "type": "number"
+                }
+            }
+        },
+        "estimator.PricingModel": {
+            "type": "object",
+            "properties": {
+                "lease_contract_length": {
+                    "description": "LeaseContractLength is the length of a reserved commitment or Savings Plan: \"1yr\" (the default) or \"3yr\".",
+                    "type": "string"
+                },
+                "offering_class": {
+                    "description": "OfferingClass is the offering class of a reserved commitment: \"standard\" (the default) or \"convertible\".",
+                    "type": "string"
+                },
+                "purchase_option": {
+                    "description": "PurchaseOption is how a reserved commitment or Savings Plan is paid: \"No Upfront\" (the default),\n\"Partial Upfront\" or \"All Upfront\".",
+                    "type": "string"
+                },
+                "savings_plan_type": {
+                    "description": "SavingsPlanType is the type of a Savings Plan: \"compute\" (the default) or \"ec2_instance\".",
+                    "type": "string"
+                },
+                "term": {
+                    "description": "Term is the pricing term: \"on_demand\" (the default), \"reserved\" or \"savings_plan\".",
+                    "type": "string"
+                }
+            }
+        },
         "estimator.UsageEstimates": {
             "type": "object",
             "properties": {
                 "nat_gateway_gb_processed": {
                     "type": "integer"
+                },
+                "resources": {
+                    "description": "Resources maps resource addresses or wildcard patterns (e.g., \"module.workers.*\") to usage estimates for the\nmatching resources. Fields left unset in an entry fall back to the global values above. See UsageEstimates.For.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/estimator.UsageEstimates"
+                    }
                 }
             }
         },
@@ -140,6 +234,23 @@ const docTemplate = `{
                     "items": {
                         "type": "string"
                     }
+                },
+                "importing": {
+                    "description": "Importing is set when the resource is being imported by an import block.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/terraform.Importing"
+                        }
+                    ]
+                }
+            }
+        },
+        "terraform.Importing": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "ID is the import ID of the resource.",
+                    "type": "string"
                 }
             }
         },
@@ -180,6 +291,14 @@ const docTemplate = `{
                         }
                     ]
                 },
+                "mode": {
+                    "description": "Mode is the mode of the resource: \"managed\" or \"data\".",
+                    "type": "string"
+                },
+                "previous_address": {
+                    "description": "PreviousAddress is the address of the resource before it was moved, if it was moved.",
+                    "type": "string"
+                },
                 "type": {
                     "description": "Type is the type of the resource.",
                     "type": "string"
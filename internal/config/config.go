@@ -19,6 +19,10 @@ type Config struct {
 	Region string `yaml:"region"`
 	// UsageEstimates contains user-provided estimates for usage-based resources.
 	UsageEstimates estimator.UsageEstimates `yaml:"usage_estimates"`
+	// PricingModels maps resource types to the pricing model used for them (e.g., 1yr No Upfront reserved for aws_instance).
+	PricingModels map[string]estimator.PricingModel `yaml:"pricing_models"`
+	// Discounts is the discount policy applied to list prices, unless the backend has one for the organization of the API key.
+	Discounts *estimator.DiscountPolicy `yaml:"discounts"`
 }
 
 // LoadConfig loads the configuration from the specified path.
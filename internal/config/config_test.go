@@ -1,6 +1,7 @@
 package config
 
 import (
+	"cloudcostguard/backend/estimator"
 	"github.com/stretchr/testify/assert"
 	"os"
 	"testing"
@@ -28,6 +29,88 @@ github:
 		assert.Equal(t, 123, config.GitHub.PRNumber)
 	})
 
+	t.Run("loads per-resource usage estimates", func(t *testing.T) {
+		configYAML := `
+usage_estimates:
+  lambda_monthly_requests: 1000000
+  resources:
+    aws_lambda_function.api:
+      lambda_monthly_requests: 50000000
+      lambda_avg_duration_ms: 120
+    "module.workers.*":
+      lambda_monthly_requests: 30
+`
+		tmpfile, err := os.CreateTemp("", "config-*.yml")
+		assert.NoError(t, err)
+		defer os.Remove(tmpfile.Name())
+
+		_, err = tmpfile.WriteString(configYAML)
+		assert.NoError(t, err)
+		tmpfile.Close()
+
+		config, err := LoadConfig(tmpfile.Name())
+		assert.NoError(t, err)
+		assert.Equal(t, 1000000, config.UsageEstimates.LambdaMonthlyRequests)
+		assert.Equal(t, 50000000, config.UsageEstimates.Resources["aws_lambda_function.api"].LambdaMonthlyRequests)
+		assert.Equal(t, 120, config.UsageEstimates.Resources["aws_lambda_function.api"].LambdaAvgDurationMS)
+		assert.Equal(t, 30, config.UsageEstimates.Resources["module.workers.*"].LambdaMonthlyRequests)
+	})
+
+	t.Run("loads pricing models", func(t *testing.T) {
+		configYAML := `
+pricing_models:
+  aws_instance:
+    term: reserved
+    lease_contract_length: 3yr
+    purchase_option: Partial Upfront
+  aws_db_instance:
+    term: on_demand
+  aws_autoscaling_group:
+    term: savings_plan
+    savings_plan_type: ec2_instance
+`
+		tmpfile, err := os.CreateTemp("", "config-*.yml")
+		assert.NoError(t, err)
+		defer os.Remove(tmpfile.Name())
+
+		_, err = tmpfile.WriteString(configYAML)
+		assert.NoError(t, err)
+		tmpfile.Close()
+
+		config, err := LoadConfig(tmpfile.Name())
+		assert.NoError(t, err)
+		assert.Equal(t, estimator.PricingModel{Term: "reserved", LeaseContractLength: "3yr", PurchaseOption: "Partial Upfront"}, config.PricingModels["aws_instance"])
+		assert.Equal(t, estimator.PricingModel{Term: "on_demand"}, config.PricingModels["aws_db_instance"])
+		assert.Equal(t, estimator.PricingModel{Term: "savings_plan", SavingsPlanType: "ec2_instance"}, config.PricingModels["aws_autoscaling_group"])
+	})
+
+	t.Run("loads a discount policy", func(t *testing.T) {
+		configYAML := `
+discounts:
+  percent: 5
+  services:
+    AmazonRDS: 15
+  overrides:
+    - sku: ABCDEFGHIJKLMNOP
+      unit_price: 0.05
+`
+		tmpfile, err := os.CreateTemp("", "config-*.yml")
+		assert.NoError(t, err)
+		defer os.Remove(tmpfile.Name())
+
+		_, err = tmpfile.WriteString(configYAML)
+		assert.NoError(t, err)
+		tmpfile.Close()
+
+		config, err := LoadConfig(tmpfile.Name())
+		assert.NoError(t, err)
+		assert.Equal(t, &estimator.DiscountPolicy{
+			Percent:   5,
+			Services:  map[string]float64{"AmazonRDS": 15},
+			Overrides: []estimator.PriceOverride{{SKU: "ABCDEFGHIJKLMNOP", UnitPrice: 0.05}},
+		}, config.Discounts)
+	})
+
 	t.Run("returns error for non-existent file", func(t *testing.T) {
 		_, err := LoadConfig("non-existent-file.yml")
 		assert.Error(t, err)
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS aws_offers (
+    offer_code TEXT PRIMARY KEY,
+    version TEXT NOT NULL DEFAULT '',
+    publication_date TIMESTAMPTZ NOT NULL,
+    last_updated TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
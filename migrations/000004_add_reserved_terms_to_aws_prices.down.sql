@@ -0,0 +1 @@
+ALTER TABLE aws_prices DROP COLUMN IF EXISTS reserved_terms_json;
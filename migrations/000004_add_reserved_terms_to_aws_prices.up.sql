@@ -0,0 +1 @@
+ALTER TABLE aws_prices ADD COLUMN IF NOT EXISTS reserved_terms_json JSONB;
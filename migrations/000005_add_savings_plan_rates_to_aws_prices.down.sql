@@ -0,0 +1 @@
+ALTER TABLE aws_prices DROP COLUMN IF EXISTS savings_plan_rates_json;
@@ -0,0 +1 @@
+ALTER TABLE aws_prices ADD COLUMN IF NOT EXISTS savings_plan_rates_json JSONB;
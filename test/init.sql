@@ -2,5 +2,14 @@ CREATE TABLE aws_prices (
     sku TEXT PRIMARY KEY,
     product_json JSONB,
     terms_json JSONB,
+    reserved_terms_json JSONB,
+    savings_plan_rates_json JSONB,
+    last_updated TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE aws_offers (
+    offer_code TEXT PRIMARY KEY,
+    version TEXT NOT NULL DEFAULT '',
+    publication_date TIMESTAMPTZ NOT NULL,
     last_updated TIMESTAMPTZ NOT NULL
 );